@@ -0,0 +1,387 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Op is the write operation.
+type Op string
+
+const (
+	// OpCreate creates items.
+	OpCreate Op = "create"
+
+	// OpUpdate updates items, which must already exist.
+	OpUpdate Op = "update"
+
+	// OpUpsert creates items, or updates them if they already exist.
+	OpUpsert Op = "upsert"
+)
+
+// Document is an item to write, with its ID.
+type Document struct {
+	// ID of the item.
+	ID string `json:"id"`
+
+	// Item to write.
+	Item any `json:"item"`
+}
+
+// BulkWriter is implemented by storages writing many items in a single call.
+type BulkWriter interface {
+	// BulkWrite writes `docs` to `target`. It returns, per document, in
+	// order, the error writing it, if any. A non-nil error fails the whole
+	// chunk.
+	BulkWrite(ctx context.Context, op Op, target string, docs []Document) ([]error, error)
+}
+
+// WriteFunc writes a single document.
+type WriteFunc func(ctx context.Context, doc Document) error
+
+// Policy defines how items are grouped into chunks.
+type Policy struct {
+	// MaxItems is the maximum number of items per chunk. If not set, chunks
+	// are only limited in size.
+	MaxItems int `json:"maxItems" validate:"gte=0"`
+
+	// MaxBytes is the maximum size, JSON encoded, of the items of a chunk.
+	// An item larger than it gets a chunk of its own. If not set, chunks are
+	// only limited in count.
+	MaxBytes int `json:"maxBytes" validate:"gte=0"`
+
+	// Linger is how long a `Collector` waits for a chunk to fill up before
+	// flushing it anyway.
+	Linger time.Duration `json:"linger" validate:"gte=0"`
+
+	// Concurrency is the maximum number of items of a chunk written at once,
+	// to storages not implementing `BulkWriter`. Defaults to the number of
+	// CPUs.
+	Concurrency int `json:"concurrency" validate:"gte=0"`
+}
+
+// Func allows to specify policy's options.
+type Func func(p *Policy) *Policy
+
+// ItemError is the error writing an item.
+type ItemError struct {
+	// Index of the item, in the written items.
+	Index int `json:"index"`
+
+	// ID of the item.
+	ID string `json:"id"`
+
+	// Err is the write error.
+	Err error `json:"-"`
+}
+
+// Error lists the items which failed to be written.
+type Error struct {
+	// Failed items, by index.
+	Failed []*ItemError `json:"failed"`
+
+	// Total number of items written.
+	Total int `json:"total"`
+}
+
+//////
+// Built-in options.
+//////
+
+// WithMaxItems sets the maximum number of items per chunk.
+func WithMaxItems(n int) Func {
+	return func(p *Policy) *Policy {
+		p.MaxItems = n
+
+		return p
+	}
+}
+
+// WithMaxBytes sets the maximum size, JSON encoded, of the items of a chunk.
+func WithMaxBytes(n int) Func {
+	return func(p *Policy) *Policy {
+		p.MaxBytes = n
+
+		return p
+	}
+}
+
+// WithConcurrency sets the maximum number of items of a chunk written at
+// once, to storages not implementing `BulkWriter`.
+func WithConcurrency(n int) Func {
+	return func(p *Policy) *Policy {
+		p.Concurrency = n
+
+		return p
+	}
+}
+
+// WithLinger sets how long a `Collector` waits for a chunk to fill up.
+func WithLinger(linger time.Duration) Func {
+	return func(p *Policy) *Policy {
+		p.Linger = linger
+
+		return p
+	}
+}
+
+//////
+// Methods.
+//////
+
+// Error implements the `error` interface.
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d (%s): %v", e.Index, e.ID, e.Err)
+}
+
+// Unwrap returns the write error.
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// Error implements the `error` interface.
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+
+	for _, f := range e.Failed {
+		msgs = append(msgs, f.Error())
+	}
+
+	return fmt.Sprintf("%d of %d items failed to be written: %s", len(e.Failed), e.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the item errors.
+func (e *Error) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+
+	for _, f := range e.Failed {
+		errs = append(errs, f)
+	}
+
+	return errs
+}
+
+// Indexes returns the indexes of the failed items.
+func (e *Error) Indexes() []int {
+	indexes := make([]int, 0, len(e.Failed))
+
+	for _, f := range e.Failed {
+		indexes = append(indexes, f.Index)
+	}
+
+	return indexes
+}
+
+// Chunks groups `docs` into chunks, according to the policy. It returns the
+// bounds, `[start, end)`, of every chunk.
+func (p *Policy) Chunks(docs []Document) [][2]int {
+	var (
+		chunks [][2]int
+		start  int
+		bytes  int
+	)
+
+	for i, doc := range docs {
+		size := 0
+
+		if p.MaxBytes > 0 {
+			size = Size(doc.Item)
+		}
+
+		full := (p.MaxItems > 0 && i-start >= p.MaxItems) ||
+			(p.MaxBytes > 0 && i > start && bytes+size > p.MaxBytes)
+
+		if full {
+			chunks = append(chunks, [2]int{start, i})
+
+			start = i
+			bytes = 0
+		}
+
+		bytes += size
+	}
+
+	if start < len(docs) {
+		chunks = append(chunks, [2]int{start, len(docs)})
+	}
+
+	return chunks
+}
+
+//////
+// Helpers.
+//////
+
+// workers returns the effective number of items written at once.
+func (p *Policy) workers() int {
+	if p.Concurrency > 0 {
+		return p.Concurrency
+	}
+
+	return runtime.NumCPU()
+}
+
+// writeChunk writes a chunk, in bulk if `s` supports it, else one document at
+// a time, up to `workers` at once. It returns, per document, the error
+// writing it.
+func writeChunk(
+	ctx context.Context,
+	s storage.IStorage,
+	op Op,
+	target string,
+	docs []Document,
+	workers int,
+	write WriteFunc,
+) []error {
+	errs := make([]error, len(docs))
+
+	if bulk, ok := s.(BulkWriter); ok {
+		itemErrs, err := bulk.BulkWrite(ctx, op, target, docs)
+
+		for i := range errs {
+			switch {
+			case err != nil:
+				errs[i] = err
+			case i < len(itemErrs):
+				errs[i] = itemErrs[i]
+			}
+		}
+
+		return errs
+	}
+
+	var wg sync.WaitGroup
+
+	// NOTE: A chunk limited only in size can hold many items.
+	sem := make(chan struct{}, workers)
+
+	for i, doc := range docs {
+		sem <- struct{}{}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() { <-sem }()
+			defer wg.Done()
+
+			errs[i] = write(ctx, doc)
+		}()
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+//////
+// Exported functions.
+//////
+
+// Size returns the size of `v`, JSON encoded. Values which can't be encoded
+// have a size of 0: writing them reports the error.
+func Size(v any) int {
+	b, err := shared.Marshal(v)
+	if err != nil {
+		return 0
+	}
+
+	return len(b)
+}
+
+// Write writes `docs` to `target`, chunk by chunk: in bulk if `s` implements
+// `BulkWriter`, else one document at a time, through `write`. It returns an
+// `*Error` listing the failed documents, if any. If `ctx` is done between
+// chunks, the documents not written yet fail with the context's error.
+func Write(
+	ctx context.Context,
+	s storage.IStorage,
+	policy *Policy,
+	op Op,
+	target string,
+	docs []Document,
+	write WriteFunc,
+) error {
+	if err := validation.Validate(policy); err != nil {
+		return err
+	}
+
+	batchErr := &Error{Total: len(docs)}
+
+	for _, chunk := range policy.Chunks(docs) {
+		if err := ctx.Err(); err != nil {
+			for i := chunk[0]; i < len(docs); i++ {
+				batchErr.Failed = append(batchErr.Failed, &ItemError{
+					Index: i,
+					ID:    docs[i].ID,
+					Err:   err,
+				})
+			}
+
+			break
+		}
+
+		errs := writeChunk(ctx, s, op, target, docs[chunk[0]:chunk[1]], policy.workers(), write)
+
+		for i, err := range errs {
+			if err != nil {
+				batchErr.Failed = append(batchErr.Failed, &ItemError{
+					Index: chunk[0] + i,
+					ID:    docs[chunk[0]+i].ID,
+					Err:   err,
+				})
+			}
+		}
+	}
+
+	if len(batchErr.Failed) > 0 {
+		return batchErr
+	}
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// New returns a new policy.
+func New(opts ...Func) (*Policy, error) {
+	p := &Policy{}
+
+	for _, opt := range opts {
+		p = opt(p)
+	}
+
+	if err := validation.Validate(p); err != nil {
+		return nil, err
+	}
+
+	if p.MaxItems == 0 && p.MaxBytes == 0 {
+		return nil, customerror.NewRequiredError("max items, or max bytes")
+	}
+
+	return p, nil
+}
+
+// Must returns a new policy, or panics if an error occurs.
+func Must(opts ...Func) *Policy {
+	p, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
@@ -0,0 +1,239 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/dal/v2/storage"
+)
+
+// perItemStorage is a stub storage without bulk support.
+type perItemStorage struct {
+	storage.IStorage
+}
+
+// bulkStorage is a stub storage with bulk support, recording its chunks,
+// and failing items whose ID starts with "bad".
+type bulkStorage struct {
+	storage.IStorage
+
+	mu       sync.Mutex
+	chunks   [][]string
+	failWith error
+}
+
+func (s *bulkStorage) BulkWrite(ctx context.Context, op Op, target string, docs []Document) ([]error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failWith != nil {
+		return nil, s.failWith
+	}
+
+	ids := make([]string, 0, len(docs))
+	errs := make([]error, len(docs))
+
+	for i, doc := range docs {
+		ids = append(ids, doc.ID)
+
+		if strings.HasPrefix(doc.ID, "bad") {
+			errs[i] = errors.New("rejected")
+		}
+	}
+
+	s.chunks = append(s.chunks, ids)
+
+	return errs, nil
+}
+
+func documents(ids ...string) []Document {
+	docs := make([]Document, 0, len(ids))
+
+	for _, id := range ids {
+		docs = append(docs, Document{ID: id, Item: id})
+	}
+
+	return docs
+}
+
+// Happy path: chunks by count, by size, and by both.
+func TestPolicy_Chunks(t *testing.T) {
+	// Every item is 5 bytes, JSON encoded: `"abc"`.
+	docs := documents("abc", "abc", "abc", "abc", "abc")
+
+	tests := []struct {
+		name   string
+		policy *Policy
+		want   [][2]int
+	}{
+		{name: "count", policy: Must(WithMaxItems(2)), want: [][2]int{{0, 2}, {2, 4}, {4, 5}}},
+		{name: "bytes", policy: Must(WithMaxBytes(15)), want: [][2]int{{0, 3}, {3, 5}}},
+		{name: "both", policy: Must(WithMaxItems(2), WithMaxBytes(15)), want: [][2]int{{0, 2}, {2, 4}, {4, 5}}},
+		{name: "single", policy: Must(WithMaxItems(10)), want: [][2]int{{0, 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Chunks(docs))
+		})
+	}
+
+	// Edge case: oversized items get a chunk of their own.
+	docs = []Document{{Item: "a"}, {Item: strings.Repeat("x", 100)}, {Item: "b"}}
+
+	assert.Equal(t, [][2]int{{0, 1}, {1, 2}, {2, 3}}, Must(WithMaxBytes(10)).Chunks(docs))
+
+	// Edge case: no documents, no chunks.
+	assert.Empty(t, Must(WithMaxItems(1)).Chunks(nil))
+}
+
+// Happy path: bulk writes, chunk by chunk, reporting exactly which items
+// failed.
+func TestWrite_bulk(t *testing.T) {
+	s := &bulkStorage{}
+
+	err := Write(context.Background(), s, Must(WithMaxItems(2)), OpCreate, "t", documents("a", "bad-b", "c", "d", "bad-e"), nil)
+	require.Error(t, err)
+
+	var batchErr *Error
+
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, []int{1, 4}, batchErr.Indexes())
+	assert.Equal(t, 5, batchErr.Total)
+	assert.Equal(t, "bad-b", batchErr.Failed[0].ID)
+	assert.Contains(t, err.Error(), "2 of 5 items failed to be written")
+	assert.Contains(t, err.Error(), "item 4 (bad-e): rejected")
+
+	assert.Equal(t, [][]string{{"a", "bad-b"}, {"c", "d"}, {"bad-e"}}, s.chunks)
+
+	// A failing bulk call fails its whole chunk.
+	s.failWith = errors.New("unavailable")
+
+	err = Write(context.Background(), s, Must(WithMaxItems(2)), OpCreate, "t", documents("a", "b", "c"), nil)
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, []int{0, 1, 2}, batchErr.Indexes())
+	assert.ErrorContains(t, batchErr.Unwrap()[0], "unavailable")
+}
+
+// Happy path: without bulk support, items are written one at a time.
+func TestWrite_perItem(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		written []string
+	)
+
+	err := Write(context.Background(), &perItemStorage{}, Must(WithMaxItems(2)), OpUpsert, "t", documents("a", "bad-b", "c"), func(ctx context.Context, doc Document) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		written = append(written, doc.ID)
+
+		if doc.ID == "bad-b" {
+			return errors.New("rejected")
+		}
+
+		return nil
+	})
+
+	var batchErr *Error
+
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, []int{1}, batchErr.Indexes())
+	assert.ElementsMatch(t, []string{"a", "bad-b", "c"}, written)
+
+	// Happy path: no failures, no error.
+	require.NoError(t, Write(context.Background(), &perItemStorage{}, Must(WithMaxItems(2)), OpCreate, "t", documents("a"), func(ctx context.Context, doc Document) error {
+		return nil
+	}))
+}
+
+// Edge case: a large chunk, written one item at a time, writes at most
+// `Concurrency` items at once.
+func TestWrite_perItemConcurrency(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+
+	ids := make([]string, 0, 50)
+
+	for i := range 50 {
+		ids = append(ids, fmt.Sprintf("id-%d", i))
+	}
+
+	err := Write(context.Background(), &perItemStorage{}, Must(WithMaxBytes(1<<20), WithConcurrency(3)), OpCreate, "t", documents(ids...), func(ctx context.Context, doc Document) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	assert.Positive(t, maxInFlight.Load())
+}
+
+// Bad path: invalid policies, and done contexts.
+func TestWrite_errors(t *testing.T) {
+	_, err := New()
+	require.Error(t, err)
+
+	_, err = New(WithMaxItems(-1))
+	require.Error(t, err)
+
+	err = Write(context.Background(), &bulkStorage{}, &Policy{MaxBytes: -1}, OpCreate, "t", documents("a"), nil)
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := &bulkStorage{}
+
+	err = Write(ctx, s, Must(WithMaxItems(1)), OpCreate, "t", documents("a"), nil)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, s.chunks)
+}
+
+// Edge case: cancelled mid-batch, the failures so far, and every document not
+// written yet, are reported.
+func TestWrite_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := Write(ctx, &perItemStorage{}, Must(WithMaxItems(2)), OpCreate, "t", documents("a", "bad-b", "c", "d", "e"), func(ctx context.Context, doc Document) error {
+		// Cancelled while writing the first chunk.
+		cancel()
+
+		if doc.ID == "bad-b" {
+			return errors.New("rejected")
+		}
+
+		return nil
+	})
+
+	var batchErr *Error
+
+	require.ErrorAs(t, err, &batchErr)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []int{1, 2, 3, 4}, batchErr.Indexes())
+	assert.Equal(t, 5, batchErr.Total)
+	assert.ErrorContains(t, batchErr.Failed[0], "rejected")
+
+	for _, f := range batchErr.Failed[1:] {
+		assert.ErrorIs(t, f, context.Canceled)
+	}
+}
@@ -0,0 +1,177 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// submission is a document waiting to be written.
+type submission struct {
+	ctx  context.Context
+	doc  Document
+	size int
+	done chan error
+}
+
+// Collector batches documents submitted concurrently, one at a time.
+type Collector struct {
+	s      storage.IStorage
+	policy *Policy
+	op     Op
+	target string
+	write  WriteFunc
+
+	mu      sync.Mutex
+	pending []*submission
+	bytes   int
+	timer   *time.Timer
+}
+
+//////
+// Methods.
+//////
+
+// take returns the pending submissions, if any, resetting the chunk.
+//
+// NOTE: Must be called with the lock held.
+func (c *Collector) take() []*submission {
+	pending := c.pending
+
+	c.pending = nil
+	c.bytes = 0
+
+	if c.timer != nil {
+		c.timer.Stop()
+
+		c.timer = nil
+	}
+
+	return pending
+}
+
+// flush writes `pending`, as a single chunk, and notifies every submitter.
+func (c *Collector) flush(pending []*submission) {
+	if len(pending) == 0 {
+		return
+	}
+
+	docs := make([]Document, 0, len(pending))
+
+	for _, p := range pending {
+		docs = append(docs, p.doc)
+	}
+
+	// NOTE: The chunk outlives any single submitter: it's written with the
+	// first submitter's context values (e.g., trace), without its
+	// cancellation.
+	errs := writeChunk(context.WithoutCancel(pending[0].ctx), c.s, c.op, c.target, docs, c.policy.workers(), c.write)
+
+	for i, p := range pending {
+		p.done <- errs[i]
+	}
+}
+
+// Submit submits `doc`, and blocks until it's written, returning the error
+// writing it, if any. If `ctx` is done first, its error is returned, but the
+// document may still be written.
+func (c *Collector) Submit(ctx context.Context, doc Document) error {
+	s := &submission{
+		ctx:  ctx,
+		doc:  doc,
+		done: make(chan error, 1),
+	}
+
+	if c.policy.MaxBytes > 0 {
+		s.size = Size(doc.Item)
+	}
+
+	c.mu.Lock()
+
+	// Flushes the pending chunk first, if the document doesn't fit in it.
+	var full []*submission
+
+	if len(c.pending) > 0 && c.policy.MaxBytes > 0 && c.bytes+s.size > c.policy.MaxBytes {
+		full = c.take()
+	}
+
+	c.pending = append(c.pending, s)
+	c.bytes += s.size
+
+	if (c.policy.MaxItems > 0 && len(c.pending) >= c.policy.MaxItems) ||
+		(c.policy.MaxBytes > 0 && c.bytes >= c.policy.MaxBytes) {
+		go c.flush(c.take())
+	} else if c.timer == nil {
+		c.timer = time.AfterFunc(c.policy.Linger, func() {
+			c.mu.Lock()
+
+			var pending []*submission
+
+			// NOTE: The chunk may have been flushed, and another one started,
+			// since the timer fired.
+			if len(c.pending) > 0 && c.pending[0] == s {
+				pending = c.take()
+			}
+
+			c.mu.Unlock()
+
+			c.flush(pending)
+		})
+	}
+
+	c.mu.Unlock()
+
+	if full != nil {
+		go c.flush(full)
+	}
+
+	select {
+	case err := <-s.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//////
+// Factory.
+//////
+
+// NewCollector returns a new collector, writing to `target` of `s`, in bulk
+// if it implements `BulkWriter`, else one document at a time, through
+// `write`. The policy's `Linger` is required.
+func NewCollector(
+	s storage.IStorage,
+	policy *Policy,
+	op Op,
+	target string,
+	write WriteFunc,
+) (*Collector, error) {
+	if policy == nil {
+		return nil, customerror.NewRequiredError("policy")
+	}
+
+	if err := validation.Validate(policy); err != nil {
+		return nil, err
+	}
+
+	if policy.Linger <= 0 {
+		return nil, customerror.NewRequiredError("policy linger")
+	}
+
+	return &Collector{
+		s:      s,
+		policy: policy,
+		op:     op,
+		target: target,
+		write:  write,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// submitAll submits `ids` concurrently, returning each one's error.
+func submitAll(ctx context.Context, c *Collector, ids ...string) map[string]error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = map[string]error{}
+	)
+
+	for _, id := range ids {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := c.Submit(ctx, Document{ID: id, Item: id})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			errs[id] = err
+		}()
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// Happy path: concurrent submissions are written in full chunks, and the
+// last, partial one after lingering. Each submitter gets its own error.
+func TestCollector_Submit(t *testing.T) {
+	s := &bulkStorage{}
+
+	c, err := NewCollector(s, Must(WithMaxItems(2), WithLinger(20*time.Millisecond)), OpCreate, "t", nil)
+	require.NoError(t, err)
+
+	errs := submitAll(context.Background(), c, "a", "b", "bad-c", "d", "e")
+
+	assert.NoError(t, errs["a"])
+	assert.NoError(t, errs["e"])
+	assert.ErrorContains(t, errs["bad-c"], "rejected")
+
+	sizes := make([]int, 0, len(s.chunks))
+
+	for _, chunk := range s.chunks {
+		sizes = append(sizes, len(chunk))
+	}
+
+	assert.ElementsMatch(t, []int{2, 2, 1}, sizes)
+}
+
+// Happy path: chunks are limited in size, too.
+func TestCollector_maxBytes(t *testing.T) {
+	s := &bulkStorage{}
+
+	// Every item is 5 bytes, JSON encoded: `"abc"`.
+	c, err := NewCollector(s, Must(WithMaxBytes(10), WithLinger(20*time.Millisecond)), OpCreate, "t", nil)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, 5)
+
+	for i := range 5 {
+		ids = append(ids, fmt.Sprintf("ab%d", i))
+	}
+
+	for _, err := range submitAll(context.Background(), c, ids...) {
+		require.NoError(t, err)
+	}
+
+	for _, chunk := range s.chunks {
+		assert.LessOrEqual(t, len(chunk), 2)
+	}
+}
+
+// Happy path: without bulk support, documents are written one at a time.
+func TestCollector_perItem(t *testing.T) {
+	c, err := NewCollector(&perItemStorage{}, Must(WithMaxItems(10), WithLinger(time.Millisecond)), OpCreate, "t", func(ctx context.Context, doc Document) error {
+		if doc.ID == "bad" {
+			return errors.New("rejected")
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	errs := submitAll(context.Background(), c, "good", "bad")
+	assert.NoError(t, errs["good"])
+	assert.ErrorContains(t, errs["bad"], "rejected")
+}
+
+// Edge case: a submitter whose context is done stops waiting.
+func TestCollector_contextDone(t *testing.T) {
+	c, err := NewCollector(&bulkStorage{}, Must(WithMaxItems(10), WithLinger(time.Hour)), OpCreate, "t", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, c.Submit(ctx, Document{ID: "a"}), context.DeadlineExceeded)
+}
+
+// Bad path: invalid policies are rejected.
+func TestNewCollector_invalid(t *testing.T) {
+	_, err := NewCollector(&bulkStorage{}, nil, OpCreate, "t", nil)
+	require.Error(t, err)
+
+	_, err = NewCollector(&bulkStorage{}, Must(WithMaxItems(1)), OpCreate, "t", nil)
+	require.Error(t, err, "linger is required")
+
+	_, err = NewCollector(&bulkStorage{}, &Policy{MaxItems: -1, Linger: time.Second}, OpCreate, "t", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,13 @@
+// Package batch provides batched writes to storages, for the storage
+// processor and converter.
+//
+// A `Policy` groups items into chunks, by count (`MaxItems`), and by JSON
+// encoded size (`MaxBytes`). Each chunk is written in a single call when the
+// storage implements `BulkWriter`, else one item at a time, up to
+// `Concurrency` items at once. Either way, failures are reported per item:
+// `*Error` lists exactly which items failed, and why.
+//
+// A `Collector` batches writes submitted concurrently, one item at a time,
+// e.g., by a converter: it flushes once a chunk is full, or once `Linger`
+// elapsed since its first item.
+package batch
@@ -0,0 +1,128 @@
+package checkpoint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/task"
+)
+
+// stores returns one of each store implementation.
+func stores(t *testing.T) map[string]IStore[int, string] {
+	t.Helper()
+
+	f, err := NewFile[int, string](t.TempDir())
+	require.NoError(t, err)
+
+	return map[string]IStore[int, string]{
+		"memory": NewMemory[int, string](),
+		"file":   f,
+	}
+}
+
+// Happy path: checkpoints round-trip, sorted by index, isolated per run, and
+// are gone once deleted.
+func TestStores_saveLoadDelete(t *testing.T) {
+	ctx := context.Background()
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			// Empty run: no checkpoints, no error.
+			got, err := store.Load(ctx, "p", "run-1")
+			require.NoError(t, err)
+			assert.Empty(t, got)
+
+			second := task.MustNew[int, string]([]int{3, 4})
+			second.ConvertedData = []string{"3", "4"}
+
+			first := task.MustNew[int, string]([]int{1, 2})
+			first.ConvertedData = []string{"1", "2"}
+
+			// Saved out of order on purpose.
+			require.NoError(t, store.Save(ctx, "p", "run-1", Checkpoint[int, string]{Index: 1, Stage: "b", Task: second}))
+			require.NoError(t, store.Save(ctx, "p", "run-1", Checkpoint[int, string]{Index: 0, Stage: "a", Task: first}))
+			require.NoError(t, store.Save(ctx, "p", "run-2", Checkpoint[int, string]{Index: 0, Stage: "a", Task: first}))
+
+			got, err = store.Load(ctx, "p", "run-1")
+			require.NoError(t, err)
+			require.Len(t, got, 2)
+
+			assert.Equal(t, "a", got[0].Stage)
+			assert.Equal(t, first.ID, got[0].Task.ID)
+			assert.Equal(t, []int{1, 2}, got[0].Task.ProcessingData)
+			assert.Equal(t, []string{"1", "2"}, got[0].Task.ConvertedData)
+			assert.Equal(t, "b", got[1].Stage)
+			assert.Equal(t, []int{3, 4}, got[1].Task.ProcessingData)
+
+			require.NoError(t, store.Delete(ctx, "p", "run-1"))
+
+			got, err = store.Load(ctx, "p", "run-1")
+			require.NoError(t, err)
+			assert.Empty(t, got)
+
+			// Other runs are untouched.
+			got, err = store.Load(ctx, "p", "run-2")
+			require.NoError(t, err)
+			assert.Len(t, got, 1)
+		})
+	}
+}
+
+// Edge case: names can't escape the store's directory.
+func TestFile_keysAreSafePathSegments(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	f, err := NewFile[int, string](filepath.Join(dir, "store"))
+	require.NoError(t, err)
+
+	tsk := task.MustNew[int, string]([]int{1})
+
+	require.NoError(t, f.Save(ctx, "../../escape", "a/b", Checkpoint[int, string]{Stage: "a", Task: tsk}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "nothing may be written outside the store's directory")
+
+	err = f.Save(ctx, "..", "run", Checkpoint[int, string]{Stage: "a", Task: tsk})
+	assert.Error(t, err)
+
+	_, err = f.Load(ctx, "p", "")
+	assert.Error(t, err)
+}
+
+// Bad path: a corrupted checkpoint fails to load; leftovers of interrupted
+// writes are ignored.
+func TestFile_corruptedAndLeftoverFiles(t *testing.T) {
+	ctx := context.Background()
+
+	f, err := NewFile[int, string](t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, f.Save(ctx, "p", "r", Checkpoint[int, string]{Stage: "a", Task: task.MustNew[int, string]([]int{1})}))
+
+	dir, err := f.runDir("p", "r")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "123.tmp"), []byte("{"), 0o600))
+
+	got, err := f.Load(ctx, "p", "r")
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1.json"), []byte("{"), 0o600))
+
+	_, err = f.Load(ctx, "p", "r")
+	assert.Error(t, err)
+}
+
+// Bad path: a file store needs a directory.
+func TestNewFile_emptyDir(t *testing.T) {
+	f, err := NewFile[int, string]("")
+	assert.Nil(t, f)
+	assert.Error(t, err)
+}
@@ -0,0 +1,9 @@
+// Package checkpoint provides stores that persist the output of each
+// completed stage of a sequential pipeline, keyed by pipeline name and run
+// ID, so a failed run can resume from the last completed stage instead of
+// starting from scratch.
+//
+// Two implementations are provided: `Memory`, for tests and single-process
+// retries, and `File`, which survives restarts. Implement `IStore` to use
+// any other backend.
+package checkpoint
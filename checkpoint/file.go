@@ -0,0 +1,200 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Extension of checkpoint files.
+const Extension = ".json"
+
+// File is a file-backed checkpoint store. Each checkpoint is a JSON file at
+// `<Dir>/<pipeline name>/<run ID>/<stage index>.json`, so the processed and
+// converted data must be JSON-serializable.
+type File[ProcessedData, ConvertedOut any] struct {
+	// Dir is the root directory of the store.
+	Dir string `json:"dir" validate:"required"`
+}
+
+//////
+// Helpers.
+//////
+
+// pathSegment turns a name into a single, safe, path segment.
+func pathSegment(name string) (string, error) {
+	segment := url.PathEscape(name)
+
+	if segment == "" || segment == "." || segment == ".." {
+		return "", customerror.NewInvalidError(fmt.Sprintf("checkpoint key %q", name))
+	}
+
+	return segment, nil
+}
+
+// runDir returns the directory holding the checkpoints of a run.
+func (f *File[ProcessedData, ConvertedOut]) runDir(pipelineName, runID string) (string, error) {
+	p, err := pathSegment(pipelineName)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := pathSegment(runID)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(f.Dir, p, r), nil
+}
+
+//////
+// Methods.
+//////
+
+// Save persists the checkpoint of a completed stage. The file is written
+// atomically: a crash mid-write never leaves a truncated checkpoint behind.
+func (f *File[ProcessedData, ConvertedOut]) Save(
+	_ context.Context,
+	pipelineName, runID string,
+	c Checkpoint[ProcessedData, ConvertedOut],
+) error {
+	dir, err := f.runDir(pipelineName, runID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return customerror.NewFailedToError("create checkpoint directory", customerror.WithError(err))
+	}
+
+	data, err := shared.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return customerror.NewFailedToError("create checkpoint file", customerror.WithError(err))
+	}
+
+	// No-op once renamed.
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return customerror.NewFailedToError("write checkpoint file", customerror.WithError(err))
+	}
+
+	if err := tmp.Close(); err != nil {
+		return customerror.NewFailedToError("write checkpoint file", customerror.WithError(err))
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, strconv.Itoa(c.Index)+Extension)); err != nil {
+		return customerror.NewFailedToError("write checkpoint file", customerror.WithError(err))
+	}
+
+	return nil
+}
+
+// Load returns the checkpoints of a run, sorted by stage index.
+func (f *File[ProcessedData, ConvertedOut]) Load(
+	_ context.Context,
+	pipelineName, runID string,
+) ([]Checkpoint[ProcessedData, ConvertedOut], error) {
+	dir, err := f.runDir(pipelineName, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Checkpoint[ProcessedData, ConvertedOut]{}, nil
+		}
+
+		return nil, customerror.NewFailedToError("read checkpoint directory", customerror.WithError(err))
+	}
+
+	checkpoints := make([]Checkpoint[ProcessedData, ConvertedOut], 0, len(entries))
+
+	for _, entry := range entries {
+		// Skips leftovers of interrupted writes, and anything else.
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), Extension) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, customerror.NewFailedToError("read checkpoint file", customerror.WithError(err))
+		}
+
+		var c Checkpoint[ProcessedData, ConvertedOut]
+
+		if err := shared.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+
+		checkpoints = append(checkpoints, c)
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Index < checkpoints[j].Index
+	})
+
+	return checkpoints, nil
+}
+
+// Delete removes the checkpoints of a run.
+func (f *File[ProcessedData, ConvertedOut]) Delete(_ context.Context, pipelineName, runID string) error {
+	dir, err := f.runDir(pipelineName, runID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return customerror.NewFailedToError("delete checkpoints", customerror.WithError(err))
+	}
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// NewFile returns a new file-backed checkpoint store rooted at `dir`, which
+// is created if needed.
+func NewFile[ProcessedData, ConvertedOut any](dir string) (*File[ProcessedData, ConvertedOut], error) {
+	// Enforces interface implementation.
+	var _ IStore[ProcessedData, ConvertedOut] = (*File[ProcessedData, ConvertedOut])(nil)
+
+	f := &File[ProcessedData, ConvertedOut]{
+		Dir: dir,
+	}
+
+	// Validation.
+	if err := validation.Validate(f); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, customerror.NewFailedToError("create checkpoint directory", customerror.WithError(err))
+	}
+
+	return f, nil
+}
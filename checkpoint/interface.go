@@ -0,0 +1,37 @@
+package checkpoint
+
+import (
+	"context"
+
+	"github.com/thalesfsp/etler/v3/task"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Checkpoint is the output of a completed stage.
+type Checkpoint[ProcessedData, ConvertedOut any] struct {
+	// Index of the stage in the pipeline.
+	Index int `json:"index"`
+
+	// Stage is the name of the stage.
+	Stage string `json:"stage"`
+
+	// Task is the stage's output.
+	Task task.Task[ProcessedData, ConvertedOut] `json:"task"`
+}
+
+// IStore defines what a checkpoint store must do. Implementations must be
+// safe for concurrent use.
+type IStore[ProcessedData, ConvertedOut any] interface {
+	// Save persists the checkpoint of a completed stage.
+	Save(ctx context.Context, pipelineName, runID string, c Checkpoint[ProcessedData, ConvertedOut]) error
+
+	// Load returns the checkpoints of a run, sorted by stage index. A run
+	// without checkpoints isn't an error.
+	Load(ctx context.Context, pipelineName, runID string) ([]Checkpoint[ProcessedData, ConvertedOut], error)
+
+	// Delete removes the checkpoints of a run.
+	Delete(ctx context.Context, pipelineName, runID string) error
+}
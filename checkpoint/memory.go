@@ -0,0 +1,96 @@
+package checkpoint
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// runKey identifies a run.
+type runKey struct {
+	pipelineName string
+	runID        string
+}
+
+// Memory is an in-memory checkpoint store. Checkpoints don't survive the
+// process.
+type Memory[ProcessedData, ConvertedOut any] struct {
+	mu   sync.Mutex
+	runs map[runKey]map[int]Checkpoint[ProcessedData, ConvertedOut]
+}
+
+//////
+// Methods.
+//////
+
+// Save persists the checkpoint of a completed stage. Saving the same stage
+// again replaces it.
+func (m *Memory[ProcessedData, ConvertedOut]) Save(
+	_ context.Context,
+	pipelineName, runID string,
+	c Checkpoint[ProcessedData, ConvertedOut],
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := runKey{pipelineName, runID}
+
+	if m.runs[k] == nil {
+		m.runs[k] = make(map[int]Checkpoint[ProcessedData, ConvertedOut])
+	}
+
+	m.runs[k][c.Index] = c
+
+	return nil
+}
+
+// Load returns the checkpoints of a run, sorted by stage index.
+func (m *Memory[ProcessedData, ConvertedOut]) Load(
+	_ context.Context,
+	pipelineName, runID string,
+) ([]Checkpoint[ProcessedData, ConvertedOut], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	run := m.runs[runKey{pipelineName, runID}]
+
+	checkpoints := make([]Checkpoint[ProcessedData, ConvertedOut], 0, len(run))
+
+	for _, c := range run {
+		checkpoints = append(checkpoints, c)
+	}
+
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Index < checkpoints[j].Index
+	})
+
+	return checkpoints, nil
+}
+
+// Delete removes the checkpoints of a run.
+func (m *Memory[ProcessedData, ConvertedOut]) Delete(_ context.Context, pipelineName, runID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.runs, runKey{pipelineName, runID})
+
+	return nil
+}
+
+//////
+// Factory.
+//////
+
+// NewMemory returns a new, empty, in-memory checkpoint store.
+func NewMemory[ProcessedData, ConvertedOut any]() *Memory[ProcessedData, ConvertedOut] {
+	// Enforces interface implementation.
+	var _ IStore[ProcessedData, ConvertedOut] = (*Memory[ProcessedData, ConvertedOut])(nil)
+
+	return &Memory[ProcessedData, ConvertedOut]{
+		runs: make(map[runKey]map[int]Checkpoint[ProcessedData, ConvertedOut]),
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"io"
+	"maps"
+
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/converters/passthru"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/etler/v3/loaders/json"
+	"github.com/thalesfsp/etler/v3/loaders/ndjson"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/registry"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Record is the data the command's pipelines process: a decoded JSON object.
+type Record = map[string]any
+
+// fieldsOptions are the options of the `select`, and `drop` processors.
+type fieldsOptions struct {
+	Fields []string `json:"fields"`
+}
+
+// renameOptions are the options of the `rename` processor.
+type renameOptions struct {
+	Fields map[string]string `json:"fields"`
+}
+
+// setOptions are the options of the `set` processor.
+type setOptions struct {
+	Values map[string]any `json:"values"`
+}
+
+//////
+// Helpers.
+//////
+
+// noOptions rejects any option.
+func noOptions(options registry.Options) error {
+	return registry.Decode(options, &struct{}{})
+}
+
+// mapRecords returns a processor applying `fn` to a copy of every record.
+//
+// NOTE: Records are copied, as async processors share them.
+func mapRecords(name, description string, fn func(in, out Record)) (processor.IProcessor[Record], error) {
+	return processor.New(name, description, func(ctx context.Context, in []Record) ([]Record, error) {
+		out := make([]Record, 0, len(in))
+
+		for _, r := range in {
+			o := make(Record, len(r))
+
+			fn(r, o)
+
+			out = append(out, o)
+		}
+
+		return out, nil
+	})
+}
+
+//////
+// Factory.
+//////
+
+// newRegistry returns the registry of the command's built-in components.
+//
+// Loaders: `json`, and `ndjson`. Converters: `passthru`. Processors:
+// `select`, `drop`, `rename`, and `set`.
+func newRegistry() (*registry.Registry[Record, Record], error) {
+	r := registry.New[Record, Record]()
+
+	loaders := map[string]registry.LoaderFactory[Record]{
+		json.Name: func(name string, options registry.Options) (loader.ILoader[io.Reader, []Record], error) {
+			if err := noOptions(options); err != nil {
+				return nil, err
+			}
+
+			return json.New[Record]()
+		},
+		ndjson.Name: func(name string, options registry.Options) (loader.ILoader[io.Reader, []Record], error) {
+			if err := noOptions(options); err != nil {
+				return nil, err
+			}
+
+			return ndjson.New[Record]()
+		},
+	}
+
+	converters := map[string]registry.ConverterFactory[Record, Record]{
+		passthru.Name: func(name string, options registry.Options) (converter.IConverter[Record, Record], error) {
+			if err := noOptions(options); err != nil {
+				return nil, err
+			}
+
+			return passthru.New[Record]()
+		},
+	}
+
+	processors := map[string]registry.ProcessorFactory[Record]{
+		"select": func(name string, options registry.Options) (processor.IProcessor[Record], error) {
+			var o fieldsOptions
+
+			if err := registry.Decode(options, &o); err != nil {
+				return nil, err
+			}
+
+			return mapRecords(name, "keeps only the listed fields", func(in, out Record) {
+				for _, f := range o.Fields {
+					if v, ok := in[f]; ok {
+						out[f] = v
+					}
+				}
+			})
+		},
+		"drop": func(name string, options registry.Options) (processor.IProcessor[Record], error) {
+			var o fieldsOptions
+
+			if err := registry.Decode(options, &o); err != nil {
+				return nil, err
+			}
+
+			return mapRecords(name, "removes the listed fields", func(in, out Record) {
+				maps.Copy(out, in)
+
+				for _, f := range o.Fields {
+					delete(out, f)
+				}
+			})
+		},
+		"rename": func(name string, options registry.Options) (processor.IProcessor[Record], error) {
+			var o renameOptions
+
+			if err := registry.Decode(options, &o); err != nil {
+				return nil, err
+			}
+
+			return mapRecords(name, "renames fields", func(in, out Record) {
+				for k, v := range in {
+					if to, ok := o.Fields[k]; ok {
+						k = to
+					}
+
+					out[k] = v
+				}
+			})
+		},
+		"set": func(name string, options registry.Options) (processor.IProcessor[Record], error) {
+			var o setOptions
+
+			if err := registry.Decode(options, &o); err != nil {
+				return nil, err
+			}
+
+			return mapRecords(name, "sets fields to constant values", func(in, out Record) {
+				maps.Copy(out, in)
+				maps.Copy(out, o.Values)
+			})
+		},
+	}
+
+	for name, factory := range loaders {
+		if err := r.RegisterLoader(name, factory); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, factory := range converters {
+		if err := r.RegisterConverter(name, factory); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, factory := range processors {
+		if err := r.RegisterProcessor(name, factory); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/etler/v3/pipeline"
+	"github.com/thalesfsp/etler/v3/registry"
+)
+
+//////
+// Helpers.
+//////
+
+// parse parses the flags of a subcommand.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return &usageError{msg: err.Error()}
+	}
+
+	return nil
+}
+
+// load parses the definition at `path`, and validates it against the
+// built-in components.
+func load(path string) (*registry.Registry[Record, Record], *registry.Definition, error) {
+	r, err := newRegistry()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	def, err := registry.ParseFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.Validate(def); err != nil {
+		return nil, nil, err
+	}
+
+	return r, def, nil
+}
+
+// printMetrics prints `metrics`, sorted by name.
+func printMetrics(w io.Writer, metrics map[string]string) {
+	for _, k := range slices.Sorted(maps.Keys(metrics)) {
+		fmt.Fprintf(w, "  %s: %s\n", k, metrics[k])
+	}
+}
+
+// reportProgress prints the progress of `p` every `interval`, until `done`
+// is closed.
+func reportProgress(w io.Writer, p pipeline.IPipeline[Record, Record], interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			fmt.Fprintf(w, "progress: %s\n", p.GetProgressPercent().Value())
+		}
+	}
+}
+
+// stageIndex returns the index of the stage named `name`, or of the last
+// stage if not set.
+func stageIndex(def *registry.Definition, name string) (int, error) {
+	if name == "" {
+		return len(def.Stages) - 1, nil
+	}
+
+	for i, s := range def.Stages {
+		if s.Name == name {
+			return i, nil
+		}
+	}
+
+	return 0, &usageError{msg: fmt.Sprintf("unknown stage %q", name)}
+}
+
+// openInput opens the input at `path`, or stdin if `-`.
+func openInput(e *env, path string) (io.Reader, func() error, error) {
+	if path == "-" {
+		return e.stdin, func() error { return nil }, nil
+	}
+
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, f.Close, nil
+}
+
+// openOutput opens the output at `path`, or stdout if `-`.
+func openOutput(e *env, path string) (io.Writer, func() error, error) {
+	if path == "-" {
+		return e.stdout, func() error { return nil }, nil
+	}
+
+	f, err := os.Create(filepath.Clean(path))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, f.Close, nil
+}
+
+//////
+// Commands.
+//////
+
+// validateCmd validates a definition.
+func validateCmd(_ context.Context, e *env, args []string) error {
+	fs := newFlagSet("validate", e.stderr)
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	path, err := definitionArg(fs)
+	if err != nil {
+		return err
+	}
+
+	r, def, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: Building, without running, has the factories check the
+	// components' options.
+	if _, err := r.Build(def, func(context.Context) (io.Reader, error) {
+		return strings.NewReader(""), nil
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stdout, "%s: pipeline %q is valid, %d stage(s)\n", path, def.Name, len(def.Stages))
+
+	return nil
+}
+
+// graphCmd prints the topology of a definition.
+func graphCmd(_ context.Context, e *env, args []string) error {
+	fs := newFlagSet("graph", e.stderr)
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	path, err := definitionArg(fs)
+	if err != nil {
+		return err
+	}
+
+	def, err := registry.ParseFile(path)
+	if err != nil {
+		return err
+	}
+
+	mode := "sequential"
+
+	for _, s := range def.Stages {
+		if len(s.DependsOn) > 0 {
+			mode = "dag"
+		}
+	}
+
+	if mode != "dag" && def.ConcurrentStage {
+		mode = "concurrent"
+	}
+
+	w := bufio.NewWriter(e.stdout)
+
+	fmt.Fprintf(w, "pipeline %q (%s)\n", def.Name, mode)
+
+	if def.Loader != nil {
+		fmt.Fprintf(w, "  loader %q (%s)\n", registry.ComponentName(*def.Loader), def.Loader.Type)
+	}
+
+	for _, s := range def.Stages {
+		fmt.Fprintf(w, "  stage %q", s.Name)
+
+		if len(s.DependsOn) > 0 {
+			fmt.Fprintf(w, " <- %s", strings.Join(s.DependsOn, ", "))
+		}
+
+		fmt.Fprintln(w)
+
+		for i, p := range s.Processors {
+			var flags []string
+
+			if p.Async {
+				flags = append(flags, "async")
+			}
+
+			if p.Disabled {
+				flags = append(flags, "disabled")
+			}
+
+			fmt.Fprintf(w, "    %d. processor %q (%s)", i+1, registry.ComponentName(p.Component), p.Type)
+
+			if len(flags) > 0 {
+				fmt.Fprintf(w, " [%s]", strings.Join(flags, ", "))
+			}
+
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "    -> converter %q (%s)\n", registry.ComponentName(s.Converter), s.Converter.Type)
+	}
+
+	return w.Flush()
+}
+
+// runCmd runs a definition.
+func runCmd(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet("run", e.stderr)
+
+	input := fs.String("input", "-", "file to load, `-` for stdin")
+	output := fs.String("output", "-", "file to write the converted records to, as NDJSON, `-` for stdout")
+	stageName := fs.String("stage", "", "stage whose converted records are written, defaults to the last one")
+	progress := fs.Duration("progress", time.Second, "interval between progress reports, on stderr, 0 to disable")
+
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	path, err := definitionArg(fs)
+	if err != nil {
+		return err
+	}
+
+	r, def, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	if def.Loader == nil {
+		return fmt.Errorf("pipeline %q has no loader, to read the input", def.Name)
+	}
+
+	idx, err := stageIndex(def, *stageName)
+	if err != nil {
+		return err
+	}
+
+	in, closeIn, err := openInput(e, *input)
+	if err != nil {
+		return err
+	}
+
+	defer closeIn()
+
+	// NOTE: The pipeline runs once, so it loads the input opened above.
+	p, err := r.Build(def, func(context.Context) (io.Reader, error) {
+		return in, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	done := make(chan struct{})
+
+	if *progress > 0 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			reportProgress(e.stderr, p, *progress, done)
+		}()
+	}
+
+	tasks, runErr := p.Run(ctx, nil)
+
+	close(done)
+
+	wg.Wait()
+
+	fmt.Fprintf(e.stderr, "progress: %s\n", p.GetProgressPercent().Value())
+	fmt.Fprintf(e.stderr, "pipeline %q:\n", def.Name)
+
+	printMetrics(e.stderr, p.GetMetrics())
+
+	if runErr != nil {
+		return runErr
+	}
+
+	out, closeOut, err := openOutput(e, *output)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(out)
+	encoder := json.NewEncoder(w)
+
+	for _, record := range tasks[idx].ConvertedData {
+		if err := encoder.Encode(record); err != nil {
+			return errors.Join(err, closeOut())
+		}
+	}
+
+	if deadLetters := len(tasks[idx].DeadLetters); deadLetters > 0 {
+		fmt.Fprintf(e.stderr, "stage %q: %d dead letter(s)\n", def.Stages[idx].Name, deadLetters)
+	}
+
+	return errors.Join(w.Flush(), closeOut())
+}
@@ -0,0 +1,21 @@
+// Etler runs, validates and inspects declarative pipelines, without writing
+// Go. See the `registry` package for the definition format.
+//
+// Usage:
+//
+//	etler run [-input path] [-output path] [-stage name] [-progress interval] <definition>
+//	etler validate <definition>
+//	etler graph <definition>
+//
+// `run` loads the input, a file or stdin, with the definition's loader, runs
+// the pipeline, and writes the records converted by a stage, the last one by
+// default, as NDJSON, to a file or stdout. Progress, and the pipeline's
+// metrics, are reported on stderr. `validate` checks the definition, and the
+// options of its components, against the built-in components. `graph` prints
+// the stage, and processor topology.
+//
+// Records are JSON objects. Built-in components are the `json`, and `ndjson`
+// loaders, the `passthru` converter, and the `select`, `drop`, `rename`, and
+// `set` processors. Applications with their own components build their own
+// command, around their own `registry.Registry`.
+package main
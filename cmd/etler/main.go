@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Exit codes.
+const (
+	exitOK = iota
+	exitFailure
+	exitUsage
+)
+
+// command is a subcommand.
+type command struct {
+	// usage of the command, after its name.
+	usage string
+
+	// summary of the command.
+	summary string
+
+	// run runs the command, with its arguments.
+	run func(ctx context.Context, env *env, args []string) error
+}
+
+// env is what commands read from, and write to.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// usageError is returned by commands called with bad arguments.
+type usageError struct {
+	msg string
+}
+
+//////
+// Methods.
+//////
+
+// Error implements the `error` interface.
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+//////
+// Helpers.
+//////
+
+// commands returns the subcommands, by name.
+func commands() map[string]command {
+	return map[string]command{
+		"run": {
+			usage:   "[-input path] [-output path] [-stage name] [-progress interval] <definition>",
+			summary: "runs the pipeline, reading the input with its loader",
+			run:     runCmd,
+		},
+		"validate": {
+			usage:   "<definition>",
+			summary: "validates the definition against the built-in components",
+			run:     validateCmd,
+		},
+		"graph": {
+			usage:   "<definition>",
+			summary: "prints the stage, and processor topology of the definition",
+			run:     graphCmd,
+		},
+	}
+}
+
+// usage prints the usage of the command.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: etler <command> [flags] <definition>")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	for _, name := range []string{"run", "validate", "graph"} {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands()[name].summary)
+	}
+}
+
+// newFlagSet returns a flag set for the `name` subcommand, printing its usage
+// to `w`.
+func newFlagSet(name string, w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	fs.SetOutput(w)
+
+	fs.Usage = func() {
+		fmt.Fprintf(w, "Usage: etler %s %s\n", name, commands()[name].usage)
+
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// definitionArg returns the definition path, the only positional argument.
+func definitionArg(fs *flag.FlagSet) (string, error) {
+	if fs.NArg() != 1 {
+		fs.Usage()
+
+		return "", &usageError{msg: "expected exactly one definition"}
+	}
+
+	return fs.Arg(0), nil
+}
+
+// run runs the command with `args`, and returns its exit code.
+func run(ctx context.Context, e *env, args []string) int {
+	if len(args) == 0 {
+		usage(e.stderr)
+
+		return exitUsage
+	}
+
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(e.stdout)
+
+		return exitOK
+	}
+
+	cmd, ok := commands()[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "etler: unknown command %q\n\n", args[0])
+
+		usage(e.stderr)
+
+		return exitUsage
+	}
+
+	if err := cmd.run(ctx, e, args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+
+		fmt.Fprintf(e.stderr, "etler %s: %v\n", args[0], err)
+
+		var uErr *usageError
+
+		if errors.As(err, &uErr) {
+			return exitUsage
+		}
+
+		return exitFailure
+	}
+
+	return exitOK
+}
+
+//////
+// Main.
+//////
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	code := run(ctx, &env{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}, os.Args[1:])
+
+	stop()
+
+	os.Exit(code)
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDefinition = `
+name: users
+loader:
+  type: ndjson
+stages:
+  - name: clean
+    converter:
+      type: passthru
+    processors:
+      - type: drop
+        options:
+          fields: [password]
+      - type: rename
+        name: rename-name
+        options:
+          fields:
+            name: fullName
+      - type: set
+        disabled: true
+        options:
+          values:
+            ignored: true
+  - name: tag
+    dependsOn: [clean]
+    converter:
+      type: passthru
+    processors:
+      - type: set
+        async: true
+        options:
+          values:
+            async: true
+      - type: set
+        options:
+          values:
+            source: etler
+`
+
+// writeDefinition writes `content` to a definition file, and returns its path.
+func writeDefinition(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "definition.yaml")
+
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+// runWith runs the command with `args`, and `stdin`.
+func runWith(stdin string, args ...string) (int, string, string) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	code := run(context.Background(), &env{
+		stdin:  strings.NewReader(stdin),
+		stdout: stdout,
+		stderr: stderr,
+	}, args)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	path := writeDefinition(t, testDefinition)
+
+	input := `{"name": "john", "password": "secret"}` + "\n" + `{"name": "jane"}` + "\n"
+
+	// Happy path: stdin to stdout, last stage.
+	code, stdout, stderr := runWith(input, "run", "-progress", "0", path)
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t,
+		`{"fullName":"john","source":"etler"}`+"\n"+`{"fullName":"jane","source":"etler"}`+"\n",
+		stdout,
+	)
+	assert.Contains(t, stderr, "progress: 100%")
+	assert.Contains(t, stderr, "counterDone: 1")
+
+	// Happy path: file to file, first stage.
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.ndjson")
+	out := filepath.Join(dir, "out.ndjson")
+
+	assert.NoError(t, os.WriteFile(in, []byte(input), 0o600))
+
+	code, _, stderr = runWith("", "run", "-input", in, "-output", out, "-stage", "clean", path)
+	assert.Equal(t, exitOK, code, stderr)
+
+	b, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"fullName":"john"}`+"\n"+`{"fullName":"jane"}`+"\n", string(b))
+
+	// Bad path: unknown stage.
+	code, _, stderr = runWith(input, "run", "-stage", "load", path)
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown stage "load"`)
+
+	// Bad path: invalid input.
+	code, _, stderr = runWith("{", "run", "-progress", "0", path)
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, "line 1")
+
+	// Bad path: missing input file.
+	code, _, _ = runWith("", "run", "-input", filepath.Join(dir, "missing"), path)
+	assert.Equal(t, exitFailure, code)
+
+	// Bad path: no loader.
+	code, _, stderr = runWith(input, "run", writeDefinition(t, strings.Replace(testDefinition, "loader:\n  type: ndjson\n", "", 1)))
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, "has no loader")
+}
+
+func TestValidate(t *testing.T) {
+	// Happy path.
+	code, stdout, _ := runWith("", "validate", writeDefinition(t, testDefinition))
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, `pipeline "users" is valid, 2 stage(s)`)
+
+	// Bad path: unknown types.
+	code, _, stderr := runWith("", "validate", writeDefinition(t, strings.ReplaceAll(testDefinition, "type: drop", "type: upper")))
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, `stages[0].processors[0]: processor type "upper"`)
+
+	// Bad path: unknown options.
+	code, _, stderr = runWith("", "validate", writeDefinition(t, strings.ReplaceAll(testDefinition, "fields: [password]", "field: password")))
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, `stages[0]: processors[0]`)
+
+	// Bad path: no definition.
+	code, _, _ = runWith("", "validate")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestGraph(t *testing.T) {
+	// Happy path.
+	code, stdout, stderr := runWith("", "graph", writeDefinition(t, testDefinition))
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, `pipeline "users" (dag)
+  loader "ndjson" (ndjson)
+  stage "clean"
+    1. processor "drop" (drop)
+    2. processor "rename-name" (rename)
+    3. processor "set" (set) [disabled]
+    -> converter "passthru" (passthru)
+  stage "tag" <- clean
+    1. processor "set" (set) [async]
+    2. processor "set" (set)
+    -> converter "passthru" (passthru)
+`, stdout)
+
+	// Bad path: missing definition.
+	code, _, stderr = runWith("", "graph", "missing.yaml")
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, "open definition")
+}
+
+func TestUsage(t *testing.T) {
+	// Happy path.
+	code, stdout, _ := runWith("", "help")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "validate")
+
+	// Bad path: no command.
+	code, _, _ = runWith("")
+	assert.Equal(t, exitUsage, code)
+
+	// Bad path: unknown command.
+	code, _, stderr := runWith("", "deploy")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown command "deploy"`)
+
+	// Bad path: unknown flag.
+	code, _, _ = runWith("", "run", "-force", "definition.yaml")
+	assert.Equal(t, exitUsage, code)
+
+	// Edge case: command help.
+	code, _, stderr = runWith("", "run", "-h")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stderr, "-progress")
+}
@@ -0,0 +1,492 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/event"
+	"github.com/thalesfsp/etler/v3/report"
+	"github.com/thalesfsp/status"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// EventStreamContentType is the content type of server-sent events.
+const EventStreamContentType = "text/event-stream"
+
+// ProgressEvent is the type of the server-sent events reporting the progress
+// of a pipeline, sent on top of its lifecycle events.
+const ProgressEvent = "progress"
+
+// DefaultProgressInterval is the default interval between progress events.
+const DefaultProgressInterval = time.Second
+
+// eventBuffer is the count of lifecycle events buffered for each client of
+// the event stream. Events overflowing it are dropped, rather than holding up
+// the pipeline.
+const eventBuffer = 256
+
+// Pipeline is a pipeline controlled through the handler. Any
+// `pipeline.IPipeline` is one.
+type Pipeline interface {
+	// GetName returns the `Name` of the pipeline.
+	GetName() string
+
+	// GetDescription returns the `Description` of the pipeline.
+	GetDescription() string
+
+	// GetMetrics returns the pipeline's metrics.
+	GetMetrics() map[string]string
+
+	// GetProgressPercent returns the `ProgressPercent` of the pipeline.
+	GetProgressPercent() *expvar.String
+
+	// GetPaused returns the Paused status.
+	GetPaused() status.Status
+
+	// SetPause the pipeline.
+	SetPause(state bool)
+
+	// GetRuns returns the reports of the runs in progress.
+	GetRuns() []*report.Run
+
+	// GetRun returns the report of the run in progress `runID`.
+	GetRun(runID string) (*report.Run, error)
+
+	// SetRunPause pauses, or resumes, the run in progress `runID` only.
+	SetRunPause(runID string, state bool) error
+
+	// Cancel cancels the run in progress `runID`.
+	Cancel(runID string) error
+
+	// DrainRun drains the run in progress `runID`.
+	DrainRun(runID string) error
+
+	// Drain drains all the runs in progress.
+	Drain()
+
+	// GetEventBus returns the bus the pipeline publishes its events to.
+	GetEventBus() *event.Bus
+}
+
+// Info describes a pipeline, and its runs in progress.
+type Info struct {
+	// Name of the pipeline.
+	Name string `json:"name"`
+
+	// Description of the pipeline.
+	Description string `json:"description"`
+
+	// Paused is whether the whole pipeline is paused.
+	Paused bool `json:"paused"`
+
+	// ProgressPercent of the last run.
+	ProgressPercent string `json:"progressPercent"`
+
+	// Metrics of the pipeline, see `GetMetrics`.
+	Metrics map[string]string `json:"metrics"`
+
+	// Runs in progress, oldest first.
+	Runs []*report.Run `json:"runs"`
+}
+
+// Handler is the control plane of pipelines: it lists them, pauses,
+// resumes, drains, and cancels their runs, and streams their progress.
+type Handler struct {
+	mu        sync.RWMutex
+	pipelines map[string]Pipeline
+
+	// progressInterval is the interval between progress events.
+	progressInterval time.Duration
+
+	mux *http.ServeMux
+}
+
+// Func allows to specify handler's options.
+type Func func(h *Handler) *Handler
+
+//////
+// Helpers.
+//////
+
+// info describes `p`.
+func info(p Pipeline) Info {
+	return Info{
+		Name:            p.GetName(),
+		Description:     p.GetDescription(),
+		Paused:          p.GetPaused() == status.Paused,
+		ProgressPercent: p.GetProgressPercent().Value(),
+		Metrics:         p.GetMetrics(),
+		Runs:            p.GetRuns(),
+	}
+}
+
+// writeJSON writes `v`, as JSON, with the `code` status.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes `err`, as JSON, with its status code, if a custom
+// error, or 500.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+
+	var cE *customerror.CustomError
+	if errors.As(err, &cE) && cE.StatusCode != 0 {
+		code = cE.StatusCode
+	}
+
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+// writeEvent writes `v`, as a server-sent event of type `eventType`.
+func writeEvent(w http.ResponseWriter, eventType string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
+
+	return err
+}
+
+//////
+// Built-in options.
+//////
+
+// WithPipelines controls `pipelines`. See `Register`.
+func WithPipelines(pipelines ...Pipeline) Func {
+	return func(h *Handler) *Handler {
+		h.Register(pipelines...)
+
+		return h
+	}
+}
+
+// WithProgressInterval sets the interval between progress events. Default
+// is `DefaultProgressInterval`.
+func WithProgressInterval(interval time.Duration) Func {
+	return func(h *Handler) *Handler {
+		if interval > 0 {
+			h.progressInterval = interval
+		}
+
+		return h
+	}
+}
+
+//////
+// Methods.
+//////
+
+// Register controls `pipelines`. A pipeline replaces any registered one with
+// the same name.
+func (h *Handler) Register(pipelines ...Pipeline) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, p := range pipelines {
+		h.pipelines[p.GetName()] = p
+	}
+}
+
+// pipeline returns the pipeline named in the path of `r`.
+func (h *Handler) pipeline(r *http.Request) (Pipeline, error) {
+	name := r.PathValue("pipeline")
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	p, ok := h.pipelines[name]
+	if !ok {
+		return nil, customerror.NewNotFoundError(fmt.Sprintf("pipeline %q", name))
+	}
+
+	return p, nil
+}
+
+// list lists the pipelines, by name.
+func (h *Handler) list(w http.ResponseWriter, _ *http.Request) {
+	h.mu.RLock()
+
+	infos := make([]Info, 0, len(h.pipelines))
+
+	for _, p := range h.pipelines {
+		infos = append(infos, info(p))
+	}
+
+	h.mu.RUnlock()
+
+	slices.SortFunc(infos, func(a, b Info) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	writeJSON(w, http.StatusOK, infos)
+}
+
+// get describes a pipeline.
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	p, err := h.pipeline(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, info(p))
+}
+
+// setPause pauses, or resumes, a pipeline.
+func (h *Handler) setPause(state bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := h.pipeline(r)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		p.SetPause(state)
+
+		writeJSON(w, http.StatusOK, info(p))
+	}
+}
+
+// drain drains all the runs in progress of a pipeline.
+func (h *Handler) drain(w http.ResponseWriter, r *http.Request) {
+	p, err := h.pipeline(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	p.Drain()
+
+	writeJSON(w, http.StatusAccepted, info(p))
+}
+
+// runs lists the runs in progress of a pipeline.
+func (h *Handler) runs(w http.ResponseWriter, r *http.Request) {
+	p, err := h.pipeline(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, p.GetRuns())
+}
+
+// run reports a run in progress.
+func (h *Handler) run(w http.ResponseWriter, r *http.Request) {
+	p, err := h.pipeline(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	run, err := p.GetRun(r.PathValue("run"))
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// controlRun applies `fn` to a run in progress, and reports it, with the
+// `code` status.
+func (h *Handler) controlRun(code int, fn func(p Pipeline, runID string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := h.pipeline(r)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		runID := r.PathValue("run")
+
+		// Reported before cancelling, as a cancelled run may finish anytime.
+		run, err := p.GetRun(runID)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		if err := fn(p, runID); err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		if latest, err := p.GetRun(runID); err == nil {
+			run = latest
+		}
+
+		writeJSON(w, code, run)
+	}
+}
+
+// events streams the lifecycle events, and the progress, of a pipeline, or
+// of one of its runs, with the `run` query parameter, as server-sent events.
+func (h *Handler) events(w http.ResponseWriter, r *http.Request) {
+	p, err := h.pipeline(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, customerror.NewFailedToError("stream events, the response can't be flushed"))
+
+		return
+	}
+
+	runID := r.URL.Query().Get("run")
+
+	events := make(chan event.Event, eventBuffer)
+
+	unsubscribe := p.GetEventBus().Subscribe(func(_ context.Context, e event.Event) {
+		if runID != "" && e.RunID != runID {
+			return
+		}
+
+		select {
+		case events <- e:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", EventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	flusher.Flush()
+
+	ticker := time.NewTicker(h.progressInterval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-events:
+			err = writeEvent(w, string(e.Type), e)
+		case <-ticker.C:
+			progress := info(p)
+
+			if runID != "" {
+				progress.Runs = slices.DeleteFunc(progress.Runs, func(run *report.Run) bool {
+					return run.RunID != runID
+				})
+			}
+
+			err = writeEvent(w, ProgressEvent, progress)
+		}
+
+		if err != nil {
+			return
+		}
+
+		flusher.Flush()
+	}
+}
+
+// ServeHTTP implements the `http.Handler` interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+//////
+// Factory.
+//////
+
+// New returns a new handler, controlling the registered pipelines. Routes:
+//
+//	GET  /pipelines
+//	GET  /pipelines/{pipeline}
+//	POST /pipelines/{pipeline}/pause
+//	POST /pipelines/{pipeline}/resume
+//	POST /pipelines/{pipeline}/drain
+//	GET  /pipelines/{pipeline}/events[?run={run}]
+//	GET  /pipelines/{pipeline}/runs
+//	GET  /pipelines/{pipeline}/runs/{run}
+//	POST /pipelines/{pipeline}/runs/{run}/pause
+//	POST /pipelines/{pipeline}/runs/{run}/resume
+//	POST /pipelines/{pipeline}/runs/{run}/drain
+//	POST /pipelines/{pipeline}/runs/{run}/cancel
+//
+// To serve it under a prefix, wrap it with `http.StripPrefix`.
+func New(opts ...Func) *Handler {
+	// Enforces interface implementation.
+	var _ http.Handler = (*Handler)(nil)
+
+	h := &Handler{
+		pipelines:        make(map[string]Pipeline),
+		progressInterval: DefaultProgressInterval,
+		mux:              http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /pipelines", h.list)
+	h.mux.HandleFunc("GET /pipelines/{pipeline}", h.get)
+	h.mux.HandleFunc("POST /pipelines/{pipeline}/pause", h.setPause(true))
+	h.mux.HandleFunc("POST /pipelines/{pipeline}/resume", h.setPause(false))
+	h.mux.HandleFunc("POST /pipelines/{pipeline}/drain", h.drain)
+	h.mux.HandleFunc("GET /pipelines/{pipeline}/events", h.events)
+	h.mux.HandleFunc("GET /pipelines/{pipeline}/runs", h.runs)
+	h.mux.HandleFunc("GET /pipelines/{pipeline}/runs/{run}", h.run)
+
+	h.mux.HandleFunc("POST /pipelines/{pipeline}/runs/{run}/pause", h.controlRun(
+		http.StatusOK,
+		func(p Pipeline, runID string) error { return p.SetRunPause(runID, true) },
+	))
+
+	h.mux.HandleFunc("POST /pipelines/{pipeline}/runs/{run}/resume", h.controlRun(
+		http.StatusOK,
+		func(p Pipeline, runID string) error { return p.SetRunPause(runID, false) },
+	))
+
+	// Draining, and cancellation, are asynchronous: the run stops once its
+	// running components finish, or give up.
+	h.mux.HandleFunc("POST /pipelines/{pipeline}/runs/{run}/drain", h.controlRun(
+		http.StatusAccepted,
+		func(p Pipeline, runID string) error { return p.DrainRun(runID) },
+	))
+
+	h.mux.HandleFunc("POST /pipelines/{pipeline}/runs/{run}/cancel", h.controlRun(
+		http.StatusAccepted,
+		func(p Pipeline, runID string) error { return p.Cancel(runID) },
+	))
+
+	for _, opt := range opts {
+		h = opt(h)
+	}
+
+	return h
+}
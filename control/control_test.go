@@ -0,0 +1,280 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/pipeline"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/report"
+	"github.com/thalesfsp/etler/v3/stage"
+	"github.com/thalesfsp/status"
+)
+
+// Enforces interface implementation.
+var _ Pipeline = pipeline.IPipeline[int, int](nil)
+
+// newPipeline returns a pipeline whose first processor signals `started`,
+// then blocks until `release` is closed, or its context is done.
+func newPipeline(t *testing.T, name string, started chan<- struct{}, release <-chan struct{}) pipeline.IPipeline[int, int] {
+	t.Helper()
+
+	blocker, err := processor.New(name+"-block", "blocks until released", func(ctx context.Context, in []int) ([]int, error) {
+		started <- struct{}{}
+
+		select {
+		case <-release:
+			return in, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	require.NoError(t, err)
+
+	stg, err := stage.New(name+"-stage", "blocking stage", converter.MustDefault(
+		func(ctx context.Context, in int) (int, error) { return in, nil },
+	), blocker)
+	require.NoError(t, err)
+
+	passthru, err := processor.New(name+"-passthru", "passes through", func(ctx context.Context, in []int) ([]int, error) {
+		return in, nil
+	})
+	require.NoError(t, err)
+
+	next, err := stage.New(name+"-next", "next stage", converter.MustDefault(
+		func(ctx context.Context, in int) (int, error) { return in, nil },
+	), passthru)
+	require.NoError(t, err)
+
+	p, err := pipeline.New(name, "controlled pipeline", false, stg, next)
+	require.NoError(t, err)
+
+	return p
+}
+
+// do serves a `method` request to `path`, and decodes the response into
+// `v`, if not nil.
+func do(t *testing.T, h http.Handler, method, path string, v any) int {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+
+	if v != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), v), rec.Body.String())
+	}
+
+	return rec.Code
+}
+
+// Happy path: pipelines are listed, paused, and resumed; runs are listed,
+// paused, resumed, drained, and cancelled, by ID.
+func TestHandler_control(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	p := newPipeline(t, "control-handler", started, release)
+
+	h := New(WithPipelines(p))
+
+	var infos []Info
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/pipelines", &infos))
+	require.Len(t, infos, 1)
+	assert.Equal(t, "control-handler", infos[0].Name)
+	assert.Equal(t, `"created"`, infos[0].Metrics["status"])
+	assert.Empty(t, infos[0].Runs)
+
+	var i Info
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodPost, "/pipelines/control-handler/pause", &i))
+	assert.True(t, i.Paused)
+	assert.Equal(t, status.Paused, p.GetPaused())
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodPost, "/pipelines/control-handler/resume", &i))
+	assert.False(t, i.Paused)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := p.Run(pipeline.ContextWithRunID(ctx, "run-1"), []int{1})
+
+		done <- err
+	}()
+
+	<-started
+
+	var runs []*report.Run
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/pipelines/control-handler/runs", &runs))
+	require.Len(t, runs, 1)
+	assert.Equal(t, "run-1", runs[0].RunID)
+
+	var run report.Run
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodPost, "/pipelines/control-handler/runs/run-1/pause", &run))
+	assert.Equal(t, status.Paused.String(), run.Status)
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodPost, "/pipelines/control-handler/runs/run-1/resume", &run))
+	assert.Equal(t, status.Runnning.String(), run.Status)
+
+	assert.Equal(t, http.StatusOK, do(t, h, http.MethodGet, "/pipelines/control-handler/runs/run-1", &run))
+	assert.Equal(t, "run-1", run.RunID)
+
+	assert.Equal(t, http.StatusAccepted, do(t, h, http.MethodPost, "/pipelines/control-handler/runs/run-1/cancel", nil))
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run wasn't cancelled")
+	}
+
+	// Happy path: a drained run stops before its next stage.
+	go func() {
+		_, err := p.Run(pipeline.ContextWithRunID(ctx, "run-2"), []int{1})
+
+		done <- err
+	}()
+
+	<-started
+
+	assert.Equal(t, http.StatusAccepted, do(t, h, http.MethodPost, "/pipelines/control-handler/runs/run-2/drain", &run))
+	assert.Equal(t, "run-2", run.RunID)
+
+	close(release)
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, pipeline.ErrInterrupted)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run wasn't drained")
+	}
+
+	assert.Equal(t, http.StatusAccepted, do(t, h, http.MethodPost, "/pipelines/control-handler/drain", &i))
+	assert.Equal(t, `"interrupted"`, i.Metrics["status"])
+
+	// Bad path: unknown pipelines, and runs.
+	var e map[string]string
+
+	assert.Equal(t, http.StatusNotFound, do(t, h, http.MethodGet, "/pipelines/unknown", &e))
+	assert.Contains(t, e["error"], `pipeline "unknown" not found`)
+
+	assert.Equal(t, http.StatusNotFound, do(t, h, http.MethodPost, "/pipelines/control-handler/runs/run-1/cancel", &e))
+	assert.Contains(t, e["error"], `run "run-1"`)
+
+	// Bad path: wrong method.
+	assert.Equal(t, http.StatusMethodNotAllowed, do(t, h, http.MethodGet, "/pipelines/control-handler/pause", nil))
+}
+
+// Happy path: lifecycle events, and progress, are streamed as server-sent
+// events.
+func TestHandler_events(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	p := newPipeline(t, "control-events", started, release)
+
+	srv := httptest.NewServer(New(WithPipelines(p), WithProgressInterval(50*time.Millisecond)))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/pipelines/control-events/events?run=run-1", nil)
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, EventStreamContentType, res.Header.Get("Content-Type"))
+
+	done := make(chan error, 2)
+
+	go func() {
+		_, err := p.Run(pipeline.ContextWithRunID(ctx, "run-1"), []int{1})
+
+		done <- err
+	}()
+
+	<-started
+
+	// Edge case: other runs are filtered out.
+	go func() {
+		_, err := p.Run(pipeline.ContextWithRunID(ctx, "run-2"), []int{1})
+
+		done <- err
+	}()
+
+	types := map[string]bool{}
+
+	var progress Info
+
+	released := false
+
+	scanner := bufio.NewScanner(res.Body)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		eventType, ok := strings.CutPrefix(line, "event: ")
+		if !ok {
+			continue
+		}
+
+		require.True(t, scanner.Scan())
+
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		require.True(t, ok)
+
+		types[eventType] = true
+
+		if eventType == ProgressEvent {
+			require.NoError(t, json.Unmarshal([]byte(data), &progress))
+
+			if len(progress.Runs) > 0 && !released {
+				released = true
+
+				close(release)
+			}
+
+			continue
+		}
+
+		var e map[string]any
+
+		require.NoError(t, json.Unmarshal([]byte(data), &e))
+		assert.Equal(t, "run-1", e["runID"])
+
+		if eventType == "run.finished" {
+			break
+		}
+	}
+
+	assert.True(t, types["run.started"])
+	assert.True(t, types["stage.finished"])
+	assert.True(t, types[ProgressEvent])
+
+	require.Len(t, progress.Runs, 1)
+	assert.Equal(t, "run-1", progress.Runs[0].RunID)
+
+	for range 2 {
+		require.NoError(t, <-done)
+	}
+}
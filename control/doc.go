@@ -0,0 +1,19 @@
+// Package control is an HTTP control plane for pipelines.
+//
+// `New` returns an `http.Handler` listing the registered pipelines, with
+// their metrics, and runs in progress, pausing, resuming, and draining a
+// pipeline, or a single run, cancelling a run, by run ID, and streaming the
+// lifecycle events, and the progress, of a pipeline as server-sent events:
+//
+//	h := control.New(control.WithPipelines(p))
+//
+//	http.Handle("/etler/", http.StripPrefix("/etler", h))
+//
+//	// curl -X POST localhost:8080/etler/pipelines/nightly/runs/2026-10-17/pause
+//	// curl -N localhost:8080/etler/pipelines/nightly/events?run=2026-10-17
+//
+// Runs are identified by their run ID, the one set with
+// `pipeline.ContextWithRunID`, or generated, and reported by
+// `pipeline.RunWithReport`. The handler doesn't authenticate requests: guard
+// it as any other administrative endpoint.
+package control
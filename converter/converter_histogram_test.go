@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Happy path: per-converter buckets, and percentiles.
+func TestConverter_histogram(t *testing.T) {
+	double := func(ctx context.Context, in int) (int, error) {
+		return in * 2, nil
+	}
+
+	c, err := New("converter-histogram", "doubles", double, WithHistogram[int, int]([]float64{10, 100}, []float64{75}))
+	require.NoError(t, err)
+
+	_, err = c.Run(context.Background(), 1)
+	require.NoError(t, err)
+
+	m := c.GetMetrics()
+	assert.Equal(t, "1", m["durationCount"])
+	assert.Contains(t, m, "durationP75")
+	assert.NotContains(t, m, "durationP99")
+
+	// Bad path: invalid buckets fail the factory.
+	_, err = New("converter-histogram-invalid", "doubles", double, WithHistogram[int, int]([]float64{-1}, nil))
+	assert.ErrorContains(t, err, "buckets")
+}
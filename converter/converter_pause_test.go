@@ -0,0 +1,84 @@
+package converter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/status"
+)
+
+// Happy path: a converter waits while paused, and converts once resumed.
+func TestConverter_pause(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ran := make(chan struct{}, 1)
+
+	c, err := New(
+		"converter-pause",
+		"signals when it runs",
+		func(ctx context.Context, in int) (int, error) {
+			ran <- struct{}{}
+
+			return in, nil
+		},
+	)
+	require.NoError(t, err)
+
+	pc := shared.NewPauseController()
+	pc.Pause()
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := c.Run(shared.ContextWithPause(ctx, pc), 1)
+
+		done <- err
+	}()
+
+	select {
+	case <-ran:
+		t.Fatal("converter ran while paused")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	assert.Equal(t, status.Paused.String(), c.GetStatus().Value())
+
+	pc.Resume()
+
+	require.NoError(t, <-done)
+	assert.Equal(t, status.Done.String(), c.GetStatus().Value())
+}
+
+// Edge case: a paused converter whose run drains is interrupted, not
+// failed.
+func TestConverter_pause_drain(t *testing.T) {
+	c, err := New(
+		"converter-pause-drain",
+		"never runs",
+		func(ctx context.Context, in int) (int, error) {
+			return in, nil
+		},
+	)
+	require.NoError(t, err)
+
+	pc := shared.NewPauseController()
+	pc.Pause()
+
+	d := shared.NewDrainer()
+
+	ctx := shared.ContextWithDrainer(shared.ContextWithPause(context.Background(), pc), d)
+
+	time.AfterFunc(100*time.Millisecond, d.Drain)
+
+	_, err = c.Run(ctx, 1)
+	require.ErrorIs(t, err, shared.ErrInterrupted)
+
+	assert.Equal(t, status.Interrupted.String(), c.GetStatus().Value())
+	assert.Equal(t, int64(1), c.GetCounterInterrupted().Value())
+	assert.Equal(t, int64(0), c.GetCounterFailed().Value())
+}
@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/retry"
+)
+
+// Happy path: a transient failure is retried, every attempt counted.
+func TestConverter_retry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	calls := 0
+
+	c, err := New(
+		"converter-retry",
+		"fails once",
+		func(ctx context.Context, in int) (int, error) {
+			calls++
+
+			if calls < 2 {
+				return 0, errors.New("transient")
+			}
+
+			return in * 2, nil
+		},
+		WithRetry[int, int](retry.Must(3, retry.Jitter(retry.Exponential(time.Millisecond, 0)))),
+	)
+	require.NoError(t, err)
+
+	out, err := c.Run(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 4, out)
+
+	assert.Equal(t, int64(2), c.GetCounterRunning().Value())
+	assert.Equal(t, int64(1), c.GetCounterFailed().Value())
+	assert.Equal(t, int64(1), c.GetCounterDone().Value())
+}
@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Bad path: a hung conversion fails with `ErrTimeout`, counted as
+// interrupted.
+func TestConverter_timeout(t *testing.T) {
+	c, err := New(
+		"converter-timeout",
+		"hangs until canceled",
+		func(ctx context.Context, in int) (int, error) {
+			<-ctx.Done()
+
+			return 0, ctx.Err()
+		},
+		WithTimeout[int, int](10*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	_, err = c.Run(context.Background(), 1)
+	require.ErrorIs(t, err, ErrTimeout)
+
+	assert.Equal(t, int64(1), c.GetCounterInterrupted().Value())
+	assert.Equal(t, int64(1), c.GetCounterFailed().Value())
+}
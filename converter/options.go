@@ -2,6 +2,9 @@ package converter
 
 import (
 	"context"
+	"time"
+
+	"github.com/thalesfsp/etler/v3/retry"
 )
 
 //////
@@ -19,6 +22,17 @@ type OnFinished[In, Out any] func(ctx context.Context, c IConverter[In, Out], or
 // Built-in options.
 //////
 
+// WithHistogram sets the buckets, in milliseconds, and the percentiles of
+// the duration histogram. Invalid ones fail the factory.
+func WithHistogram[In, Out any](buckets, percentiles []float64) Func[In, Out] {
+	return func(p IConverter[In, Out]) IConverter[In, Out] {
+		// NOTE: The factory reports errors, once options are applied.
+		_ = p.SetHistogram(buckets, percentiles)
+
+		return p
+	}
+}
+
 // WithOnFinished sets the OnFinished function.
 func WithOnFinished[In, Out any](onFinished OnFinished[In, Out]) Func[In, Out] {
 	return func(p IConverter[In, Out]) IConverter[In, Out] {
@@ -27,3 +41,22 @@ func WithOnFinished[In, Out any](onFinished OnFinished[In, Out]) Func[In, Out] {
 		return p
 	}
 }
+
+// WithRetry sets the retry policy, see the `retry` package.
+func WithRetry[In, Out any](policy *retry.Policy) Func[In, Out] {
+	return func(p IConverter[In, Out]) IConverter[In, Out] {
+		p.SetRetry(policy)
+
+		return p
+	}
+}
+
+// WithTimeout sets the timeout of a run. Runs taking longer fail with
+// `ErrTimeout`, and count as interrupted.
+func WithTimeout[In, Out any](timeout time.Duration) Func[In, Out] {
+	return func(p IConverter[In, Out]) IConverter[In, Out] {
+		p.SetTimeout(timeout)
+
+		return p
+	}
+}
@@ -0,0 +1,3 @@
+// Package json contains the JSON converter which allows to concurrently
+// store the processed data into a JSON array.
+package json
@@ -0,0 +1,82 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Name of the converter.
+const Name = "json"
+
+// JSON definition.
+type JSON[In any] struct {
+	converter.IConverter[In, string] `json:"converter" validate:"required"`
+}
+
+//////
+// Factory.
+//////
+
+// New creates a new converter.
+func New[In any](
+	opts ...converter.Func[[]In, string],
+) (*JSON[[]In], error) {
+	// Enforces interface implementation.
+	var _ converter.IConverter[[]In, string] = (*JSON[[]In])(nil)
+
+	conv, err := converter.New(
+		Name,
+		fmt.Sprintf("%s %s", Name, converter.Type),
+		func(tracedContext context.Context, in []In) (string, error) {
+			// NOTE: An empty array, rather than `null`.
+			if in == nil {
+				in = []In{}
+			}
+
+			b, err := json.Marshal(in)
+			if err != nil {
+				return "", err
+			}
+
+			return string(b), nil
+		},
+		opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &JSON[[]In]{
+		conv,
+	}
+
+	// NOTE: `opts` were already applied by `converter.New` — applying them
+	// again here would run each option twice.
+
+	// Validation.
+	if err := validation.Validate(j); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Must returns a new converter or panics if an error occurs.
+func Must[In any](
+	opts ...converter.Func[[]In, string],
+) *JSON[[]In] {
+	j, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return j
+}
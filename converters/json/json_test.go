@@ -0,0 +1,55 @@
+package json
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/converter"
+)
+
+// Test struct.
+type Test struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestNew(t *testing.T) {
+	buf := new(strings.Builder)
+
+	jsonConverter, err := New(
+		converter.WithOnFinished(func(ctx context.Context, c converter.IConverter[[]Test, string], originalIn []Test, convertedOut string) {
+			buf.WriteString(c.GetName() + " finished")
+		}),
+	)
+	require.NoError(t, err)
+
+	convertedData, err := jsonConverter.Run(context.Background(), []Test{{ID: "1", Name: "John"}, {ID: "2", Name: "Peter"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, `[{"id":"1","name":"John"},{"id":"2","name":"Peter"}]`, convertedData)
+	assert.Equal(t, "json finished", buf.String())
+}
+
+// Edge case: nil and empty slices yield an empty array.
+func TestMust_empty(t *testing.T) {
+	c := Must[Test]()
+
+	out, err := c.Run(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", out)
+
+	out, err = c.Run(context.Background(), []Test{})
+	require.NoError(t, err)
+	assert.Equal(t, "[]", out)
+}
+
+// Bad path: unsupported values fail.
+func TestNew_unsupportedValue(t *testing.T) {
+	c := Must[chan int]()
+
+	_, err := c.Run(context.Background(), []chan int{make(chan int)})
+	require.Error(t, err)
+}
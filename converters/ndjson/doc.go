@@ -0,0 +1,3 @@
+// Package ndjson contains the NDJSON converter which allows to concurrently
+// store the processed data into newline-delimited JSON, one item per line.
+package ndjson
@@ -0,0 +1,84 @@
+package ndjson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Name of the converter.
+const Name = "ndjson"
+
+// NDJSON definition.
+type NDJSON[In any] struct {
+	converter.IConverter[In, string] `json:"converter" validate:"required"`
+}
+
+//////
+// Factory.
+//////
+
+// New creates a new converter.
+func New[In any](
+	opts ...converter.Func[[]In, string],
+) (*NDJSON[[]In], error) {
+	// Enforces interface implementation.
+	var _ converter.IConverter[[]In, string] = (*NDJSON[[]In])(nil)
+
+	conv, err := converter.New(
+		Name,
+		fmt.Sprintf("%s %s", Name, converter.Type),
+		func(tracedContext context.Context, in []In) (string, error) {
+			buf := new(strings.Builder)
+
+			// NOTE: `Encode` terminates every item with a newline.
+			encoder := json.NewEncoder(buf)
+
+			for i, item := range in {
+				if err := encoder.Encode(item); err != nil {
+					return "", fmt.Errorf("item %d: %w", i, err)
+				}
+			}
+
+			return buf.String(), nil
+		},
+		opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &NDJSON[[]In]{
+		conv,
+	}
+
+	// NOTE: `opts` were already applied by `converter.New` — applying them
+	// again here would run each option twice.
+
+	// Validation.
+	if err := validation.Validate(n); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Must returns a new converter or panics if an error occurs.
+func Must[In any](
+	opts ...converter.Func[[]In, string],
+) *NDJSON[[]In] {
+	n, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return n
+}
@@ -0,0 +1,50 @@
+package ndjson
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/converter"
+)
+
+// Test struct.
+type Test struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestNew(t *testing.T) {
+	buf := new(strings.Builder)
+
+	ndjsonConverter, err := New(
+		converter.WithOnFinished(func(ctx context.Context, c converter.IConverter[[]Test, string], originalIn []Test, convertedOut string) {
+			buf.WriteString(c.GetName() + " finished")
+		}),
+	)
+	require.NoError(t, err)
+
+	convertedData, err := ndjsonConverter.Run(context.Background(), []Test{{ID: "1", Name: "John"}, {ID: "2", Name: "Peter"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "{\"id\":\"1\",\"name\":\"John\"}\n{\"id\":\"2\",\"name\":\"Peter\"}\n", convertedData)
+	assert.Equal(t, "ndjson finished", buf.String())
+}
+
+// Edge case: an empty slice yields an empty string.
+func TestMust_empty(t *testing.T) {
+	out, err := Must[Test]().Run(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+// Bad path: unsupported values fail, with their index.
+func TestNew_unsupportedValue(t *testing.T) {
+	c := Must[any]()
+
+	_, err := c.Run(context.Background(), []any{1, make(chan int)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "item 1")
+}
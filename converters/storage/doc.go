@@ -1,3 +1,7 @@
 // Package storage contains the storage converter which allows to concurrently
 // store the processed data in a storage system.
+//
+// `NewBatched` batches the items a stage converts concurrently, writing them
+// in bulk if the storage implements `batch.BulkWriter`. Each item still gets
+// its own ID and error, so the stage's error policy applies per item.
 package storage
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/etler/v3/batch"
 	"github.com/thalesfsp/etler/v3/converter"
 	"github.com/thalesfsp/etler/v3/internal/shared"
 	"github.com/thalesfsp/params/v2/create"
@@ -27,10 +28,9 @@ type Storage[In any] struct {
 // Factory.
 //////
 
-// New creates a new Storage converter.
-func New[In any](
-	s storage.IStorage,
-	target string,
+// newStorage creates a new Storage converter, converting with `fn`.
+func newStorage[In any](
+	fn converter.Convert[In, string],
 	opts ...converter.Func[In, string],
 ) (*Storage[In], error) {
 	// Enforces interface implementation.
@@ -39,9 +39,7 @@ func New[In any](
 	conv, err := converter.New(
 		Name,
 		fmt.Sprintf("%s %s", Name, converter.Type),
-		func(tracedContext context.Context, in In) (string, error) {
-			return s.Create(tracedContext, shared.GenerateUUID(), target, in, &create.Create{})
-		},
+		fn,
 		opts...,
 	)
 	if err != nil {
@@ -60,6 +58,62 @@ func New[In any](
 	return csv, nil
 }
 
+// New creates a new Storage converter.
+func New[In any](
+	s storage.IStorage,
+	target string,
+	opts ...converter.Func[In, string],
+) (*Storage[In], error) {
+	return newStorage(
+		func(tracedContext context.Context, in In) (string, error) {
+			return s.Create(tracedContext, shared.GenerateUUID(), target, in, &create.Create{})
+		},
+		opts...,
+	)
+}
+
+// NewBatched creates a new Storage converter, batching the items it converts
+// concurrently, as a stage does, according to `policy`: chunks are written
+// in bulk if the storage implements `batch.BulkWriter`, else one item at a
+// time. Each item still gets its own ID, and error.
+//
+// NOTE: The policy's `Linger` is required: it bounds how long an item waits
+// for its chunk to fill up.
+func NewBatched[In any](
+	s storage.IStorage,
+	target string,
+	policy *batch.Policy,
+	opts ...converter.Func[In, string],
+) (*Storage[In], error) {
+	collector, err := batch.NewCollector(
+		s,
+		policy,
+		batch.OpCreate,
+		target,
+		func(ctx context.Context, doc batch.Document) error {
+			_, err := s.Create(ctx, doc.ID, target, doc.Item, &create.Create{})
+
+			return err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStorage(
+		func(tracedContext context.Context, in In) (string, error) {
+			id := shared.GenerateUUID()
+
+			if err := collector.Submit(tracedContext, batch.Document{ID: id, Item: in}); err != nil {
+				return "", err
+			}
+
+			return id, nil
+		},
+		opts...,
+	)
+}
+
 // Must returns a new converter or panics if an error occurs.
 func Must[In any](
 	s storage.IStorage,
@@ -73,3 +127,18 @@ func Must[In any](
 
 	return c
 }
+
+// MustBatched returns a new batching converter or panics if an error occurs.
+func MustBatched[In any](
+	s storage.IStorage,
+	target string,
+	policy *batch.Policy,
+	opts ...converter.Func[In, string],
+) *Storage[In] {
+	c, err := NewBatched(s, target, policy, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
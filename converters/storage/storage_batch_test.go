@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/concurrentloop"
+	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/etler/v3/batch"
+)
+
+// bulkStorage is a stub storage with bulk support, recording its chunks.
+// A second DAL memory storage per test binary would panic on expvar
+// re-registration, so a stub is used instead.
+type bulkStorage struct {
+	storage.IStorage
+
+	mu     sync.Mutex
+	chunks [][]batch.Document
+}
+
+func (s *bulkStorage) BulkWrite(ctx context.Context, op batch.Op, target string, docs []batch.Document) ([]error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chunks = append(s.chunks, docs)
+
+	errs := make([]error, len(docs))
+
+	for i, doc := range docs {
+		if doc.Item == "bad" {
+			errs[i] = errors.New("rejected")
+		}
+	}
+
+	return errs, nil
+}
+
+// Happy path: items converted concurrently, as a stage does, are written in
+// bulk, each getting its own ID, and error.
+func TestNewBatched(t *testing.T) {
+	s := &bulkStorage{}
+
+	c := MustBatched[string](s, "test", batch.Must(batch.WithMaxItems(2), batch.WithLinger(10*time.Millisecond)))
+
+	in := []string{"a", "b", "bad", "d", "e"}
+
+	var (
+		mu   sync.Mutex
+		errs = map[string]error{}
+	)
+
+	ids, _ := concurrentloop.Map(context.Background(), in, func(ctx context.Context, item string) (string, error) {
+		id, err := c.Run(ctx, item)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		errs[item] = err
+
+		return id, nil
+	})
+
+	assert.NoError(t, errs["a"])
+	assert.ErrorContains(t, errs["bad"], "rejected")
+
+	written := 0
+
+	for _, chunk := range s.chunks {
+		assert.LessOrEqual(t, len(chunk), 2)
+
+		written += len(chunk)
+	}
+
+	assert.Equal(t, len(in), written)
+
+	// IDs are unique.
+	seen := map[string]bool{}
+
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+
+		assert.False(t, seen[id])
+
+		seen[id] = true
+	}
+
+	assert.Len(t, seen, len(in)-1)
+}
+
+// Bad path: a policy without linger is rejected.
+func TestNewBatched_invalid(t *testing.T) {
+	_, err := NewBatched[string](&bulkStorage{}, "test", batch.Must(batch.WithMaxItems(2)))
+	require.Error(t, err)
+}
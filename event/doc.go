@@ -0,0 +1,27 @@
+// Package event provides the lifecycle events of pipelines, and their
+// components, and the bus they're published to.
+//
+// Every pipeline has a bus (`IPipeline.GetEventBus`), with any number of
+// subscribers (`Bus.Subscribe`, or `pipeline.WithSubscriber`), each
+// handling all events, or only some types. Runs publish, in order:
+//
+//   - RunStarted, RunFinished, RunFailed, or RunInterrupted, for the
+//     pipeline;
+//   - StageStarted, StageFinished, StageFailed, or StageInterrupted, for
+//     each stage;
+//   - ProcessorStarted, ProcessorFinished, ProcessorFailed, or
+//     ProcessorInterrupted, for each processor;
+//   - ConverterFinished, ConverterFailed, or ConverterInterrupted, for each
+//     converted item;
+//   - LoaderFinished, LoaderFailed, or LoaderInterrupted, for the extract
+//     step.
+//
+// Interrupted events are published instead of failed ones when a run
+// drains: they carry the interruption as their error, but aren't failures.
+//
+// Pausing, and resuming, a pipeline, or one of its stages, or processors,
+// publish Paused, and Resumed. Events carry the run ID, pipeline, component,
+// task ID, duration, item counts, and error, as relevant. Handlers run in
+// the publishing goroutine: they should be quick, and hand slow work, e.g.,
+// alerting, off.
+package event
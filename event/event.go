@@ -0,0 +1,238 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/etler/v3/internal/shared"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Type of an event.
+type Type string
+
+// Lifecycle events.
+const (
+	RunStarted     Type = "run.started"
+	RunFinished    Type = "run.finished"
+	RunFailed      Type = "run.failed"
+	RunInterrupted Type = "run.interrupted"
+
+	StageStarted     Type = "stage.started"
+	StageFinished    Type = "stage.finished"
+	StageFailed      Type = "stage.failed"
+	StageInterrupted Type = "stage.interrupted"
+
+	ProcessorStarted     Type = "processor.started"
+	ProcessorFinished    Type = "processor.finished"
+	ProcessorFailed      Type = "processor.failed"
+	ProcessorInterrupted Type = "processor.interrupted"
+
+	// ConverterFinished, ConverterFailed, and ConverterInterrupted, are
+	// emitted for each item.
+	ConverterFinished    Type = "converter.finished"
+	ConverterFailed      Type = "converter.failed"
+	ConverterInterrupted Type = "converter.interrupted"
+
+	LoaderFinished    Type = "loader.finished"
+	LoaderFailed      Type = "loader.failed"
+	LoaderInterrupted Type = "loader.interrupted"
+
+	Paused  Type = "paused"
+	Resumed Type = "resumed"
+)
+
+// Event is a lifecycle event of a pipeline, or one of its components.
+type Event struct {
+	// Type of the event.
+	Type Type `json:"type"`
+
+	// Time of the event.
+	Time time.Time `json:"time"`
+
+	// RunID is the ID of the pipeline run, if any.
+	RunID string `json:"runID,omitempty"`
+
+	// Pipeline is the name of the pipeline.
+	Pipeline string `json:"pipeline"`
+
+	// Component is the type of the component, e.g., "stage".
+	Component string `json:"component"`
+
+	// Name of the component.
+	Name string `json:"name"`
+
+	// TaskID is the ID of the task being run, if any.
+	TaskID string `json:"taskID,omitempty"`
+
+	// Duration of the run, for finished, failed, and interrupted events.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// ItemsIn is the count of items received.
+	ItemsIn int `json:"itemsIn,omitempty"`
+
+	// ItemsOut is the count of items returned, for finished events.
+	ItemsOut int `json:"itemsOut,omitempty"`
+
+	// Err is the error, for failed, and interrupted, events.
+	Err error `json:"-"`
+
+	// Error is the message of `Err`, kept for serialization.
+	Error string `json:"error,omitempty"`
+}
+
+// Handler handles events.
+type Handler func(ctx context.Context, e Event)
+
+// subscriber is a handler, and the types it handles, all if empty.
+type subscriber struct {
+	id      uint64
+	handler Handler
+	types   []Type
+}
+
+// Bus delivers the events published to it to its subscribers. It's safe
+// for concurrent use. Publishing to a nil bus is a no-op.
+type Bus struct {
+	mu          sync.RWMutex
+	nextID      uint64
+	subscribers []subscriber
+}
+
+// scope is what events published through a context are about.
+type scope struct {
+	bus      *Bus
+	runID    string
+	pipeline string
+	taskID   string
+}
+
+// scopeCtxKey is the key of the scope in a context.
+type scopeCtxKey struct{}
+
+//////
+// Methods.
+//////
+
+// Subscribe registers `h` for the events of `types`, or all if none. The
+// returned function unsubscribes it.
+func (b *Bus) Subscribe(h Handler, types ...Type) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+
+	id := b.nextID
+
+	b.subscribers = append(b.subscribers, subscriber{id: id, handler: h, types: types})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.subscribers = slices.DeleteFunc(b.subscribers, func(s subscriber) bool {
+			return s.id == id
+		})
+	}
+}
+
+// Publish delivers `e` to the subscribers of its type, in subscription
+// order, in the caller's goroutine. Handlers should be quick, and hand slow
+// work off, as they hold up the publishing component.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	if e.Err != nil && e.Error == "" {
+		e.Error = e.Err.Error()
+	}
+
+	b.mu.RLock()
+	subscribers := slices.Clone(b.subscribers)
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		if len(s.types) == 0 || slices.Contains(s.types, e.Type) {
+			s.handler(ctx, e)
+		}
+	}
+}
+
+//////
+// Exported functions.
+//////
+
+// ContextWithBus returns a copy of `ctx` publishing events of the run
+// `runID` of the pipeline named `pipeline` to `b`.
+func ContextWithBus(ctx context.Context, b *Bus, runID, pipeline string) context.Context {
+	return context.WithValue(ctx, scopeCtxKey{}, scope{bus: b, runID: runID, pipeline: pipeline})
+}
+
+// ContextWithTask returns a copy of `ctx` whose events are about the task
+// `taskID`.
+func ContextWithTask(ctx context.Context, taskID string) context.Context {
+	s, ok := ctx.Value(scopeCtxKey{}).(scope)
+	if !ok {
+		return ctx
+	}
+
+	s.taskID = taskID
+
+	return context.WithValue(ctx, scopeCtxKey{}, s)
+}
+
+// Publish publishes `e` to the bus in `ctx`, if any, filling in the run ID,
+// pipeline, and task ID, if not set.
+func Publish(ctx context.Context, e Event) {
+	s, ok := ctx.Value(scopeCtxKey{}).(scope)
+	if !ok {
+		return
+	}
+
+	if e.RunID == "" {
+		e.RunID = s.runID
+	}
+
+	if e.Pipeline == "" {
+		e.Pipeline = s.pipeline
+	}
+
+	if e.TaskID == "" {
+		e.TaskID = s.taskID
+	}
+
+	s.bus.Publish(ctx, e)
+}
+
+// Outcome returns `finished`, `interrupted` if `err` is because the run
+// drains, or `failed` if `err` is any other error.
+func Outcome(err error, finished, failed, interrupted Type) Type {
+	switch {
+	case err == nil:
+		return finished
+	case errors.Is(err, shared.ErrInterrupted):
+		return interrupted
+	default:
+		return failed
+	}
+}
+
+//////
+// Factory.
+//////
+
+// NewBus returns a new bus, without subscribers.
+func NewBus() *Bus {
+	return &Bus{}
+}
@@ -0,0 +1,70 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+)
+
+func TestBus(t *testing.T) {
+	b := NewBus()
+
+	var all, failures []Event
+
+	unsubscribe := b.Subscribe(func(_ context.Context, e Event) { all = append(all, e) })
+	b.Subscribe(func(_ context.Context, e Event) { failures = append(failures, e) }, StageFailed, RunFailed)
+
+	ctx := ContextWithTask(ContextWithBus(context.Background(), b, "run-1", "pipeline"), "task-1")
+
+	// Happy path: scoped, and filtered.
+	Publish(ctx, Event{Type: StageStarted, Component: "stage", Name: "s"})
+	Publish(ctx, Event{Type: Outcome(errors.New("boom"), StageFinished, StageFailed, StageInterrupted), Component: "stage", Name: "s", Err: errors.New("boom")})
+
+	require.Len(t, all, 2)
+	require.Len(t, failures, 1)
+
+	assert.Equal(t, "run-1", all[0].RunID)
+	assert.Equal(t, "pipeline", all[0].Pipeline)
+	assert.Equal(t, "task-1", all[0].TaskID)
+	assert.False(t, all[0].Time.IsZero())
+
+	assert.Equal(t, StageFailed, failures[0].Type)
+	assert.Equal(t, "boom", failures[0].Error)
+
+	// Happy path: explicit fields aren't overridden.
+	Publish(ctx, Event{Type: StageStarted, TaskID: "task-2"})
+	assert.Equal(t, "task-2", all[2].TaskID)
+
+	// Happy path: unsubscribed.
+	unsubscribe()
+
+	Publish(ctx, Event{Type: RunFailed})
+
+	assert.Len(t, all, 3)
+	assert.Len(t, failures, 2)
+
+	assert.Equal(t, RunFinished, Outcome(nil, RunFinished, RunFailed, RunInterrupted))
+
+	// Edge case: a drain isn't a failure.
+	assert.Equal(t, RunInterrupted, Outcome(fmt.Errorf("stage: %w", shared.ErrInterrupted), RunFinished, RunFailed, RunInterrupted))
+}
+
+// Edge case: without a bus, nothing is published, nor panics.
+func TestPublish_noBus(t *testing.T) {
+	ctx := ContextWithTask(context.Background(), "task")
+
+	assert.NotPanics(t, func() {
+		Publish(ctx, Event{Type: RunStarted})
+
+		var b *Bus
+
+		b.Publish(ctx, Event{Type: RunStarted})
+
+		Publish(ContextWithBus(ctx, nil, "run", "pipeline"), Event{Type: RunStarted})
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/thalesfsp/etler/v3/internal/logging"
+	"github.com/thalesfsp/etler/v3/tracing"
 	"github.com/thalesfsp/status"
 	"github.com/thalesfsp/sypl/v2"
 	"github.com/thalesfsp/sypl/v2/fields"
@@ -94,70 +95,43 @@ func logAndMetrics(
 	}
 }
 
-// Trace will trace an operation. It uses the existing TX otherwise it fallback
+// Trace will trace an operation, through the tracer set in the `tracing`
+// package. With Elastic APM, it uses the existing TX otherwise it fallback
 // creating a new TX then it creates a new span within the TX.
 //
-// NOTE: It's up to the developer to call `span.End()`.
-//
-// Note on TX and Span naming:
-//
-// The name and type of a transaction and span depend on the specific operation
-// being performed by the request. Here's an example of how you might name and
-// type a transaction and span for an incoming request to insert data into a db:
-//
-// Transaction:
-//
-// - Name: "post-user"
-// - Type: "request.post.user"
-//
-// Span:
-//
-// - Name: "insert-user"
-// - Type: "db.sql.insert"
-//
-// In this example, the tx was named "post-user" to describe the operation being
-// performed "request.post.user".
-//
-// For the span, it was named "insert-user" to describe a specific operation
-// being performed by the span. It was also categorized as "db.sql.insert",
-// which indicates that it involves a database operation.
-//
-// If the span type contains two dots, they are assumed to separate the type and
-// subtype parts of the span type. The action will not be set in this case.
-//
-// For example, if you use a span type of "db.sql.insert", this indicates that
-// the span represents a database operation of type "db", subtype "sql", and
-// action "insert". The StartSpan() method will automatically parse the span
-// type string and set the appropriate values for the Type, Subtype, and Action
-// fields of the SpanData object.
+// The span carries the attributes inherited from `ctx`, and the name of the
+// component, e.g., "etler.stage": "clean", which is also inherited by the
+// spans started under the returned context.
 //
-// If you use a span type of "db.sql", this indicates that the span represents
-// a database operation of type "db" and subtype "sql". The Action field of
-// the SpanData object will be left blank in this case.
+// NOTE: It's up to the developer to call `span.End()`.
 //
-// If you use a span type of "db", this indicates that the span represents a
-// generic database operation of type "db". Both the Subtype and Action fields
-// of the SpanData object will be left blank in this case.
+// See `TXFromCtx` for notes on TX and Span naming.
 func Trace(
 	ctx context.Context,
 	what, nameOf string,
 	operation status.Status,
 	l sypl.ISypl,
 	metric *expvar.Int,
-) (context.Context, *apm.Span) {
-	tx := TXFromCtx(ctx, nameOf, what)
-
-	ctx = apm.ContextWithTransaction(ctx, tx)
+) (context.Context, tracing.Span) {
+	ctx = tracing.ContextWithAttributes(ctx, tracing.Component(what, nameOf))
 
-	sType := fmt.Sprintf("%s.%s.%s", what, nameOf, operation)
-
-	span, ctx := apm.StartSpan(
+	ctx, span := tracing.GetTracer().Start(
 		ctx,
 		fmt.Sprintf("%s.%s", nameOf, operation),
-		sType,
+		fmt.Sprintf("%s.%s.%s", what, nameOf, operation),
 	)
 
+	span.SetAttributes(tracing.AttributesFromContext(ctx)...)
+
 	logAndMetrics(ctx, what, nameOf, operation, l, metric)
 
 	return ctx, span
 }
+
+// Annotate sets `attrs` on `span`, and returns a copy of `ctx` carrying them,
+// so they're also set on the spans started under it, e.g., the task ID.
+func Annotate(ctx context.Context, span tracing.Span, attrs ...tracing.Attribute) context.Context {
+	span.SetAttributes(attrs...)
+
+	return tracing.ContextWithAttributes(ctx, attrs...)
+}
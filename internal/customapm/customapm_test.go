@@ -10,6 +10,7 @@ import (
 	"github.com/thalesfsp/customerror/v2"
 	"github.com/thalesfsp/etler/v3/internal/logging"
 	"github.com/thalesfsp/etler/v3/internal/metrics"
+	"github.com/thalesfsp/etler/v3/tracing"
 	"github.com/thalesfsp/status"
 	"go.elastic.co/apm"
 )
@@ -29,6 +30,66 @@ func TestTrace_createsTransactionAndCountsMetric(t *testing.T) {
 	assert.Equal(t, int64(1), metric.Value())
 }
 
+// fakeSpan records the attributes set on it.
+type fakeSpan struct {
+	attributes map[string]any
+	failed     bool
+}
+
+func (s *fakeSpan) End() {}
+
+func (s *fakeSpan) SetAttributes(attrs ...tracing.Attribute) {
+	for _, attr := range attrs {
+		s.attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *fakeSpan) Fail() { s.failed = true }
+
+// fakeTracer records the spans it starts.
+type fakeTracer struct {
+	tracing.NoopTracer
+
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, _, _ string) (context.Context, tracing.Span) {
+	span := &fakeSpan{attributes: map[string]any{}}
+
+	t.spans = append(t.spans, span)
+
+	return ctx, span
+}
+
+// Happy path: spans carry the names of the components they run under, and
+// the annotations of their ancestors.
+func TestTrace_attributes(t *testing.T) {
+	tracer := &fakeTracer{}
+
+	tracing.SetTracer(tracer)
+	defer tracing.SetTracer(nil)
+
+	ctx, _ := Trace(context.Background(), "pipeline", "p", status.Runnning, nil, nil)
+	ctx = Annotate(ctx, tracer.spans[0], tracing.String(tracing.KeyTaskID, "task-1"))
+
+	_, span := Trace(ctx, "stage", "s", status.Runnning, nil, nil)
+	span.SetAttributes(tracing.Int(tracing.KeyItemsIn, 2))
+
+	require.Len(t, tracer.spans, 2)
+
+	assert.Equal(t, map[string]any{
+		"etler.pipeline":  "p",
+		tracing.KeyTaskID: "task-1",
+	}, tracer.spans[0].attributes)
+
+	assert.Equal(t, map[string]any{
+		"etler.pipeline":   "p",
+		"etler.stage":      "s",
+		tracing.KeyTaskID:  "task-1",
+		tracing.KeyItemsIn: 2,
+	}, tracer.spans[1].attributes)
+}
+
 // Edge case: an existing transaction in the context is reused; nil logger and
 // nil metric are tolerated.
 func TestTrace_reusesTransaction_nilLoggerAndMetric(t *testing.T) {
@@ -73,7 +134,7 @@ func TestTraceError_returnsOriginalAndCounts(t *testing.T) {
 	got := TraceError(ctx, wrapped, l, failed)
 
 	// NOTE: read the outcome BEFORE End() — the span data is released after.
-	assert.Equal(t, string(Failure), span.Outcome)
+	assert.Equal(t, string(Failure), apm.SpanFromContext(ctx).Outcome)
 
 	span.End()
 
@@ -6,10 +6,10 @@ import (
 	"expvar"
 
 	"github.com/thalesfsp/etler/v3/internal/logging"
+	"github.com/thalesfsp/etler/v3/tracing"
 	"github.com/thalesfsp/sypl/v2"
 	"github.com/thalesfsp/sypl/v2/fields"
 	"github.com/thalesfsp/sypl/v2/level"
-	"go.elastic.co/apm"
 )
 
 // Outcome is the outcome of a span. It can be either success or failure.
@@ -28,8 +28,8 @@ func (o Outcome) String() string {
 }
 
 // TraceError is a helper function to trace an error. It will log the error
-// with the APM fields, and tell APM that it was an error. It will also set
-// the span outcome to failure.
+// with the APM fields, and tell the tracer, see the `tracing` package, that it
+// was an error. It will also set the span outcome to failure.
 func TraceError(
 	ctx context.Context,
 	err error,
@@ -48,17 +48,13 @@ func TraceError(
 	// APM.
 	//////
 
-	// Get the current span from the context, if any, set the outcome.
-	span := apm.SpanFromContext(ctx)
-	if span != nil {
-		span.Outcome = string(Failure)
-	}
+	// Tells the tracer that it was an error. It also flags the current span,
+	// if any, as failed.
+	tracing.GetTracer().CaptureError(ctx, err)
 
 	originalError := err
 
-	// Unwrap any nested errorcatalog. By default, apm.CaptureError() does not
-	// automatically unwrap nested errors or extract any additional context or
-	// metadata from the error.
+	// Unwrap any nested errorcatalog, logging the root cause.
 	for {
 		unwrapped := errors.Unwrap(err)
 		if unwrapped == nil {
@@ -68,9 +64,6 @@ func TraceError(
 		err = unwrapped
 	}
 
-	// Tells APM that it that was an error.
-	apm.CaptureError(ctx, err).Send()
-
 	//////
 	// Logging
 	//////
@@ -86,3 +79,14 @@ func TraceError(
 
 	return originalError
 }
+
+// TraceTimeout flags the current span, if any, as timed out. APM outcomes are
+// limited to success, failure and unknown, so the outcome is failure, and the
+// timeout is told apart by the `timeout` attribute.
+func TraceTimeout(ctx context.Context) {
+	if span := tracing.GetTracer().SpanFromContext(ctx); span != nil {
+		span.Fail()
+
+		span.SetAttributes(tracing.Bool(tracing.KeyTimeout, true))
+	}
+}
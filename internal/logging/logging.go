@@ -4,11 +4,11 @@ import (
 	"context"
 	"sync"
 
+	"github.com/thalesfsp/etler/v3/tracing"
 	"github.com/thalesfsp/sypl/v2"
 	"github.com/thalesfsp/sypl/v2/fields"
 	"github.com/thalesfsp/sypl/v2/level"
 	"github.com/thalesfsp/sypl/v2/processor"
-	"go.elastic.co/apm"
 )
 
 //////
@@ -67,24 +67,18 @@ func Get() *Logger {
 	return singletonLogger
 }
 
-// ToAPM adds the required APM fields enabling log correlation.
+// ToAPM adds the required APM fields enabling log correlation, through the
+// tracer set in the `tracing` package, e.g., "trace.id" for Elastic APM, or
+// "trace_id" for OpenTelemetry.
 //
-// NOTE: It expects the `apm.Transaction` to be in the context.
+// NOTE: It expects the trace to be in the context.
 func ToAPM(ctx context.Context, f fields.Fields) fields.Fields {
 	if f == nil {
 		f = fields.Fields{}
 	}
 
-	tx := apm.TransactionFromContext(ctx)
-	if tx != nil {
-		traceContext := tx.TraceContext()
-
-		f["trace.id"] = traceContext.Trace.String()
-		f["transaction.id"] = traceContext.Span.String()
-
-		if span := apm.SpanFromContext(ctx); span != nil {
-			f["span.id"] = span.TraceContext().Span.String()
-		}
+	for k, v := range tracing.GetTracer().CorrelationFields(ctx) {
+		f[k] = v
 	}
 
 	return f
@@ -0,0 +1,379 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/thalesfsp/customerror/v2"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+const (
+	// DefaultMetricHistogramLabel is the default label for a histogram
+	// metric.
+	DefaultMetricHistogramLabel = "histogram"
+
+	// BucketsEnvVar overrides the histograms' default buckets:
+	// comma-separated upper bounds, in milliseconds, e.g., "10,100,1000".
+	BucketsEnvVar = "ETLER_METRICS_BUCKETS"
+
+	// PercentilesEnvVar overrides the default percentiles reported by
+	// histograms: comma-separated, between 0 and 100, e.g., "50,90,99.9".
+	PercentilesEnvVar = "ETLER_METRICS_PERCENTILES"
+
+	// MaxPercentile is the highest percentile.
+	MaxPercentile = 100
+)
+
+var (
+	// DefaultBuckets are the default upper bounds of histograms, in
+	// milliseconds.
+	DefaultBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+	// DefaultPercentiles are the default percentiles reported by histograms.
+	DefaultPercentiles = []float64{50, 90, 99}
+)
+
+// Histogram counts observations, e.g., durations, into buckets. It's safe
+// for concurrent use, and implements `expvar.Var`.
+type Histogram struct {
+	buckets     []float64
+	percentiles []float64
+
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramFunc allows to specify histogram's options.
+type HistogramFunc func(h *Histogram) *Histogram
+
+// Bucket of a histogram.
+type Bucket struct {
+	// UpperBound of the bucket, inclusive.
+	UpperBound float64 `json:"upperBound"`
+
+	// Count of observations lower than, or equal to, the upper bound.
+	Count uint64 `json:"count"`
+}
+
+// Snapshot of a histogram.
+type Snapshot struct {
+	// Buckets, cumulative, not including the implicit `+Inf` one.
+	Buckets []Bucket `json:"buckets"`
+
+	// Count of observations.
+	Count uint64 `json:"count"`
+
+	// Sum of observations.
+	Sum float64 `json:"sum"`
+
+	// Percentiles, estimated from the buckets, by percentile, e.g., "p99".
+	Percentiles map[string]float64 `json:"percentiles"`
+}
+
+//////
+// Helpers.
+//////
+
+// checkFloats returns the sorted, deduplicated, `values`, or an error if
+// any isn't between `lower` and `upper`.
+func checkFloats(name string, values []float64, lower, upper float64) ([]float64, error) {
+	for _, v := range values {
+		if math.IsNaN(v) || v < lower || v > upper {
+			return nil, customerror.NewInvalidError(
+				fmt.Sprintf("%s %v, expected between %v and %v", name, v, lower, upper),
+			)
+		}
+	}
+
+	values = slices.Clone(values)
+
+	slices.Sort(values)
+
+	return slices.Compact(values), nil
+}
+
+// floatsFromEnv returns the comma-separated, sorted, values of the `name`
+// env var, or `fallback` if not set. Invalid values are an error.
+func floatsFromEnv(name string, fallback []float64, lower, upper float64) ([]float64, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback, nil
+	}
+
+	values := []float64{}
+
+	for _, s := range strings.Split(raw, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, customerror.NewInvalidError(fmt.Sprintf("%s %q", name, raw), customerror.WithError(err))
+		}
+
+		values = append(values, v)
+	}
+
+	return checkFloats(name, values, lower, upper)
+}
+
+// PercentileKey returns the key of percentile `p`, e.g., "p99", or "p99.9".
+func PercentileKey(p float64) string {
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+//////
+// Built-in options.
+//////
+
+// WithBuckets sets the upper bounds of the histogram's buckets, in
+// milliseconds. If none, the default ones are used.
+func WithBuckets(buckets ...float64) HistogramFunc {
+	return func(h *Histogram) *Histogram {
+		if len(buckets) > 0 {
+			h.buckets = buckets
+		}
+
+		return h
+	}
+}
+
+// WithPercentiles sets the percentiles reported by the histogram, between 0
+// and 100. If none, the default ones are used.
+func WithPercentiles(percentiles ...float64) HistogramFunc {
+	return func(h *Histogram) *Histogram {
+		if len(percentiles) > 0 {
+			h.percentiles = percentiles
+		}
+
+		return h
+	}
+}
+
+//////
+// Methods.
+//////
+
+// Observe records `v`.
+func (h *Histogram) Observe(v float64) {
+	i, _ := slices.BinarySearch(h.buckets, v)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.counts[i]++
+	h.count++
+	h.sum += v
+}
+
+// Count returns the count of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.count
+}
+
+// Sum returns the sum of observations.
+func (h *Histogram) Sum() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.sum
+}
+
+// Percentile estimates percentile `p`, between 0 and 100, interpolating
+// linearly within the bucket it falls into. Observations above the highest
+// bucket are estimated at its upper bound.
+func (h *Histogram) Percentile(p float64) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.percentile(p)
+}
+
+// percentile is `Percentile`, with the lock held.
+func (h *Histogram) percentile(p float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	rank := math.Max(p, 0) / 100 * float64(h.count)
+
+	var cumulative uint64
+
+	for i, c := range h.counts {
+		if c == 0 || float64(cumulative+c) < rank {
+			cumulative += c
+
+			continue
+		}
+
+		// Above the highest bucket.
+		if i == len(h.buckets) {
+			break
+		}
+
+		lower := 0.0
+
+		if i > 0 {
+			lower = h.buckets[i-1]
+		}
+
+		return lower + (h.buckets[i]-lower)*(rank-float64(cumulative))/float64(c)
+	}
+
+	if len(h.buckets) == 0 {
+		return 0
+	}
+
+	return h.buckets[len(h.buckets)-1]
+}
+
+// Snapshot returns a consistent snapshot of the histogram.
+func (h *Histogram) Snapshot() Snapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := Snapshot{
+		Buckets:     make([]Bucket, 0, len(h.buckets)),
+		Count:       h.count,
+		Sum:         h.sum,
+		Percentiles: make(map[string]float64, len(h.percentiles)),
+	}
+
+	var cumulative uint64
+
+	for i, upperBound := range h.buckets {
+		cumulative += h.counts[i]
+
+		s.Buckets = append(s.Buckets, Bucket{UpperBound: upperBound, Count: cumulative})
+	}
+
+	for _, p := range h.percentiles {
+		s.Percentiles[PercentileKey(p)] = h.percentile(p)
+	}
+
+	return s
+}
+
+// Metrics returns the count, sum, and percentiles of the histogram, keyed
+// by `prefix`, e.g., "durationCount", "durationSum", and "durationP99".
+func (h *Histogram) Metrics(prefix string) map[string]string {
+	s := h.Snapshot()
+
+	m := map[string]string{
+		prefix + "Count": strconv.FormatUint(s.Count, 10),
+		prefix + "Sum":   strconv.FormatFloat(s.Sum, 'f', -1, 64),
+	}
+
+	for key, v := range s.Percentiles {
+		m[prefix+"P"+key[1:]] = strconv.FormatFloat(v, 'f', 3, 64)
+	}
+
+	return m
+}
+
+// String implements the `expvar.Var` interface.
+func (h *Histogram) String() string {
+	b, err := json.Marshal(h.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+
+	return string(b)
+}
+
+//////
+// Exported functions.
+//////
+
+// NewHistogram creates a new histogram, with the buckets, and percentiles
+// set through options, else through `BucketsEnvVar`, and
+// `PercentilesEnvVar`, else the defaults. Invalid buckets, percentiles, or
+// env vars, are an error. Unless publishing is enabled (see PublishEnvVar),
+// the histogram is NOT registered globally. A reused published histogram is
+// returned as-is.
+func NewHistogram(name string, opts ...HistogramFunc) (*Histogram, error) {
+	buckets, err := floatsFromEnv(BucketsEnvVar, DefaultBuckets, 0, math.MaxFloat64)
+	if err != nil {
+		return nil, err
+	}
+
+	percentiles, err := floatsFromEnv(PercentilesEnvVar, DefaultPercentiles, 0, MaxPercentile)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Histogram{
+		buckets:     buckets,
+		percentiles: percentiles,
+	}
+
+	for _, opt := range opts {
+		h = opt(h)
+	}
+
+	if h.buckets, err = checkFloats("buckets", h.buckets, 0, math.MaxFloat64); err != nil {
+		return nil, err
+	}
+
+	if h.percentiles, err = checkFloats("percentiles", h.percentiles, 0, MaxPercentile); err != nil {
+		return nil, err
+	}
+
+	h.counts = make([]uint64, len(h.buckets)+1)
+
+	if !shouldPublish() {
+		return h, nil
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	// Reuse: do NOT clobber the shared metric's observations.
+	if v, ok := expvar.Get(name).(*Histogram); ok {
+		return v, nil
+	}
+
+	expvar.Publish(name, h)
+
+	return h, nil
+}
+
+// NewHistogramWithPattern creates a new histogram with a consistent naming
+// pattern where `t` is the type of the entity, `subject` is the subject of
+// the metric, and `metric` is what's observed, e.g., "duration".
+func NewHistogramWithPattern(t, subject, metric string, opts ...HistogramFunc) (*Histogram, error) {
+	return NewHistogram(
+		fmt.Sprintf(
+			"%s.%s.%s.%s.%s",
+			Name,
+			t,
+			subject,
+			metric,
+			DefaultMetricHistogramLabel,
+		),
+		opts...,
+	)
+}
+
+// Throughput returns `items` per second, over the `sum` of durations, in
+// milliseconds, they took.
+func Throughput(items int64, sum float64) float64 {
+	if sum <= 0 {
+		return 0
+	}
+
+	return float64(items) / (sum / 1000)
+}
@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Setenv(BucketsEnvVar, "100, 10")
+	t.Setenv(PercentilesEnvVar, "50,99.9")
+
+	h, err := NewHistogram("etler.test.histogram.duration.histogram")
+	require.NoError(t, err)
+
+	// Edge case: no observations.
+	assert.Zero(t, h.Percentile(50))
+
+	// Happy path: concurrent observations.
+	var wg sync.WaitGroup
+
+	for _, v := range []float64{2, 4, 6, 8, 10, 20, 40, 60, 80, 500} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			h.Observe(v)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, uint64(10), h.Count())
+	assert.InDelta(t, 730, h.Sum(), 0.001)
+
+	s := h.Snapshot()
+	assert.Equal(t, []Bucket{{UpperBound: 10, Count: 5}, {UpperBound: 100, Count: 9}}, s.Buckets)
+
+	// Percentiles are interpolated within their bucket.
+	assert.InDelta(t, 10, h.Percentile(50), 0.001)
+	assert.InDelta(t, 55, h.Percentile(70), 0.001)
+
+	// Edge case: above the highest bucket.
+	assert.InDelta(t, 100, h.Percentile(99.9), 0.001)
+
+	assert.Equal(t, map[string]float64{"p50": 10, "p99.9": 100}, s.Percentiles)
+
+	m := h.Metrics("duration")
+	assert.Equal(t, "10", m["durationCount"])
+	assert.Equal(t, "730", m["durationSum"])
+	assert.Equal(t, "10.000", m["durationP50"])
+	assert.Equal(t, "100.000", m["durationP99.9"])
+
+	// `expvar.Var`: JSON.
+	var decoded Snapshot
+
+	assert.NoError(t, json.Unmarshal([]byte(h.String()), &decoded))
+	assert.Equal(t, s, decoded)
+
+	// Bad path: invalid env vars are reported.
+	t.Setenv(BucketsEnvVar, "10,ten")
+
+	_, err = NewHistogram("etler.test.histogram.invalid.histogram")
+	assert.ErrorContains(t, err, BucketsEnvVar)
+
+	t.Setenv(BucketsEnvVar, "")
+	t.Setenv(PercentilesEnvVar, "101")
+
+	_, err = NewHistogram("etler.test.histogram.invalid.histogram")
+	assert.ErrorContains(t, err, PercentilesEnvVar)
+}
+
+func TestHistogram_options(t *testing.T) {
+	t.Setenv(BucketsEnvVar, "100, 10")
+
+	// Happy path: options override the env vars, which override the
+	// defaults.
+	h, err := NewHistogram("etler.test.histogram.options.histogram", WithBuckets(50, 5, 50), WithPercentiles(95))
+	require.NoError(t, err)
+
+	s := h.Snapshot()
+	assert.Equal(t, []Bucket{{UpperBound: 5}, {UpperBound: 50}}, s.Buckets)
+	assert.Equal(t, map[string]float64{"p95": 0}, s.Percentiles)
+
+	h, err = NewHistogram("etler.test.histogram.options.histogram", WithBuckets(), WithPercentiles())
+	require.NoError(t, err)
+
+	s = h.Snapshot()
+	assert.Equal(t, []Bucket{{UpperBound: 10}, {UpperBound: 100}}, s.Buckets)
+	assert.Len(t, s.Percentiles, len(DefaultPercentiles))
+
+	// Bad path: invalid options.
+	_, err = NewHistogram("etler.test.histogram.options.histogram", WithBuckets(-1))
+	assert.ErrorContains(t, err, "buckets")
+
+	_, err = NewHistogram("etler.test.histogram.options.histogram", WithPercentiles(50, 101))
+	assert.ErrorContains(t, err, "percentiles")
+}
+
+func TestHistogram_publish(t *testing.T) {
+	t.Setenv(PublishEnvVar, "true")
+
+	h1, err := NewHistogramWithPattern("test", "publish-histogram", "duration")
+	require.NoError(t, err)
+
+	h2, err := NewHistogramWithPattern("test", "publish-histogram", "duration")
+	require.NoError(t, err)
+
+	assert.Same(t, h1, h2, "same pattern name must reuse the same histogram")
+
+	assert.NotNil(t, expvar.Get("etler.test.publish-histogram.duration.histogram"))
+
+	typ, subject, metric, ok := ParsePattern("etler.test.publish-histogram.duration.histogram")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"test", "publish-histogram", "duration"}, []string{typ, subject, metric})
+}
+
+func TestThroughput(t *testing.T) {
+	assert.InDelta(t, 50, Throughput(10, 200), 0.001)
+
+	// Edge case: nothing observed.
+	assert.Zero(t, Throughput(10, 0))
+}
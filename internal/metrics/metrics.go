@@ -4,6 +4,7 @@ import (
 	"expvar"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/thalesfsp/status"
@@ -114,3 +115,24 @@ func NewIntWithPattern(t, subject string, status status.Status) *expvar.Int {
 		),
 	)
 }
+
+// ParsePattern parses a name built by `NewIntWithPattern`,
+// `NewStringWithPattern`, or `NewHistogramWithPattern`, into the type of the entity, its subject, and the
+// metric. Subjects may contain dots. `ok` is false for any other name.
+func ParsePattern(name string) (t, subject, metric string, ok bool) {
+	rest, found := strings.CutPrefix(name, Name+".")
+	if !found {
+		return "", "", "", false
+	}
+
+	for _, label := range []string{DefaultMetricCounterLabel, DefaultMetricHistogramLabel} {
+		rest = strings.TrimSuffix(rest, "."+label)
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) < 3 {
+		return "", "", "", false
+	}
+
+	return parts[0], strings.Join(parts[1:len(parts)-1], "."), parts[len(parts)-1], true
+}
@@ -74,3 +74,26 @@ func TestMetrics_publishReuse_doesNotResetValues(t *testing.T) {
 	assert.Equal(t, "something-live", s2.Value(),
 		"constructing a same-named entity must not reset the shared string")
 }
+
+func TestParsePattern(t *testing.T) {
+	// Happy path: int, and string metrics.
+	typ, subject, metric, ok := ParsePattern("etler.pipeline.my-pipeline.done.counter")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"pipeline", "my-pipeline", "done"}, []string{typ, subject, metric})
+
+	typ, subject, metric, ok = ParsePattern("etler.stage.my-stage.status")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"stage", "my-stage", "status"}, []string{typ, subject, metric})
+
+	// Edge case: subject with dots.
+	_, subject, metric, ok = ParsePattern("etler.processor.users.v2.duration.counter")
+	assert.True(t, ok)
+	assert.Equal(t, "users.v2", subject)
+	assert.Equal(t, "duration", metric)
+
+	// Bad path: other names.
+	for _, name := range []string{"memstats", "etler.pipeline.done", "other.pipeline.p.done.counter"} {
+		_, _, _, ok = ParsePattern(name)
+		assert.False(t, ok, name)
+	}
+}
@@ -0,0 +1,108 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+
+	"github.com/thalesfsp/status"
+	"github.com/thalesfsp/sypl/v2"
+	"github.com/thalesfsp/sypl/v2/level"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// ErrInterrupted is the cause of runs stopped by a drain, before their next
+// processor, as opposed to failed.
+var ErrInterrupted = errors.New("interrupted")
+
+// drainCtxKey is the context key under which a `Drainer` travels.
+type drainCtxKey struct{}
+
+// Drainer signals a run to drain: what's in flight finishes, and nothing new
+// starts.
+type Drainer struct {
+	once    sync.Once
+	drainCh chan struct{}
+}
+
+//////
+// Methods.
+//////
+
+// Drain signals the run to drain. Safe for concurrent use. Idempotent.
+func (d *Drainer) Drain() {
+	d.once.Do(func() {
+		close(d.drainCh)
+	})
+}
+
+// Draining returns whether the run is draining.
+func (d *Drainer) Draining() bool {
+	select {
+	case <-d.drainCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Done returns a channel closed once the run is draining.
+func (d *Drainer) Done() <-chan struct{} {
+	return d.drainCh
+}
+
+//////
+// Exported functions.
+//////
+
+// OnInterruptedHandler deals with observability when a processor, stage, or
+// pipeline, stops because its run drains. Unlike `OnErrorHandler`, it isn't
+// counted as a failure, but as an interruption, and it returns
+// `ErrInterrupted`.
+func OnInterruptedHandler(
+	iMetric IMetrics,
+	counterInterrupted *expvar.Int,
+	l sypl.ISypl,
+) error {
+	iMetric.GetStatus().Set(status.Interrupted.String())
+
+	if counterInterrupted != nil {
+		counterInterrupted.Add(1)
+	}
+
+	l.PrintlnWithOptions(level.Debug, status.Interrupted.String())
+
+	return ErrInterrupted
+}
+
+//////
+// Factory and context plumbing.
+//////
+
+// NewDrainer returns a new drainer, not draining.
+func NewDrainer() *Drainer {
+	return &Drainer{drainCh: make(chan struct{})}
+}
+
+// ContextWithDrainer returns a copy of `ctx` carrying `d`.
+func ContextWithDrainer(ctx context.Context, d *Drainer) context.Context {
+	return context.WithValue(ctx, drainCtxKey{}, d)
+}
+
+// DrainerFromCtx extracts the `Drainer` from `ctx`, or nil if none.
+func DrainerFromCtx(ctx context.Context) *Drainer {
+	d, _ := ctx.Value(drainCtxKey{}).(*Drainer)
+
+	return d
+}
+
+// IsDraining returns whether the run of `ctx` is draining.
+func IsDraining(ctx context.Context) bool {
+	d := DrainerFromCtx(ctx)
+
+	return d != nil && d.Draining()
+}
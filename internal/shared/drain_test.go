@@ -0,0 +1,58 @@
+package shared
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Happy path: draining is idempotent, and visible through the context.
+func TestDrainer(t *testing.T) {
+	assert.False(t, IsDraining(context.Background()))
+	assert.Nil(t, DrainerFromCtx(context.Background()))
+
+	d := NewDrainer()
+	ctx := ContextWithDrainer(context.Background(), d)
+
+	assert.Same(t, d, DrainerFromCtx(ctx))
+	assert.False(t, IsDraining(ctx))
+
+	d.Drain()
+	d.Drain()
+
+	assert.True(t, IsDraining(ctx))
+
+	select {
+	case <-d.Done():
+	default:
+		t.Fatal("Done isn't closed once draining")
+	}
+}
+
+// Edge case: a paused run drains right away.
+func TestWaitResumed_draining(t *testing.T) {
+	pc := NewPauseController()
+	pc.Pause()
+
+	d := NewDrainer()
+
+	ctx := ContextWithDrainer(ContextWithPause(context.Background(), pc), d)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- WaitResumed(ctx)
+	}()
+
+	d.Drain()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, ErrInterrupted)
+	case <-time.After(2 * time.Second):
+		t.Fatal("WaitResumed did not unblock on drain")
+	}
+}
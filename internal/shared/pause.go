@@ -13,8 +13,8 @@ import (
 type pauseCtxKey struct{}
 
 // PauseController coordinates pausing and resuming the processors running
-// under a single pipeline. Each pipeline owns its own controller, so pausing
-// one pipeline does not affect any other.
+// under a single pipeline, run, stage, or processor. Each of them owns its
+// own controller, so pausing one does not affect any other.
 type PauseController struct {
 	mu       sync.Mutex
 	paused   bool
@@ -49,8 +49,13 @@ func (pc *PauseController) Resume() {
 	}
 }
 
-// Paused returns whether the controller is currently paused.
+// Paused returns whether the controller is currently paused. A nil
+// controller never is.
 func (pc *PauseController) Paused() bool {
+	if pc == nil {
+		return false
+	}
+
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
 
@@ -90,16 +95,90 @@ func NewPauseController() *PauseController {
 	return &PauseController{}
 }
 
-// ContextWithPause returns a copy of `ctx` carrying `pc`. The pipeline uses
-// this to make its controller visible to the processors it runs.
+// ContextWithPause returns a copy of `ctx` carrying `pc`, on top of the
+// controllers already carried, e.g., the pipeline's, and the run's. Processors
+// pause while any of them is paused. A nil `pc` is ignored.
 func ContextWithPause(ctx context.Context, pc *PauseController) context.Context {
-	return context.WithValue(ctx, pauseCtxKey{}, pc)
+	if pc == nil {
+		return ctx
+	}
+
+	inherited := PausesFromCtx(ctx)
+
+	pcs := make([]*PauseController, 0, len(inherited)+1)
+	pcs = append(pcs, inherited...)
+	pcs = append(pcs, pc)
+
+	return context.WithValue(ctx, pauseCtxKey{}, pcs)
 }
 
-// PauseFromCtx extracts the `PauseController` from `ctx`, or nil if none —
-// e.g., a processor running standalone, outside a pipeline.
+// PauseFromCtx extracts the innermost `PauseController` from `ctx`, or nil if
+// none — e.g., a processor running standalone, outside a pipeline.
 func PauseFromCtx(ctx context.Context) *PauseController {
-	pc, _ := ctx.Value(pauseCtxKey{}).(*PauseController)
+	pcs := PausesFromCtx(ctx)
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	return pcs[len(pcs)-1]
+}
+
+// PausesFromCtx extracts all the controllers carried by `ctx`, outermost
+// first.
+func PausesFromCtx(ctx context.Context) []*PauseController {
+	pcs, _ := ctx.Value(pauseCtxKey{}).([]*PauseController)
+
+	return pcs
+}
+
+// IsPaused returns whether any of the controllers carried by `ctx` is paused.
+func IsPaused(ctx context.Context) bool {
+	for _, pc := range PausesFromCtx(ctx) {
+		if pc.Paused() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WaitResumed blocks while any of the controllers carried by `ctx` is paused.
+// It returns nil once all of them are resumed, `ErrInterrupted` if the run
+// drains first — a paused run doesn't hold up its drain — or the context
+// error if the context is done first.
+func WaitResumed(ctx context.Context) error {
+	if !IsPaused(ctx) {
+		return nil
+	}
+
+	waitCtx := ctx
+
+	if d := DrainerFromCtx(ctx); d != nil {
+		var cancel context.CancelFunc
+
+		waitCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		go func() {
+			select {
+			case <-d.Done():
+				cancel()
+			case <-waitCtx.Done():
+			}
+		}()
+	}
+
+	for IsPaused(ctx) {
+		for _, pc := range PausesFromCtx(ctx) {
+			if err := pc.Wait(waitCtx); err != nil {
+				if IsDraining(ctx) && ctx.Err() == nil {
+					return ErrInterrupted
+				}
+
+				return err
+			}
+		}
+	}
 
-	return pc
+	return nil
 }
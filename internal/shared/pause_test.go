@@ -116,3 +116,47 @@ func TestPauseController_contextPlumbing(t *testing.T) {
 
 	assert.Same(t, pc, PauseFromCtx(ctx))
 }
+
+// Happy path: the controllers carried by a context combine — waiters are
+// released once all of them are resumed.
+func TestPauseController_combined(t *testing.T) {
+	pipelinePC, runPC := NewPauseController(), NewPauseController()
+
+	ctx := ContextWithPause(ContextWithPause(context.Background(), pipelinePC), runPC)
+
+	assert.Equal(t, []*PauseController{pipelinePC, runPC}, PausesFromCtx(ctx))
+	assert.Same(t, runPC, PauseFromCtx(ctx))
+	assert.False(t, IsPaused(ctx))
+	require.NoError(t, WaitResumed(ctx))
+
+	// Edge case: a nil controller is ignored.
+	assert.Len(t, PausesFromCtx(ContextWithPause(ctx, nil)), 2)
+
+	pipelinePC.Pause()
+	runPC.Pause()
+
+	assert.True(t, IsPaused(ctx))
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- WaitResumed(ctx)
+	}()
+
+	pipelinePC.Resume()
+
+	select {
+	case <-done:
+		t.Fatal("a waiter was released while the run is paused")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	runPC.Resume()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("WaitResumed did not unblock once all controllers resumed")
+	}
+}
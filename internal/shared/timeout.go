@@ -0,0 +1,100 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+
+	"github.com/thalesfsp/etler/v3/internal/customapm"
+	"github.com/thalesfsp/sypl/v2"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// ErrTimeout is the cause of failures due to a component's own timeout, as
+// opposed to the caller's context being done.
+var ErrTimeout = errors.New("timed out")
+
+//////
+// Helpers.
+//////
+
+// RunWithTimeout calls `fn`, failing with `ErrTimeout` if it doesn't return
+// within `timeout`. A non-positive `timeout` means no timeout.
+//
+// NOTE: `fn`'s context is canceled on timeout, but `fn` isn't waited for:
+// a function ignoring its context keeps running in the background, but no
+// longer stalls the caller.
+func RunWithTimeout[T any](
+	ctx context.Context,
+	timeout time.Duration,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	timeoutCtx, cancel := context.WithTimeoutCause(ctx, timeout, ErrTimeout)
+	defer cancel()
+
+	type result struct {
+		out T
+		err error
+	}
+
+	// Buffered: an abandoned `fn` never blocks on sending.
+	resultCh := make(chan result, 1)
+
+	go func() {
+		out, err := fn(timeoutCtx)
+
+		resultCh <- result{out: out, err: err}
+	}()
+
+	var r result
+
+	select {
+	case r = <-resultCh:
+	case <-timeoutCtx.Done():
+		// The caller's context is done: same as without a timeout, it's up
+		// to `fn` to return.
+		if !errors.Is(context.Cause(timeoutCtx), ErrTimeout) {
+			r = <-resultCh
+
+			break
+		}
+
+		return *new(T), fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+
+	// `fn` returned an error because it timed out.
+	if r.err != nil && errors.Is(context.Cause(timeoutCtx), ErrTimeout) {
+		return r.out, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+
+	return r.out, r.err
+}
+
+// OnTimeoutHandler deals with observability when a processor, converter or
+// loader times out. It's `OnErrorHandler`, plus counting the interruption,
+// and flagging the span as timed out.
+func OnTimeoutHandler(
+	tracedContext context.Context,
+	iMetric IMetrics,
+	counterInterrupted *expvar.Int,
+	l sypl.ISypl,
+	err error,
+	message, t, name string,
+) error {
+	if counterInterrupted != nil {
+		counterInterrupted.Add(1)
+	}
+
+	customapm.TraceTimeout(tracedContext)
+
+	return OnErrorHandler(tracedContext, iMetric, l, err, message, t, name)
+}
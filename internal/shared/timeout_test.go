@@ -0,0 +1,79 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithTimeout(t *testing.T) {
+	// Happy path: no timeout.
+	out, err := RunWithTimeout(context.Background(), 0, func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, out)
+
+	// Happy path: within the timeout.
+	out, err = RunWithTimeout(context.Background(), time.Second, func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, out)
+
+	// Errors go through.
+	boom := errors.New("boom")
+
+	_, err = RunWithTimeout(context.Background(), time.Second, func(ctx context.Context) (int, error) {
+		return 0, boom
+	})
+	require.ErrorIs(t, err, boom)
+	assert.NotErrorIs(t, err, ErrTimeout)
+}
+
+// Bad path: a function ignoring its context no longer stalls the caller.
+func TestRunWithTimeout_hungFunction(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	start := time.Now()
+
+	_, err := RunWithTimeout(context.Background(), 20*time.Millisecond, func(ctx context.Context) (int, error) {
+		<-release
+
+		return 1, nil
+	})
+	require.ErrorIs(t, err, ErrTimeout)
+	assert.Contains(t, err.Error(), "20ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+// Bad path: a function honoring its context fails with `ErrTimeout`, not with
+// a context error.
+func TestRunWithTimeout_contextAwareFunction(t *testing.T) {
+	_, err := RunWithTimeout(context.Background(), 10*time.Millisecond, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	})
+	require.ErrorIs(t, err, ErrTimeout)
+	assert.NotErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// Edge case: the caller's context being done isn't a timeout.
+func TestRunWithTimeout_callerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := RunWithTimeout(ctx, time.Hour, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrTimeout)
+}
@@ -2,8 +2,11 @@ package loader
 
 import (
 	"context"
+	"expvar"
+	"time"
 
 	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/retry"
 )
 
 // ILoader defines what a `Conveter` must do.
@@ -18,6 +21,25 @@ type ILoader[In, Out any] interface {
 	// SetOnFinished sets the `OnFinished` function.
 	SetOnFinished(onFinished OnFinished[In, Out])
 
+	// GetCounterInterrupted returns the `CounterInterrupted` metric.
+	GetCounterInterrupted() *expvar.Int
+
+	// GetTimeout returns the `Timeout` of a run.
+	GetTimeout() time.Duration
+
+	// SetTimeout sets the `Timeout` of a run.
+	SetTimeout(timeout time.Duration)
+
+	// GetRetry returns the `Retry` policy.
+	GetRetry() *retry.Policy
+
+	// SetRetry sets the `Retry` policy.
+	SetRetry(policy *retry.Policy)
+
+	// SetHistogram sets the buckets, and percentiles, of the duration
+	// histogram, replacing it.
+	SetHistogram(buckets, percentiles []float64) error
+
 	// Run the stage function.
 	Run(ctx context.Context, in In) (Out, error)
 }
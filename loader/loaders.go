@@ -2,13 +2,19 @@ package loader
 
 import (
 	"context"
+	"errors"
 	"expvar"
+	"fmt"
+	"maps"
 	"time"
 
+	"github.com/thalesfsp/etler/v3/event"
 	"github.com/thalesfsp/etler/v3/internal/customapm"
 	"github.com/thalesfsp/etler/v3/internal/logging"
 	"github.com/thalesfsp/etler/v3/internal/metrics"
 	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/retry"
+	"github.com/thalesfsp/etler/v3/tracing"
 	"github.com/thalesfsp/status"
 	"github.com/thalesfsp/sypl/v2"
 	"github.com/thalesfsp/sypl/v2/level"
@@ -22,6 +28,10 @@ import (
 // Type of the entity.
 const Type = "loader"
 
+// ErrTimeout is the cause of runs failing because they exceeded their
+// `Timeout`.
+var ErrTimeout = shared.ErrTimeout
+
 // Load is a function that converts the data (`in`). It returns the
 // converted data and any errors that occurred during conversion.
 type Load[In, Out any] func(ctx context.Context, in In) (out Out, err error)
@@ -40,19 +50,34 @@ type Loader[In, Out any] struct {
 	// Name of the stage.
 	Name string `json:"name" validate:"required"`
 
+	// Timeout of a run. If set, runs taking longer fail with `ErrTimeout`.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// Retry policy. If set, failed runs are retried.
+	Retry *retry.Policy `json:"retry,omitempty"`
+
 	// OnFinished is the function that is called when a processor finishes its
 	// execution.
 	OnFinished OnFinished[In, Out] `json:"-"`
 
+	// Buckets of the duration histogram, in milliseconds. See
+	// `SetHistogram`.
+	Buckets []float64 `json:"buckets,omitempty"`
+
+	// Percentiles reported by the duration histogram. See `SetHistogram`.
+	Percentiles []float64 `json:"percentiles,omitempty"`
+
 	// Metrics.
-	CounterCreated *expvar.Int `json:"counterCreated"`
-	CounterRunning *expvar.Int `json:"counterRunning"`
-	CounterFailed  *expvar.Int `json:"counterFailed"`
-	CounterDone    *expvar.Int `json:"counterDone"`
-
-	CreatedAt time.Time      `json:"createdAt"`
-	Duration  *expvar.Int    `json:"duration"`
-	Status    *expvar.String `json:"status"`
+	CounterCreated     *expvar.Int `json:"counterCreated"`
+	CounterRunning     *expvar.Int `json:"counterRunning"`
+	CounterFailed      *expvar.Int `json:"counterFailed"`
+	CounterDone        *expvar.Int `json:"counterDone"`
+	CounterInterrupted *expvar.Int `json:"counterInterrupted"`
+
+	CreatedAt         time.Time          `json:"createdAt"`
+	Duration          *expvar.Int        `json:"duration"`
+	DurationHistogram *metrics.Histogram `json:"durationHistogram"`
+	Status            *expvar.String     `json:"status"`
 }
 
 //////
@@ -99,6 +124,11 @@ func (c *Loader[In, Out]) GetCounterDone() *expvar.Int {
 	return c.CounterDone
 }
 
+// GetCounterInterrupted returns the `CounterInterrupted` metric.
+func (c *Loader[In, Out]) GetCounterInterrupted() *expvar.Int {
+	return c.CounterInterrupted
+}
+
 // GetStatus returns the `Status` metric.
 func (c *Loader[In, Out]) GetStatus() *expvar.String {
 	return c.Status
@@ -124,21 +154,101 @@ func (c *Loader[In, Out]) GetDuration() *expvar.Int {
 	return c.Duration
 }
 
+// GetDurationHistogram returns the `DurationHistogram` of the loader.
+func (c *Loader[In, Out]) GetDurationHistogram() *metrics.Histogram {
+	return c.DurationHistogram
+}
+
+// SetHistogram sets the buckets, in milliseconds, and the percentiles of the
+// duration histogram, replacing it. If none, the defaults, or those set
+// through the `ETLER_METRICS_BUCKETS`, and `ETLER_METRICS_PERCENTILES`, env
+// vars, are used.
+func (c *Loader[In, Out]) SetHistogram(buckets, percentiles []float64) error {
+	c.Buckets = buckets
+	c.Percentiles = percentiles
+
+	durationHistogram, err := c.newDurationHistogram()
+	if err != nil {
+		return err
+	}
+
+	c.DurationHistogram = durationHistogram
+
+	return nil
+}
+
+// newDurationHistogram returns a new duration histogram, with the loader's
+// buckets, and percentiles.
+func (c *Loader[In, Out]) newDurationHistogram() (*metrics.Histogram, error) {
+	return metrics.NewHistogramWithPattern(
+		Type,
+		c.GetName(),
+		"duration",
+		metrics.WithBuckets(c.Buckets...),
+		metrics.WithPercentiles(c.Percentiles...),
+	)
+}
+
 // GetMetrics returns the stage's metrics.
 func (c *Loader[In, Out]) GetMetrics() map[string]string {
-	return map[string]string{
-		"createdAt":      c.GetCreatedAt().String(),
-		"counterCreated": c.GetCounterCreated().String(),
-		"counterDone":    c.GetCounterDone().String(),
-		"counterFailed":  c.GetCounterFailed().String(),
-		"counterRunning": c.GetCounterRunning().String(),
-		"duration":       c.GetDuration().String(),
-		"status":         c.GetStatus().String(),
+	m := map[string]string{
+		"createdAt":          c.GetCreatedAt().String(),
+		"counterCreated":     c.GetCounterCreated().String(),
+		"counterDone":        c.GetCounterDone().String(),
+		"counterFailed":      c.GetCounterFailed().String(),
+		"counterInterrupted": c.GetCounterInterrupted().String(),
+		"counterRunning":     c.GetCounterRunning().String(),
+		"duration":           c.GetDuration().String(),
+		"status":             c.GetStatus().String(),
 	}
+
+	maps.Copy(m, c.GetDurationHistogram().Metrics("duration"))
+
+	return m
+}
+
+// GetTimeout returns the `Timeout` of a run.
+func (c *Loader[In, Out]) GetTimeout() time.Duration {
+	return c.Timeout
+}
+
+// SetTimeout sets the `Timeout` of a run.
+func (c *Loader[In, Out]) SetTimeout(timeout time.Duration) {
+	c.Timeout = timeout
+}
+
+// GetRetry returns the `Retry` policy.
+func (c *Loader[In, Out]) GetRetry() *retry.Policy {
+	return c.Retry
+}
+
+// SetRetry sets the `Retry` policy.
+func (c *Loader[In, Out]) SetRetry(policy *retry.Policy) {
+	c.Retry = policy
 }
 
-// Run the conversion function.
+// Run the load function. With a retry policy set, failed runs are retried,
+// each attempt traced and counted on its own.
 func (c *Loader[In, Out]) Run(ctx context.Context, in In) (Out, error) {
+	now := time.Now()
+
+	out, err := retry.Do(ctx, c.GetRetry(), func(ctx context.Context, attempt int) (Out, error) {
+		return c.run(ctx, in, attempt)
+	})
+
+	event.Publish(ctx, event.Event{
+		Type:      event.Outcome(err, event.LoaderFinished, event.LoaderFailed, event.LoaderInterrupted),
+		Component: Type,
+		Name:      c.GetName(),
+		Duration:  time.Since(now),
+		Err:       err,
+	})
+
+	return out, err
+}
+
+// run is a single attempt of `Run`.
+func (c *Loader[In, Out]) run(ctx context.Context, in In, attempt int) (Out, error) {
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -153,17 +263,100 @@ func (c *Loader[In, Out]) Run(ctx context.Context, in In) (Out, error) {
 	)
 	defer span.End()
 
+	if attempt > 1 {
+		span.SetAttributes(tracing.Int(tracing.KeyAttempt, attempt))
+
+		c.GetLogger().PrintlnWithOptions(level.Debug, fmt.Sprintf("retrying, attempt %d", attempt))
+	}
+
 	c.GetStatus().Set(status.Runnning.String())
 
 	c.GetLogger().PrintlnWithOptions(level.Debug, status.Runnning.String())
 
+	// NOTE: Built-in loaders also pause between the items they load, see
+	// `pause.Checkpoint`.
+	if shared.IsPaused(tracedContext) {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		// Update the status.
+		c.GetStatus().Set(status.Paused.String())
+
+		// Notifiy user.
+		c.GetLogger().Tracelnf("Loader %s is paused. Waiting to be resumed...", c.GetName())
+
+		// Blocks until resumed, or until the run drains, or the context is
+		// done.
+		err := shared.WaitResumed(tracedContext)
+		if errors.Is(err, shared.ErrInterrupted) {
+			//////
+			// Observability: tracing, metrics, status, logging, etc.
+			//////
+
+			return *new(Out), shared.OnInterruptedHandler(c, c.GetCounterInterrupted(), c.GetLogger())
+		}
+
+		if err != nil {
+			//////
+			// Observability: tracing, metrics, status, logging, etc.
+			//////
+
+			return *new(Out), shared.OnErrorHandler(
+				tracedContext,
+				c,
+				c.GetLogger(),
+				err,
+				"process",
+				Type,
+				c.GetName(),
+			)
+		}
+
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		c.GetStatus().Set(status.Runnning.String())
+	}
+
 	//////
 	// Run conversor.
 	//////
 
 	now := time.Now()
 
-	out, err := c.Func(tracedContext, in)
+	out, err := shared.RunWithTimeout(tracedContext, c.GetTimeout(), func(ctx context.Context) (Out, error) {
+		return c.Func(ctx, in)
+	})
+
+	// The function reached a `pause.Checkpoint` while paused, and the run
+	// drains.
+	if errors.Is(err, shared.ErrInterrupted) {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return *new(Out), shared.OnInterruptedHandler(c, c.GetCounterInterrupted(), c.GetLogger())
+	}
+
+	if errors.Is(err, shared.ErrTimeout) {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return *new(Out), shared.OnTimeoutHandler(
+			tracedContext,
+			c,
+			c.GetCounterInterrupted(),
+			c.GetLogger(),
+			err,
+			"process",
+			Type,
+			c.GetName(),
+		)
+	}
+
 	if err != nil {
 		//////
 		// Observability: tracing, metrics, status, logging, etc.
@@ -196,7 +389,11 @@ func (c *Loader[In, Out]) Run(ctx context.Context, in In) (Out, error) {
 	}
 
 	// Set duration.
-	c.GetDuration().Set(time.Since(now).Milliseconds())
+	elapsed := time.Since(now)
+
+	c.GetDuration().Set(elapsed.Milliseconds())
+
+	c.GetDurationHistogram().Observe(float64(elapsed.Microseconds()) / 1000)
 
 	// Print the stage's status.
 	c.GetLogger().PrintWithOptions(
@@ -206,6 +403,7 @@ func (c *Loader[In, Out]) Run(ctx context.Context, in In) (Out, error) {
 		sypl.WithField("counterCreated", c.GetCounterCreated().String()),
 		sypl.WithField("counterDone", c.GetCounterDone().String()),
 		sypl.WithField("counterFailed", c.GetCounterFailed().String()),
+		sypl.WithField("counterInterrupted", c.GetCounterInterrupted().String()),
 		sypl.WithField("counterRunning", c.GetCounterRunning().String()),
 		sypl.WithField("duration", c.GetDuration().String()),
 		sypl.WithField("status", c.GetStatus().String()),
@@ -235,7 +433,9 @@ func New[In, Out any](
 		CounterCreated: metrics.NewIntWithPattern(Type, name, status.Created),
 		CounterDone:    metrics.NewIntWithPattern(Type, name, status.Done),
 		CounterFailed:  metrics.NewIntWithPattern(Type, name, status.Failed),
-		CounterRunning: metrics.NewIntWithPattern(Type, name, status.Runnning),
+
+		CounterInterrupted: metrics.NewIntWithPattern(Type, name, status.Interrupted),
+		CounterRunning:     metrics.NewIntWithPattern(Type, name, status.Runnning),
 
 		Duration: metrics.NewIntWithPattern(Type, name, "duration"),
 		Status:   metrics.NewStringWithPattern(Type, name, status.Name),
@@ -251,6 +451,13 @@ func New[In, Out any](
 		return nil, err
 	}
 
+	durationHistogram, err := c.newDurationHistogram()
+	if err != nil {
+		return nil, err
+	}
+
+	c.DurationHistogram = durationHistogram
+
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -0,0 +1,68 @@
+package loader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/pause"
+	"github.com/thalesfsp/status"
+)
+
+// Happy path: a load pauses at its checkpoints, and, if its run drains
+// meanwhile, is interrupted, not failed.
+func TestLoader_checkpoint(t *testing.T) {
+	pc := shared.NewPauseController()
+
+	d := shared.NewDrainer()
+
+	ctx := shared.ContextWithDrainer(shared.ContextWithPause(context.Background(), pc), d)
+
+	loaded := make(chan int, 3)
+
+	l, err := New(
+		"loader-checkpoint",
+		"pauses after the first item",
+		func(ctx context.Context, in []int) ([]int, error) {
+			for _, item := range in {
+				if err := pause.Checkpoint(ctx); err != nil {
+					return nil, err
+				}
+
+				loaded <- item
+
+				pc.Pause()
+			}
+
+			return in, nil
+		},
+	)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := l.Run(ctx, []int{1, 2, 3})
+
+		done <- err
+	}()
+
+	assert.Equal(t, 1, <-loaded)
+
+	select {
+	case <-loaded:
+		t.Fatal("loaded an item while paused")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	d.Drain()
+
+	require.ErrorIs(t, <-done, shared.ErrInterrupted)
+
+	assert.Equal(t, status.Interrupted.String(), l.GetStatus().Value())
+	assert.Equal(t, int64(1), l.GetCounterInterrupted().Value())
+	assert.Equal(t, int64(0), l.GetCounterFailed().Value())
+}
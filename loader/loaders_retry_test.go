@@ -0,0 +1,42 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/retry"
+)
+
+// Bad path: errors not worth retrying aren't retried.
+func TestLoader_retry_classifier(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	notFound := errors.New("not found")
+
+	calls := 0
+
+	l, err := New(
+		"loader-retry",
+		"fails permanently",
+		func(ctx context.Context, in string) ([]string, error) {
+			calls++
+
+			return nil, notFound
+		},
+		WithRetry[string, []string](retry.Must(5, retry.Constant(0), retry.WithClassifier(func(err error) bool {
+			return !errors.Is(err, notFound)
+		}))),
+	)
+	require.NoError(t, err)
+
+	_, err = l.Run(ctx, "in")
+	require.ErrorIs(t, err, notFound)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, int64(1), l.GetCounterFailed().Value())
+}
@@ -0,0 +1,40 @@
+package loader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Bad path: a hung load fails with `ErrTimeout`, counted as interrupted;
+// without a timeout, nothing is interrupted.
+func TestLoader_timeout(t *testing.T) {
+	l, err := New(
+		"loader-timeout",
+		"hangs until canceled",
+		func(ctx context.Context, in string) ([]string, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+				return []string{in}, nil
+			}
+		},
+		WithTimeout[string, []string](10*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	_, err = l.Run(context.Background(), "in")
+	require.ErrorIs(t, err, ErrTimeout)
+	assert.Equal(t, int64(1), l.GetCounterInterrupted().Value())
+
+	l.SetTimeout(0)
+
+	out, err := l.Run(context.Background(), "in")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"in"}, out)
+	assert.Equal(t, int64(1), l.GetCounterInterrupted().Value())
+}
@@ -0,0 +1,138 @@
+package csv
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// DecodeError is returned when a CSV value can't be coerced into its field.
+type DecodeError struct {
+	// Line of the record, starting at 1.
+	Line int `json:"line"`
+
+	// Column name, after renaming.
+	Column string `json:"column"`
+
+	// Raw value, after trimming.
+	Raw string `json:"raw"`
+
+	// Err is the coercion error.
+	Err error `json:"-"`
+}
+
+//////
+// Methods.
+//////
+
+// Error implements the `error` interface.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("line %d, column %q, value %q: %v", e.Line, e.Column, e.Raw, e.Err)
+}
+
+// Unwrap returns the coercion error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+//////
+// Helpers.
+//////
+
+// coerce sets `field` from its raw CSV value. Supported are strings, bools,
+// ints, uints, floats, `time.Time` (parsed with `timeLayout`), types
+// implementing `encoding.TextUnmarshaler`, and pointers to any of them,
+// which are nullable: an empty value leaves them nil. An empty value leaves
+// any other non-string field to its zero value.
+func coerce(field reflect.Value, raw, timeLayout string) error {
+	if field.Kind() == reflect.Pointer {
+		if raw == "" {
+			return nil
+		}
+
+		elem := reflect.New(field.Type().Elem())
+
+		if err := coerce(elem.Elem(), raw, timeLayout); err != nil {
+			return err
+		}
+
+		field.Set(elem)
+
+		return nil
+	}
+
+	if field.Kind() == reflect.String {
+		field.SetString(raw)
+
+		return nil
+	}
+
+	if raw == "" {
+		return nil
+	}
+
+	// NOTE: Checked before `encoding.TextUnmarshaler`, which `time.Time`
+	// implements, but only for RFC 3339.
+	if field.Type() == timeType {
+		t, err := time.Parse(timeLayout, raw)
+		if err != nil {
+			return err
+		}
+
+		field.Set(reflect.ValueOf(t))
+
+		return nil
+	}
+
+	if reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) {
+		u, _ := field.Addr().Interface().(encoding.TextUnmarshaler)
+
+		return u.UnmarshalText([]byte(raw))
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
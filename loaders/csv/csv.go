@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"reflect"
 
 	"github.com/thalesfsp/etler/v3/loader"
 	"github.com/thalesfsp/validation"
@@ -19,15 +20,27 @@ const Name = "csv"
 // CSV definition.
 type CSV[Out any] struct {
 	loader.ILoader[io.Reader, Out] `json:"loader" validate:"required"`
+
+	// Dialect of the CSV being loaded.
+	Dialect *Dialect `json:"dialect" validate:"required"`
 }
 
 //////
 // Factory.
 //////
 
-// New creates a new loader.
+// New creates a new loader, reading comma-delimited CSV with a header row.
 func New[Out any](
 	opts ...loader.Func[io.Reader, []Out],
+) (*CSV[[]Out], error) {
+	return NewWithDialect(NewDialect(), opts...)
+}
+
+// NewWithDialect creates a new loader, reading CSV according to `dialect`.
+// Records are decoded one at a time, as they're read.
+func NewWithDialect[Out any](
+	dialect *Dialect,
+	opts ...loader.Func[io.Reader, []Out],
 ) (*CSV[[]Out], error) {
 	// Enforces interface implementation.
 	var _ loader.ILoader[io.Reader, []Out] = (*CSV[[]Out])(nil)
@@ -36,12 +49,14 @@ func New[Out any](
 		Name,
 		fmt.Sprintf("%s %s", Name, loader.Type),
 		func(ctx context.Context, in io.Reader) ([]Out, error) {
-			out, err := Load[[]Out](in)
+			out, err := load(ctx, in, reflect.TypeFor[[]Out](), dialect)
 			if err != nil {
 				return nil, err
 			}
 
-			return out, nil
+			o, _ := out.Interface().([]Out)
+
+			return o, nil
 		},
 		opts...,
 	)
@@ -50,7 +65,8 @@ func New[Out any](
 	}
 
 	csv := &CSV[[]Out]{
-		conv,
+		ILoader: conv,
+		Dialect: dialect,
 	}
 
 	// NOTE: `opts` were already applied by `loader.New` — applying them
@@ -63,3 +79,29 @@ func New[Out any](
 
 	return csv, nil
 }
+
+// Must returns a new loader or panics if an error occurs.
+func Must[Out any](
+	opts ...loader.Func[io.Reader, []Out],
+) *CSV[[]Out] {
+	csv, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return csv
+}
+
+// MustWithDialect returns a new loader, reading CSV according to `dialect`,
+// or panics if an error occurs.
+func MustWithDialect[Out any](
+	dialect *Dialect,
+	opts ...loader.Func[io.Reader, []Out],
+) *CSV[[]Out] {
+	csv, err := NewWithDialect(dialect, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return csv
+}
@@ -42,9 +42,10 @@ func TestLoad_typeMismatch_returnsError(t *testing.T) {
 	assert.Error(t, err)
 }
 
-// Happy path: rows map to the target struct; tabs are stripped from values.
+// Happy path: rows map to the target struct; tabs are stripped from values,
+// when asked to.
 func TestLoad_happyPath_mapsRowsAndStripsTabs(t *testing.T) {
-	out, err := Load[[]auditRow](strings.NewReader("name,age\na\tlice,30\nbob,0\n"))
+	out, err := Load[[]auditRow](strings.NewReader("name,age\na\tlice,30\nbob,0\n"), WithStripTabs(true))
 	require.NoError(t, err)
 	require.Len(t, out, 2)
 
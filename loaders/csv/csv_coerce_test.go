@@ -0,0 +1,138 @@
+package csv
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// level implements `encoding.TextUnmarshaler`.
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+
+	return nil
+}
+
+type typedRow struct {
+	Name    string    `csv:"name"`
+	Age     int       `csv:"age"`
+	Small   int8      `csv:"small"`
+	Count   uint32    `csv:"count"`
+	Score   float64   `csv:"score"`
+	Active  bool      `csv:"active"`
+	Born    time.Time `csv:"born"`
+	Nick    *string   `csv:"nick"`
+	Rank    *int      `csv:"rank"`
+	Level   level     `csv:"level"`
+	Backup  *level    `csv:"backup"`
+	Ignored []string  `csv:"-"`
+}
+
+const typedHeader = "name,age,small,count,score,active,born,nick,rank,level,backup\n"
+
+// Happy path: values are coerced into their field's type.
+func TestLoad_coercion(t *testing.T) {
+	out, err := Load[[]typedRow](strings.NewReader(typedHeader +
+		"alice,30,-8,7,1.5,true,2024-03-01T10:00:00Z,ali,1,low,high\n" +
+		"bob,,,,,,,,,,\n",
+	))
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+
+	nick := "ali"
+	rank := 1
+	backup := level(2)
+
+	assert.Equal(t, typedRow{
+		Name:   "alice",
+		Age:    30,
+		Small:  -8,
+		Count:  7,
+		Score:  1.5,
+		Active: true,
+		Born:   time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		Nick:   &nick,
+		Rank:   &rank,
+		Level:  1,
+		Backup: &backup,
+	}, out[0])
+
+	// Edge case: empty values leave zero values, and nil pointers.
+	assert.Equal(t, typedRow{Name: "bob"}, out[1])
+}
+
+// Happy path: times are parsed with the dialect's layout.
+func TestLoad_timeLayout(t *testing.T) {
+	type row struct {
+		Day time.Time `csv:"day"`
+	}
+
+	out, err := Load[[]row](strings.NewReader("day\n01/03/2024\n"), WithTimeLayout("02/01/2006"))
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), out[0].Day)
+}
+
+// Bad path: failures report line, column and raw value.
+func TestLoad_coercionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+		column string
+		raw    string
+		target error
+	}{
+		{name: "int", record: "a,x1,,,,,,,,,", column: "age", raw: "x1", target: strconv.ErrSyntax},
+		{name: "int overflow", record: "a,,300,,,,,,,,", column: "small", raw: "300", target: strconv.ErrRange},
+		{name: "negative uint", record: "a,,,-1,,,,,,,", column: "count", raw: "-1", target: strconv.ErrSyntax},
+		{name: "float", record: "a,,,,1.2.3,,,,,,", column: "score", raw: "1.2.3", target: strconv.ErrSyntax},
+		{name: "bool", record: "a,,,,,maybe,,,,,", column: "active", raw: "maybe", target: strconv.ErrSyntax},
+		{name: "time", record: "a,,,,,,yesterday,,,,", column: "born", raw: "yesterday"},
+		{name: "pointer", record: "a,,,,,,,,one,,", column: "rank", raw: "one", target: strconv.ErrSyntax},
+		{name: "text unmarshaler", record: "a,,,,,,,,,medium,", column: "level", raw: "medium"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load[[]typedRow](strings.NewReader(typedHeader + "ok,1,,,,,,,,,\n" + tt.record + "\n"))
+			require.Error(t, err)
+
+			var decodeErr *DecodeError
+
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, 3, decodeErr.Line)
+			assert.Equal(t, tt.column, decodeErr.Column)
+			assert.Equal(t, tt.raw, decodeErr.Raw)
+			assert.Contains(t, err.Error(), "line 3")
+
+			if tt.target != nil {
+				assert.ErrorIs(t, err, tt.target)
+			}
+		})
+	}
+}
+
+// Bad path: unsupported field types fail, rather than being skipped.
+func TestLoad_unsupportedType(t *testing.T) {
+	type row struct {
+		Tags []string `csv:"tags"`
+	}
+
+	_, err := Load[[]row](strings.NewReader("tags\na\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported field type []string")
+}
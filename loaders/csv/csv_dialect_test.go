@@ -0,0 +1,181 @@
+package csv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dialectRow struct {
+	ID    int    `csv:"id"`
+	Name  string `json:"name"`
+	Email string
+}
+
+// Happy path: dialect options, one at a time.
+func TestLoad_dialect(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Func
+		want  []dialectRow
+	}{
+		{
+			name:  "default",
+			input: "id,name,Email\n1,alice,a@x\n",
+			want:  []dialectRow{{ID: 1, Name: "alice", Email: "a@x"}},
+		},
+		{
+			name:  "delimiter",
+			input: "id;name\n1;alice\n",
+			opts:  []Func{WithDelimiter(';')},
+			want:  []dialectRow{{ID: 1, Name: "alice"}},
+		},
+		{
+			name:  "comment",
+			input: "# export\nid,name\n# skipped\n1,alice\n",
+			opts:  []Func{WithComment('#')},
+			want:  []dialectRow{{ID: 1, Name: "alice"}},
+		},
+		{
+			name:  "lazy quotes",
+			input: "id,name\n1,al\"ice\n",
+			opts:  []Func{WithLazyQuotes(true)},
+			want:  []dialectRow{{ID: 1, Name: `al"ice`}},
+		},
+		{
+			name:  "header rename, case-insensitive match, unknown columns ignored",
+			input: "ID,Full Name,EMAIL,extra\n1,alice,a@x,ignored\n",
+			opts:  []Func{WithHeaders(map[string]string{"Full Name": "name"})},
+			want:  []dialectRow{{ID: 1, Name: "alice", Email: "a@x"}},
+		},
+		{
+			name:  "headerless",
+			input: "1,alice\n2,bob\n",
+			opts:  []Func{WithColumns("id", "name")},
+			want:  []dialectRow{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}},
+		},
+		{
+			name:  "skip rows, of any length",
+			input: "report,2024\nexported by,someone,today\nid,name\n1,alice\n",
+			opts:  []Func{WithSkipRows(2)},
+			want:  []dialectRow{{ID: 1, Name: "alice"}},
+		},
+		{
+			name:  "trim none keeps whitespace",
+			input: "id,name\n1, alice \n",
+			want:  []dialectRow{{ID: 1, Name: " alice "}},
+		},
+		{
+			name:  "trim leading",
+			input: "id,name\n1, alice \n",
+			opts:  []Func{WithTrim(TrimLeading)},
+			want:  []dialectRow{{ID: 1, Name: "alice "}},
+		},
+		{
+			name:  "trim space, values and headers",
+			input: " id , name \n 1 , alice \n",
+			opts:  []Func{WithTrim(TrimSpace)},
+			want:  []dialectRow{{ID: 1, Name: "alice"}},
+		},
+		{
+			name:  "tabs kept by default",
+			input: "id,name\n1,al\tice\n",
+			want:  []dialectRow{{ID: 1, Name: "al\tice"}},
+		},
+		{
+			name:  "tabs stripped",
+			input: "id,name\n1,al\tice\n",
+			opts:  []Func{WithStripTabs(true)},
+			want:  []dialectRow{{ID: 1, Name: "alice"}},
+		},
+		{
+			name:  "empty values leave zero values",
+			input: "id,name\n,alice\n",
+			want:  []dialectRow{{Name: "alice"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Load[[]dialectRow](strings.NewReader(tt.input), tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+// Happy path: rows can be pointers to structs.
+func TestLoad_pointerRows(t *testing.T) {
+	out, err := Load[[]*dialectRow](strings.NewReader("id,name\n1,alice\n"))
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, &dialectRow{ID: 1, Name: "alice"}, out[0])
+}
+
+// Happy path: the decoder streams records, one at a time.
+func TestDecoder_Decode(t *testing.T) {
+	d, err := NewDecoder[dialectRow](strings.NewReader("id,name\n1,alice\n2,bob\n"))
+	require.NoError(t, err)
+
+	row, err := d.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, dialectRow{ID: 1, Name: "alice"}, row)
+
+	row, err = d.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, dialectRow{ID: 2, Name: "bob"}, row)
+
+	_, err = d.Decode()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// Happy path: the loader reads according to its dialect.
+func TestNewWithDialect(t *testing.T) {
+	l, err := NewWithDialect[dialectRow](NewDialect(WithDelimiter('\t'), WithSkipRows(1)))
+	require.NoError(t, err)
+
+	out, err := l.Run(context.Background(), strings.NewReader("preamble\nid\tname\n1\talice\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []dialectRow{{ID: 1, Name: "alice"}}, out)
+}
+
+// Bad path: a record with the wrong number of values fails, with its line.
+func TestLoad_wrongValueCount(t *testing.T) {
+	_, err := Load[[]dialectRow](strings.NewReader("id,name\n1,alice\n2\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+	assert.Contains(t, err.Error(), "1 values, expected 2")
+}
+
+// Bad path: invalid dialects, and output types.
+func TestNewWithDialect_invalid(t *testing.T) {
+	_, err := NewWithDialect[dialectRow](NewDialect(WithTrim("all")))
+	require.Error(t, err)
+
+	_, err = NewWithDialect[dialectRow](NewDialect(WithSkipRows(-1)))
+	require.Error(t, err)
+
+	_, err = Load[[]int](strings.NewReader("id\n1\n"))
+	require.Error(t, err)
+
+	_, err = Load[dialectRow](strings.NewReader("id\n1\n"))
+	require.Error(t, err)
+}
+
+// Edge case: a done context stops loading.
+func TestNewWithDialect_contextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	l := MustWithDialect[dialectRow](NewDialect())
+
+	_, err := l.Run(ctx, strings.NewReader("id,name\n1,alice\n"))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "context canceled"))
+}
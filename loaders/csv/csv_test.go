@@ -23,7 +23,9 @@ func TestNew(t *testing.T) {
 
 	buf := new(strings.Builder)
 
-	csvLoader, err := New(
+	// Edge case: the indented content relies on tabs being stripped.
+	csvLoader, err := NewWithDialect(
+		NewDialect(WithStripTabs(true)),
 		loader.WithOnFinished(func(ctx context.Context, c loader.ILoader[io.Reader, []Test], originalIn io.Reader, convertedOut []Test) {
 			buf.WriteString(c.GetName() + " finished")
 		}),
@@ -0,0 +1,360 @@
+package csv
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/pause"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// decoder decodes CSV records, one at a time, into a struct type.
+type decoder struct {
+	dialect *Dialect
+	reader  *csv.Reader
+
+	// rowType is the struct type records decode into.
+	rowType reflect.Type
+
+	// ptr is true when rows are pointers to `rowType`.
+	ptr bool
+
+	// columns are the column names, after renaming.
+	columns []string
+
+	// fields are, per column, the index path of the field it fills, or nil
+	// if no field matches it.
+	fields [][]int
+
+	started bool
+}
+
+// Decoder decodes CSV records, one at a time, into `T`, a struct, or a
+// pointer to one. Memory use doesn't depend on the size of the input.
+type Decoder[T any] struct {
+	d *decoder
+}
+
+//////
+// Helpers.
+//////
+
+// tagName returns the name set by a struct tag, if any.
+func tagName(field reflect.StructField, key string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+
+	return name
+}
+
+// fieldPaths returns the index path of every field of `t` which can be
+// filled, keyed by name: the `csv` tag, else the `json` tag, else the field
+// name. Fields of embedded structs are promoted.
+func fieldPaths(t reflect.Type) map[string][]int {
+	paths := make(map[string][]int)
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || (field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		// Fields promoted through embedded pointers would need allocating.
+		if throughPointer(t, field.Index) {
+			continue
+		}
+
+		name := tagName(field, "csv")
+		if name == "" {
+			name = tagName(field, "json")
+		}
+
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		// Shallower fields win, as in Go's own promotion rules.
+		if _, ok := paths[name]; !ok {
+			paths[name] = field.Index
+		}
+	}
+
+	return paths
+}
+
+// throughPointer returns whether the field at `index` of `t` is reached
+// through an embedded pointer.
+func throughPointer(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		field := t.Field(i)
+
+		if field.Type.Kind() == reflect.Pointer {
+			return true
+		}
+
+		t = field.Type
+	}
+
+	return false
+}
+
+// clean applies the dialect's whitespace policies to a value.
+func (d *decoder) clean(value string) string {
+	if d.dialect.StripTabs {
+		value = strings.ReplaceAll(value, "\t", "")
+	}
+
+	if d.dialect.Trim == TrimSpace {
+		value = strings.TrimSpace(value)
+	}
+
+	return value
+}
+
+// start skips the dialect's rows, reads the header row, unless headerless,
+// and maps columns to fields.
+func (d *decoder) start() error {
+	d.started = true
+
+	for i := 0; i < d.dialect.SkipRows; i++ {
+		if _, err := d.reader.Read(); err != nil {
+			if errors.Is(err, io.EOF) && len(d.dialect.Columns) == 0 {
+				return customerror.NewMissingError("CSV header row")
+			}
+
+			if errors.Is(err, io.EOF) {
+				return io.EOF
+			}
+
+			return customerror.NewFailedToError("skip CSV rows", customerror.WithError(err))
+		}
+	}
+
+	columns := d.dialect.Columns
+
+	if len(columns) == 0 {
+		header, err := d.reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return customerror.NewMissingError("CSV header row")
+			}
+
+			return customerror.NewFailedToError("read CSV header row", customerror.WithError(err))
+		}
+
+		columns = make([]string, 0, len(header))
+
+		for _, h := range header {
+			columns = append(columns, d.clean(h))
+		}
+	}
+
+	paths := fieldPaths(d.rowType)
+
+	d.columns = make([]string, len(columns))
+	d.fields = make([][]int, len(columns))
+
+	for i, column := range columns {
+		if renamed, ok := d.dialect.Headers[column]; ok {
+			column = renamed
+		}
+
+		d.columns[i] = column
+
+		if path, ok := paths[column]; ok {
+			d.fields[i] = path
+
+			continue
+		}
+
+		for name, path := range paths {
+			if strings.EqualFold(name, column) {
+				d.fields[i] = path
+
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// decode decodes the next record. It returns `io.EOF` once the input is
+// exhausted.
+func (d *decoder) decode() (reflect.Value, error) {
+	if !d.started {
+		if err := d.start(); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	record, err := d.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return reflect.Value{}, io.EOF
+		}
+
+		return reflect.Value{}, customerror.NewFailedToError("read CSV record", customerror.WithError(err))
+	}
+
+	line, _ := d.reader.FieldPos(0)
+
+	if len(record) != len(d.columns) {
+		return reflect.Value{}, customerror.NewInvalidError(
+			fmt.Sprintf("CSV record on line %d, it has %d values, expected %d", line, len(record), len(d.columns)),
+		)
+	}
+
+	row := reflect.New(d.rowType).Elem()
+
+	for i, raw := range record {
+		if d.fields[i] == nil {
+			continue
+		}
+
+		value := d.clean(raw)
+
+		if err := coerce(row.FieldByIndex(d.fields[i]), value, d.dialect.TimeLayout); err != nil {
+			return reflect.Value{}, &DecodeError{
+				Line:   line,
+				Column: d.columns[i],
+				Raw:    value,
+				Err:    err,
+			}
+		}
+	}
+
+	if d.ptr {
+		return row.Addr(), nil
+	}
+
+	return row, nil
+}
+
+// load decodes every record of `in` into `outType`, a slice.
+func load(ctx context.Context, in io.Reader, outType reflect.Type, dialect *Dialect) (reflect.Value, error) {
+	if outType.Kind() != reflect.Slice {
+		return reflect.Value{}, customerror.NewInvalidError(
+			fmt.Sprintf("output type %s, it must be a slice", outType),
+		)
+	}
+
+	d, err := newDecoder(in, outType.Elem(), dialect)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	out := reflect.MakeSlice(outType, 0, 0)
+
+	for {
+		if err := pause.Checkpoint(ctx); err != nil {
+			return reflect.Value{}, err
+		}
+
+		row, err := d.decode()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out = reflect.Append(out, row)
+	}
+}
+
+//////
+// Methods.
+//////
+
+// Decode returns the next record. It returns `io.EOF` once the input is
+// exhausted.
+func (d *Decoder[T]) Decode() (T, error) {
+	row, err := d.d.decode()
+	if err != nil {
+		return *new(T), err
+	}
+
+	out, _ := row.Interface().(T)
+
+	return out, nil
+}
+
+//////
+// Exported functions.
+//////
+
+// Load decodes every record of `in` into `Out`, a slice of structs (or of
+// pointers to structs), according to the dialect set by `opts`.
+func Load[Out any](in io.Reader, opts ...Func) (Out, error) {
+	out, err := load(context.Background(), in, reflect.TypeFor[Out](), NewDialect(opts...))
+	if err != nil {
+		return *new(Out), err
+	}
+
+	o, _ := out.Interface().(Out)
+
+	return o, nil
+}
+
+//////
+// Factory.
+//////
+
+// newDecoder returns a new decoder of `in` into `rowType`.
+func newDecoder(in io.Reader, rowType reflect.Type, dialect *Dialect) (*decoder, error) {
+	ptr := rowType.Kind() == reflect.Pointer
+	if ptr {
+		rowType = rowType.Elem()
+	}
+
+	if rowType.Kind() != reflect.Struct {
+		return nil, customerror.NewInvalidError(
+			fmt.Sprintf("row type %s, it must be a struct, or a pointer to one", rowType),
+		)
+	}
+
+	reader := csv.NewReader(in)
+
+	reader.Comma = dialect.Delimiter
+	reader.Comment = dialect.Comment
+	reader.LazyQuotes = dialect.LazyQuotes
+	reader.TrimLeadingSpace = dialect.Trim == TrimLeading
+
+	// Value counts are checked against the header, not the first record:
+	// skipped rows may have any number of values.
+	reader.FieldsPerRecord = -1
+
+	// Values are copied into rows: the record slice can be reused.
+	reader.ReuseRecord = true
+
+	return &decoder{
+		dialect: dialect,
+		reader:  reader,
+		rowType: rowType,
+		ptr:     ptr,
+	}, nil
+}
+
+// NewDecoder returns a new decoder of `in` into `T`, a struct, or a pointer
+// to one, according to the dialect set by `opts`.
+func NewDecoder[T any](in io.Reader, opts ...Func) (*Decoder[T], error) {
+	d, err := newDecoder(in, reflect.TypeFor[T](), NewDialect(opts...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decoder[T]{d: d}, nil
+}
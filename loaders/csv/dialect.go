@@ -0,0 +1,168 @@
+package csv
+
+import "time"
+
+//////
+// Consts, vars and types.
+//////
+
+// TrimPolicy defines which whitespace is trimmed from values.
+type TrimPolicy string
+
+const (
+	// TrimNone keeps values as they are. It's the default.
+	TrimNone TrimPolicy = "none"
+
+	// TrimLeading trims leading whitespace, even of quoted values'
+	// delimiters, as `encoding/csv`'s `TrimLeadingSpace` does.
+	TrimLeading TrimPolicy = "leading"
+
+	// TrimSpace trims leading and trailing whitespace of values, and header
+	// names.
+	TrimSpace TrimPolicy = "space"
+)
+
+// Dialect defines how CSV is read, and how columns map to fields.
+type Dialect struct {
+	// Delimiter separates values. Defaults to ','.
+	Delimiter rune `json:"delimiter"`
+
+	// Comment, if set, marks lines to ignore when at their beginning.
+	Comment rune `json:"comment"`
+
+	// LazyQuotes allows quotes in unquoted values, and non-doubled quotes in
+	// quoted values.
+	LazyQuotes bool `json:"lazyQuotes"`
+
+	// Headers renames columns: it maps a CSV header to the name of the
+	// field it fills.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Columns, if set, switches to headerless mode: there's no header row,
+	// and values map to fields by position, the n-th value filling the field
+	// named by the n-th column.
+	Columns []string `json:"columns,omitempty"`
+
+	// SkipRows is the number of records to skip before the header row (or
+	// the first data row, in headerless mode), e.g., a preamble.
+	SkipRows int `json:"skipRows" validate:"gte=0"`
+
+	// Trim defines which whitespace is trimmed from values.
+	Trim TrimPolicy `json:"trim" validate:"omitempty,oneof=none leading space"`
+
+	// StripTabs removes every tab character from values.
+	StripTabs bool `json:"stripTabs"`
+
+	// TimeLayout is the layout `time.Time` fields are parsed with, as
+	// `time.Parse` expects it. Defaults to `time.RFC3339`.
+	TimeLayout string `json:"timeLayout" validate:"required"`
+}
+
+// Func allows to specify dialect's options.
+type Func func(d *Dialect) *Dialect
+
+//////
+// Built-in options.
+//////
+
+// WithDelimiter sets the value delimiter.
+func WithDelimiter(delimiter rune) Func {
+	return func(d *Dialect) *Dialect {
+		d.Delimiter = delimiter
+
+		return d
+	}
+}
+
+// WithComment sets the comment character.
+func WithComment(comment rune) Func {
+	return func(d *Dialect) *Dialect {
+		d.Comment = comment
+
+		return d
+	}
+}
+
+// WithLazyQuotes sets whether quotes are parsed leniently.
+func WithLazyQuotes(lazyQuotes bool) Func {
+	return func(d *Dialect) *Dialect {
+		d.LazyQuotes = lazyQuotes
+
+		return d
+	}
+}
+
+// WithHeaders renames columns, from CSV header to field name.
+func WithHeaders(headers map[string]string) Func {
+	return func(d *Dialect) *Dialect {
+		d.Headers = headers
+
+		return d
+	}
+}
+
+// WithColumns switches to headerless mode, mapping values to fields by
+// position.
+func WithColumns(columns ...string) Func {
+	return func(d *Dialect) *Dialect {
+		d.Columns = columns
+
+		return d
+	}
+}
+
+// WithSkipRows skips `n` records before the header row.
+func WithSkipRows(n int) Func {
+	return func(d *Dialect) *Dialect {
+		d.SkipRows = n
+
+		return d
+	}
+}
+
+// WithTrim sets which whitespace is trimmed from values.
+func WithTrim(trim TrimPolicy) Func {
+	return func(d *Dialect) *Dialect {
+		d.Trim = trim
+
+		return d
+	}
+}
+
+// WithStripTabs sets whether every tab character is removed from values.
+func WithStripTabs(stripTabs bool) Func {
+	return func(d *Dialect) *Dialect {
+		d.StripTabs = stripTabs
+
+		return d
+	}
+}
+
+// WithTimeLayout sets the layout `time.Time` fields are parsed with.
+func WithTimeLayout(layout string) Func {
+	return func(d *Dialect) *Dialect {
+		d.TimeLayout = layout
+
+		return d
+	}
+}
+
+//////
+// Factory.
+//////
+
+// NewDialect returns a new dialect: comma-delimited, with a header row,
+// values kept as they are, and RFC 3339 times.
+func NewDialect(opts ...Func) *Dialect {
+	d := &Dialect{
+		Delimiter:  ',',
+		Trim:       TrimNone,
+		TimeLayout: time.RFC3339,
+	}
+
+	for _, opt := range opts {
+		d = opt(d)
+	}
+
+	return d
+}
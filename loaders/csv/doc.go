@@ -1,3 +1,17 @@
 // Package csv contains the CSV loader which allows to load data in a CSV format
 // into a slice of structs.
+//
+// Records are decoded one at a time, as they're read, so memory use only
+// depends on the output. A `Dialect` defines how CSV is read: delimiter,
+// comment character, lazy quotes, header renames, headerless positional
+// columns (`WithColumns`), preamble rows to skip, and trimming. Columns map
+// to fields by `csv` tag, else `json` tag, else field name, case-insensitively;
+// unknown columns are ignored. `NewDecoder` exposes the streaming decoder
+// itself.
+//
+// Values are coerced into strings, bools, ints, uints, floats, `time.Time`
+// (parsed with the dialect's `TimeLayout`), and `encoding.TextUnmarshaler`
+// types. Pointers are nullable: an empty value leaves them nil. A value that
+// can't be coerced fails with a `*DecodeError`, carrying its line, column and
+// raw value.
 package csv
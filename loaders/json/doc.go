@@ -0,0 +1,5 @@
+// Package json contains the JSON loader which allows to load data in a JSON
+// array format into a slice of structs.
+//
+// Array elements are decoded one at a time, as they're read.
+package json
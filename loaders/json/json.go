@@ -0,0 +1,135 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/etler/v3/pause"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Name of the loader.
+const Name = "json"
+
+// JSON definition.
+type JSON[Out any] struct {
+	loader.ILoader[io.Reader, Out] `json:"loader" validate:"required"`
+}
+
+//////
+// Helpers.
+//////
+
+// load decodes the JSON array read from `in`, one element at a time.
+func load[Out any](ctx context.Context, in io.Reader) ([]Out, error) {
+	decoder := json.NewDecoder(in)
+
+	token, err := decoder.Token()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, customerror.NewMissingError("JSON array")
+		}
+
+		return nil, customerror.NewFailedToError("read JSON", customerror.WithError(err))
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, customerror.NewInvalidError(fmt.Sprintf("JSON, expected an array, got %v", token))
+	}
+
+	out := []Out{}
+
+	for i := 0; decoder.More(); i++ {
+		if err := pause.Checkpoint(ctx); err != nil {
+			return nil, err
+		}
+
+		var item Out
+
+		if err := decoder.Decode(&item); err != nil {
+			return nil, customerror.NewFailedToError(
+				fmt.Sprintf("decode JSON array element %d", i),
+				customerror.WithError(err),
+			)
+		}
+
+		out = append(out, item)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, customerror.NewFailedToError("read JSON", customerror.WithError(err))
+	}
+
+	return out, nil
+}
+
+//////
+// Exported functions.
+//////
+
+// Load decodes the JSON array read from `in` into `Out`, a slice.
+func Load[Out ~[]Item, Item any](in io.Reader) (Out, error) {
+	out, err := load[Item](context.Background(), in)
+	if err != nil {
+		return nil, err
+	}
+
+	return Out(out), nil
+}
+
+//////
+// Factory.
+//////
+
+// New creates a new loader.
+func New[Out any](
+	opts ...loader.Func[io.Reader, []Out],
+) (*JSON[[]Out], error) {
+	// Enforces interface implementation.
+	var _ loader.ILoader[io.Reader, []Out] = (*JSON[[]Out])(nil)
+
+	conv, err := loader.New(
+		Name,
+		fmt.Sprintf("%s %s", Name, loader.Type),
+		load[Out],
+		opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &JSON[[]Out]{
+		conv,
+	}
+
+	// NOTE: `opts` were already applied by `loader.New` — applying them
+	// again here would run each option twice.
+
+	// Validation.
+	if err := validation.Validate(j); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Must returns a new loader or panics if an error occurs.
+func Must[Out any](
+	opts ...loader.Func[io.Reader, []Out],
+) *JSON[[]Out] {
+	j, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return j
+}
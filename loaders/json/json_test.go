@@ -0,0 +1,83 @@
+package json
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/loader"
+)
+
+// Test struct.
+type Test struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestNew(t *testing.T) {
+	buf := new(strings.Builder)
+
+	jsonLoader, err := New(
+		loader.WithOnFinished(func(ctx context.Context, c loader.ILoader[io.Reader, []Test], originalIn io.Reader, convertedOut []Test) {
+			buf.WriteString(c.GetName() + " finished")
+		}),
+	)
+	require.NoError(t, err)
+
+	loadedData, err := jsonLoader.Run(
+		context.Background(),
+		strings.NewReader(`[{"id":1,"name":"John"},{"id":2,"name":"Peter"}]`),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Test{{ID: 1, Name: "John"}, {ID: 2, Name: "Peter"}}, loadedData)
+	assert.Equal(t, "json finished", buf.String())
+}
+
+// Happy path: Must returns a working loader.
+func TestMust(t *testing.T) {
+	var l *JSON[[]Test]
+
+	require.NotPanics(t, func() {
+		l = Must[Test]()
+	})
+
+	out, err := l.Run(context.Background(), strings.NewReader(" [ ] "))
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+// Bad path: invalid input fails.
+func TestLoad_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: "", want: "missing"},
+		{name: "not an array", input: `{"id":1}`, want: "expected an array"},
+		{name: "bad element", input: `[{"id":1},{"id":"two"}]`, want: "element 1"},
+		{name: "truncated", input: `[{"id":1}`, want: "element 1"},
+		{name: "malformed", input: `[{"id":1]`, want: "element 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load[[]Test](strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+// Edge case: a done context stops loading.
+func TestLoad_contextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := load[Test](ctx, strings.NewReader(`[{"id":1}]`))
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,5 @@
+// Package ndjson contains the NDJSON loader which allows to load data in a
+// newline-delimited JSON format into a slice of structs.
+//
+// Lines are decoded one at a time, as they're read. Blank lines are skipped.
+package ndjson
@@ -0,0 +1,130 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/etler/v3/pause"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Name of the loader.
+const Name = "ndjson"
+
+// NDJSON definition.
+type NDJSON[Out any] struct {
+	loader.ILoader[io.Reader, Out] `json:"loader" validate:"required"`
+}
+
+//////
+// Helpers.
+//////
+
+// load decodes the NDJSON read from `in`, one line at a time.
+func load[Out any](ctx context.Context, in io.Reader) ([]Out, error) {
+	reader := bufio.NewReader(in)
+
+	out := []Out{}
+
+	for line := 1; ; line++ {
+		if err := pause.Checkpoint(ctx); err != nil {
+			return nil, err
+		}
+
+		// NOTE: Unlike `bufio.Scanner`, lines aren't limited in length.
+		b, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, customerror.NewFailedToError("read NDJSON", customerror.WithError(err))
+		}
+
+		if b = bytes.TrimSpace(b); len(b) > 0 {
+			var item Out
+
+			if err := json.Unmarshal(b, &item); err != nil {
+				return nil, customerror.NewFailedToError(
+					fmt.Sprintf("decode NDJSON line %d", line),
+					customerror.WithError(err),
+				)
+			}
+
+			out = append(out, item)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+	}
+}
+
+//////
+// Exported functions.
+//////
+
+// Load decodes the NDJSON read from `in` into `Out`, a slice.
+func Load[Out ~[]Item, Item any](in io.Reader) (Out, error) {
+	out, err := load[Item](context.Background(), in)
+	if err != nil {
+		return nil, err
+	}
+
+	return Out(out), nil
+}
+
+//////
+// Factory.
+//////
+
+// New creates a new loader.
+func New[Out any](
+	opts ...loader.Func[io.Reader, []Out],
+) (*NDJSON[[]Out], error) {
+	// Enforces interface implementation.
+	var _ loader.ILoader[io.Reader, []Out] = (*NDJSON[[]Out])(nil)
+
+	conv, err := loader.New(
+		Name,
+		fmt.Sprintf("%s %s", Name, loader.Type),
+		load[Out],
+		opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &NDJSON[[]Out]{
+		conv,
+	}
+
+	// NOTE: `opts` were already applied by `loader.New` — applying them
+	// again here would run each option twice.
+
+	// Validation.
+	if err := validation.Validate(n); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Must returns a new loader or panics if an error occurs.
+func Must[Out any](
+	opts ...loader.Func[io.Reader, []Out],
+) *NDJSON[[]Out] {
+	n, err := New(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return n
+}
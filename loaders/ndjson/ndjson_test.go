@@ -0,0 +1,74 @@
+package ndjson
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/loader"
+)
+
+// Test struct.
+type Test struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestNew(t *testing.T) {
+	buf := new(strings.Builder)
+
+	ndjsonLoader, err := New(
+		loader.WithOnFinished(func(ctx context.Context, c loader.ILoader[io.Reader, []Test], originalIn io.Reader, convertedOut []Test) {
+			buf.WriteString(c.GetName() + " finished")
+		}),
+	)
+	require.NoError(t, err)
+
+	loadedData, err := ndjsonLoader.Run(
+		context.Background(),
+		strings.NewReader("{\"id\":1,\"name\":\"John\"}\n{\"id\":2,\"name\":\"Peter\"}\n"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Test{{ID: 1, Name: "John"}, {ID: 2, Name: "Peter"}}, loadedData)
+	assert.Equal(t, "ndjson finished", buf.String())
+}
+
+// Happy path: Must returns a working loader.
+func TestMust(t *testing.T) {
+	var l *NDJSON[[]Test]
+
+	require.NotPanics(t, func() {
+		l = Must[Test]()
+	})
+
+	out, err := l.Run(context.Background(), strings.NewReader(""))
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+// Edge case: blank lines, CRLF, and a missing trailing newline.
+func TestLoad_lines(t *testing.T) {
+	out, err := Load[[]Test](strings.NewReader("\n{\"id\":1}\r\n  \n{\"id\":2}"))
+	require.NoError(t, err)
+	assert.Equal(t, []Test{{ID: 1}, {ID: 2}}, out)
+}
+
+// Bad path: a bad line fails, with its number.
+func TestLoad_badLine(t *testing.T) {
+	_, err := Load[[]Test](strings.NewReader("{\"id\":1}\n\n{\"id\":\"three\"}\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+}
+
+// Edge case: a done context stops loading.
+func TestLoad_contextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := load[Test](ctx, strings.NewReader("{\"id\":1}\n"))
+	assert.ErrorIs(t, err, context.Canceled)
+}
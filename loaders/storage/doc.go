@@ -0,0 +1,10 @@
+// Package storage contains the storage loader which allows to load data from
+// any storage system implementing the `dal` `storage.IStorage` interface.
+//
+// `New` lists a target, with the filter, sort and fields of the `list.List`
+// params it runs with. With a page size set, large targets are listed lazily,
+// one page at a time; `NewPager` exposes the paging itself. How a page is
+// decoded depends on the backend: `ListSlice` for backends listing into a
+// slice (e.g., MongoDB, SQL), `ListItems` for backends wrapping items (e.g.,
+// memory). `NewRetrieve` retrieves items by ID.
+package storage
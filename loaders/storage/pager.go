@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"io"
+
+	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/etler/v3/pause"
+	"github.com/thalesfsp/params/v2/list"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// DefaultTarget is the target (e.g., collection, index, table) items are
+// listed from, if not set. It's the same as the storage processor's.
+const DefaultTarget = "etl"
+
+// ListFunc lists a page of `target`, according to `prm`, decoding it.
+type ListFunc[Out any] func(
+	ctx context.Context,
+	s storage.IStorage,
+	target string,
+	prm *list.List,
+) ([]Out, error)
+
+// Config defines where, and how items are listed.
+type Config[Out any] struct {
+	// Target items are listed from.
+	Target string `json:"target" validate:"required"`
+
+	// PageSize is the number of items listed per call. If not set, the target
+	// is listed in a single call, with the params as they are.
+	PageSize int `json:"pageSize" validate:"gte=0"`
+
+	// List lists, and decodes a page.
+	List ListFunc[Out] `json:"-" validate:"required"`
+}
+
+// Func allows to specify config's options.
+type Func[Out any] func(c *Config[Out]) *Config[Out]
+
+// Pager lists a target lazily, one page at a time.
+type Pager[Out any] struct {
+	s      storage.IStorage
+	config *Config[Out]
+	prm    list.List
+	done   bool
+}
+
+//////
+// Built-in list functions.
+//////
+
+// ListSlice lists backends decoding a page into a slice, e.g., MongoDB, SQL.
+func ListSlice[Out any]() ListFunc[Out] {
+	return func(ctx context.Context, s storage.IStorage, target string, prm *list.List) ([]Out, error) {
+		return storage.List[[]Out](ctx, s, target, prm)
+	}
+}
+
+// ListItems lists backends decoding a page into an object, with items under
+// "items", e.g., memory.
+func ListItems[Out any]() ListFunc[Out] {
+	return func(ctx context.Context, s storage.IStorage, target string, prm *list.List) ([]Out, error) {
+		page, err := storage.List[struct {
+			Items []Out `json:"items"`
+		}](ctx, s, target, prm)
+		if err != nil {
+			return nil, err
+		}
+
+		return page.Items, nil
+	}
+}
+
+//////
+// Built-in options.
+//////
+
+// WithTarget sets the target items are listed from.
+func WithTarget[Out any](target string) Func[Out] {
+	return func(c *Config[Out]) *Config[Out] {
+		c.Target = target
+
+		return c
+	}
+}
+
+// WithPageSize sets the number of items listed per call.
+func WithPageSize[Out any](pageSize int) Func[Out] {
+	return func(c *Config[Out]) *Config[Out] {
+		c.PageSize = pageSize
+
+		return c
+	}
+}
+
+// WithList sets how a page is listed, and decoded.
+func WithList[Out any](fn ListFunc[Out]) Func[Out] {
+	return func(c *Config[Out]) *Config[Out] {
+		c.List = fn
+
+		return c
+	}
+}
+
+//////
+// Methods.
+//////
+
+// Next returns the next page. It returns `io.EOF` once the target is
+// exhausted.
+//
+// NOTE: Paging stops at the first page shorter than the page size, and at
+// the first page longer than it, for backends ignoring `Limit`, and `Offset`.
+func (p *Pager[Out]) Next(ctx context.Context) ([]Out, error) {
+	if p.done {
+		return nil, io.EOF
+	}
+
+	if err := pause.Checkpoint(ctx); err != nil {
+		return nil, err
+	}
+
+	prm := p.prm
+
+	if p.config.PageSize > 0 {
+		prm.Limit = p.config.PageSize
+	}
+
+	items, err := p.config.List(ctx, p.s, p.config.Target, &prm)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.config.PageSize == 0 || len(items) != p.config.PageSize {
+		p.done = true
+	}
+
+	if len(items) == 0 {
+		return nil, io.EOF
+	}
+
+	p.prm.Offset += len(items)
+
+	return items, nil
+}
+
+//////
+// Factory.
+//////
+
+// NewConfig returns a new config: the `DefaultTarget` is listed in a single
+// call, into a slice.
+func NewConfig[Out any](opts ...Func[Out]) *Config[Out] {
+	c := &Config[Out]{
+		Target: DefaultTarget,
+		List:   ListSlice[Out](),
+	}
+
+	for _, opt := range opts {
+		c = opt(c)
+	}
+
+	return c
+}
+
+// NewPager returns a new pager of `s`, according to `config`, and `prm`,
+// which sets the filter, sort, fields, and first offset. If nil, everything
+// is listed.
+func NewPager[Out any](s storage.IStorage, config *Config[Out], prm *list.List) (*Pager[Out], error) {
+	if err := validation.Validate(config); err != nil {
+		return nil, err
+	}
+
+	p := &Pager[Out]{
+		s:      s,
+		config: config,
+	}
+
+	if prm != nil {
+		p.prm = *prm
+	}
+
+	return p, nil
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/params/v2/list"
+)
+
+// sliceList returns a list function paging through `items`, honoring
+// `Limit`, and `Offset`, and recording the params of every call.
+func sliceList(items []int, calls *[]list.List) ListFunc[int] {
+	return func(ctx context.Context, s storage.IStorage, target string, prm *list.List) ([]int, error) {
+		*calls = append(*calls, *prm)
+
+		start := min(prm.Offset, len(items))
+		end := len(items)
+
+		if prm.Limit > 0 {
+			end = min(start+prm.Limit, len(items))
+		}
+
+		return items[start:end], nil
+	}
+}
+
+// Happy path: pages are listed lazily, until exhausted.
+func TestPager_Next(t *testing.T) {
+	var calls []list.List
+
+	p, err := NewPager(nil, NewConfig(
+		WithList(sliceList([]int{1, 2, 3, 4, 5}, &calls)),
+		WithPageSize[int](2),
+	), &list.List{Search: "filter", Offset: 0})
+	require.NoError(t, err)
+
+	var pages [][]int
+
+	for {
+		page, err := p.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		pages = append(pages, page)
+
+		// Lazily: a page per call.
+		assert.Len(t, calls, len(pages))
+	}
+
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, pages)
+
+	for i, call := range calls {
+		assert.Equal(t, "filter", call.Search)
+		assert.Equal(t, 2, call.Limit)
+		assert.Equal(t, i*2, call.Offset)
+	}
+
+	// Exhausted pagers stay exhausted.
+	_, err = p.Next(context.Background())
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// Edge case: a full last page costs an extra, empty call.
+func TestPager_exactPages(t *testing.T) {
+	var calls []list.List
+
+	l := Must(nil, NewConfig(WithList(sliceList([]int{1, 2, 3, 4}, &calls)), WithPageSize[int](2)))
+
+	out, err := l.Run(context.Background(), &list.List{Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 4}, out)
+	assert.Len(t, calls, 2)
+}
+
+// Edge case: without page size, a single call, with the params as they are.
+func TestPager_noPaging(t *testing.T) {
+	var calls []list.List
+
+	l := Must(nil, NewConfig(WithList(sliceList([]int{1, 2, 3}, &calls))))
+
+	out, err := l.Run(context.Background(), &list.List{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, out)
+	assert.Len(t, calls, 1)
+
+	// Edge case: nothing to list.
+	out, err = Must(nil, NewConfig(WithList(sliceList(nil, &calls)))).Run(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+// Bad path: list errors, done contexts, and invalid configs fail.
+func TestPager_errors(t *testing.T) {
+	failing := Must(nil, NewConfig(WithList(func(ctx context.Context, s storage.IStorage, target string, prm *list.List) ([]int, error) {
+		return nil, errors.New("backend unavailable")
+	})))
+
+	_, err := failing.Run(context.Background(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backend unavailable")
+
+	var calls []list.List
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p, err := NewPager(nil, NewConfig(WithList(sliceList([]int{1}, &calls))), nil)
+	require.NoError(t, err)
+
+	_, err = p.Next(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, calls)
+
+	_, err = New(nil, NewConfig(WithTarget[int]("")))
+	require.Error(t, err)
+
+	_, err = New(nil, NewConfig(WithPageSize[int](-1)))
+	require.Error(t, err)
+
+	_, err = New(nil, NewConfig(WithList[int](nil)))
+	require.Error(t, err)
+
+	_, err = New[int](nil, nil)
+	require.Error(t, err)
+}
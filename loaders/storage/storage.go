@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/etler/v3/pause"
+	"github.com/thalesfsp/params/v2/list"
+	"github.com/thalesfsp/params/v2/retrieve"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Name of the loader.
+const Name = "storage"
+
+// Storage definition.
+type Storage[In, Out any] struct {
+	loader.ILoader[In, Out] `json:"loader" validate:"required"`
+}
+
+//////
+// Factory.
+//////
+
+// New creates a new loader, listing items according to `config`, and to the
+// `list.List` params it runs with: filter, sort, fields, and first offset.
+// Running with nil params lists everything.
+func New[Out any](
+	s storage.IStorage,
+	config *Config[Out],
+	opts ...loader.Func[*list.List, []Out],
+) (*Storage[*list.List, []Out], error) {
+	// Enforces interface implementation.
+	var _ loader.ILoader[*list.List, []Out] = (*Storage[*list.List, []Out])(nil)
+
+	// Fails early, rather than on every run.
+	if _, err := NewPager(s, config, nil); err != nil {
+		return nil, err
+	}
+
+	l, err := loader.New(
+		Name,
+		fmt.Sprintf("%s %s", Name, loader.Type),
+		func(ctx context.Context, prm *list.List) ([]Out, error) {
+			pager, err := NewPager(s, config, prm)
+			if err != nil {
+				return nil, err
+			}
+
+			out := []Out{}
+
+			for {
+				page, err := pager.Next(ctx)
+				if errors.Is(err, io.EOF) {
+					return out, nil
+				}
+
+				if err != nil {
+					return nil, err
+				}
+
+				out = append(out, page...)
+			}
+		},
+		opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	str := &Storage[*list.List, []Out]{
+		l,
+	}
+
+	// NOTE: `opts` were already applied by `loader.New` — applying them
+	// again here would run each option twice.
+
+	// Validation.
+	if err := validation.Validate(str); err != nil {
+		return nil, err
+	}
+
+	return str, nil
+}
+
+// NewRetrieve creates a new loader, retrieving from `target` the items whose
+// IDs it runs with, in order.
+func NewRetrieve[Out any](
+	s storage.IStorage,
+	target string,
+	opts ...loader.Func[[]string, []Out],
+) (*Storage[[]string, []Out], error) {
+	// Enforces interface implementation.
+	var _ loader.ILoader[[]string, []Out] = (*Storage[[]string, []Out])(nil)
+
+	l, err := loader.New(
+		Name,
+		fmt.Sprintf("%s %s", Name, loader.Type),
+		func(ctx context.Context, ids []string) ([]Out, error) {
+			out := make([]Out, 0, len(ids))
+
+			for _, id := range ids {
+				if err := pause.Checkpoint(ctx); err != nil {
+					return nil, err
+				}
+
+				item, err := storage.Retrieve[Out](ctx, s, id, target, &retrieve.Retrieve{ID: id})
+				if err != nil {
+					return nil, fmt.Errorf("item %q: %w", id, err)
+				}
+
+				out = append(out, item)
+			}
+
+			return out, nil
+		},
+		opts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	str := &Storage[[]string, []Out]{
+		l,
+	}
+
+	// NOTE: `opts` were already applied by `loader.New` — applying them
+	// again here would run each option twice.
+
+	// Validation.
+	if err := validation.Validate(str); err != nil {
+		return nil, err
+	}
+
+	return str, nil
+}
+
+// Must returns a new loader or panics if an error occurs.
+func Must[Out any](
+	s storage.IStorage,
+	config *Config[Out],
+	opts ...loader.Func[*list.List, []Out],
+) *Storage[*list.List, []Out] {
+	str, err := New(s, config, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return str
+}
+
+// MustRetrieve returns a new retrieving loader or panics if an error occurs.
+func MustRetrieve[Out any](
+	s storage.IStorage,
+	target string,
+	opts ...loader.Func[[]string, []Out],
+) *Storage[[]string, []Out] {
+	str, err := NewRetrieve(s, target, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return str
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/dal/v2/memory"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/params/v2/create"
+	"github.com/thalesfsp/params/v2/list"
+)
+
+// Test struct.
+type Test struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// newMemory returns the test binary's memory storage, seeded with two items.
+// A second DAL memory storage per test binary would panic on expvar
+// re-registration.
+var newMemory = sync.OnceValues(func() (*memory.Memory, error) {
+	m, err := memory.New(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range []Test{{ID: "1", Name: "John"}, {ID: "2", Name: "Peter"}} {
+		if _, err := m.Create(context.Background(), "test-"+t.ID, DefaultTarget, t, &create.Create{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+})
+
+func TestNew(t *testing.T) {
+	m, err := newMemory()
+	require.NoError(t, err)
+
+	finished := ""
+
+	storageLoader, err := New(
+		m,
+		NewConfig(WithList(ListItems[Test]())),
+		loader.WithOnFinished(func(ctx context.Context, c loader.ILoader[*list.List, []Test], originalIn *list.List, convertedOut []Test) {
+			finished = c.GetName() + " finished"
+		}),
+	)
+	require.NoError(t, err)
+
+	loadedData, err := storageLoader.Run(context.Background(), nil)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []Test{{ID: "1", Name: "John"}, {ID: "2", Name: "Peter"}}, loadedData)
+	assert.Equal(t, "storage finished", finished)
+
+	// Filtering, with the memory storage's key pattern.
+	loadedData, err = storageLoader.Run(context.Background(), &list.List{Search: "test-2"})
+	require.NoError(t, err)
+	assert.Equal(t, []Test{{ID: "2", Name: "Peter"}}, loadedData)
+
+	// Edge case: the memory storage ignores `Limit`, paging stops after the
+	// first, longer page.
+	pagedLoader := Must(m, NewConfig(WithList(ListItems[Test]()), WithPageSize[Test](1)))
+
+	loadedData, err = pagedLoader.Run(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, loadedData, 2)
+}
+
+func TestNewRetrieve(t *testing.T) {
+	m, err := newMemory()
+	require.NoError(t, err)
+
+	retrieveLoader := MustRetrieve[Test](m, DefaultTarget)
+
+	loadedData, err := retrieveLoader.Run(context.Background(), []string{"test-2", "test-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []Test{{ID: "2", Name: "Peter"}, {ID: "1", Name: "John"}}, loadedData)
+
+	// Bad path: a missing item fails, with its ID.
+	_, err = retrieveLoader.Run(context.Background(), []string{"test-1", "test-3"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `item "test-3"`)
+}
@@ -0,0 +1,20 @@
+// Package pause lets transform functions, processors', converters', and
+// loaders', honour pauses at safe points of their own loops, e.g., between
+// items.
+//
+// Processors, converters, and loaders, already wait before running their
+// function while their pipeline, run, stage, or themselves, are paused. A
+// function looping over many items calls `Checkpoint` between them, to also
+// pause midway:
+//
+//	for _, item := range items {
+//		if err := pause.Checkpoint(ctx); err != nil {
+//			return nil, err
+//		}
+//
+//		// Process item.
+//	}
+//
+// `Checkpoint` returns `ErrInterrupted` if the run drains while paused:
+// returned as is, the component reports itself interrupted, not failed.
+package pause
@@ -0,0 +1,36 @@
+package pause
+
+import (
+	"context"
+
+	"github.com/thalesfsp/etler/v3/internal/shared"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// ErrInterrupted is returned by `Checkpoint` when the run drains while
+// paused.
+var ErrInterrupted = shared.ErrInterrupted
+
+//////
+// Exported functions.
+//////
+
+// Paused returns whether anything `ctx` runs under, the pipeline, the run,
+// the stage, or the component, is paused.
+func Paused(ctx context.Context) bool {
+	return shared.IsPaused(ctx)
+}
+
+// Checkpoint blocks while `ctx` is paused, see `Paused`. It returns nil once
+// resumed, or right away if not paused, `ErrInterrupted` if the run drains
+// first, or the context error if the context is done.
+func Checkpoint(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return shared.WaitResumed(ctx)
+}
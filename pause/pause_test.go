@@ -0,0 +1,67 @@
+package pause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+)
+
+// Happy path: a checkpoint blocks while paused, and returns once resumed.
+func TestCheckpoint(t *testing.T) {
+	// Edge case: outside a pipeline, nothing pauses.
+	assert.False(t, Paused(context.Background()))
+	require.NoError(t, Checkpoint(context.Background()))
+
+	pc := shared.NewPauseController()
+
+	ctx := shared.ContextWithPause(context.Background(), pc)
+
+	pc.Pause()
+
+	assert.True(t, Paused(ctx))
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- Checkpoint(ctx)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the checkpoint returned while paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	pc.Resume()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the checkpoint didn't return once resumed")
+	}
+}
+
+// Bad path: a checkpoint returns `ErrInterrupted` if the run drains while
+// paused, and the context error if the context is done.
+func TestCheckpoint_stopped(t *testing.T) {
+	pc := shared.NewPauseController()
+	pc.Pause()
+
+	d := shared.NewDrainer()
+	d.Drain()
+
+	ctx := shared.ContextWithDrainer(shared.ContextWithPause(context.Background(), pc), d)
+
+	require.ErrorIs(t, Checkpoint(ctx), ErrInterrupted)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Not paused, yet the context is done.
+	require.ErrorIs(t, Checkpoint(ctx), context.Canceled)
+}
@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/checkpoint"
+	"github.com/thalesfsp/etler/v3/task"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// runIDCtxKey is the context key under which a run ID travels.
+type runIDCtxKey struct{}
+
+//////
+// Context plumbing.
+//////
+
+// ContextWithRunID returns a copy of `ctx` carrying `runID`. With a
+// checkpoint store set, running a sequential pipeline with the run ID of a
+// failed run resumes it from its last completed stage.
+func ContextWithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDCtxKey{}, runID)
+}
+
+// RunIDFromCtx extracts the run ID from `ctx`, or "" if none.
+func RunIDFromCtx(ctx context.Context) string {
+	runID, _ := ctx.Value(runIDCtxKey{}).(string)
+
+	return runID
+}
+
+//////
+// Helpers.
+//////
+
+// checkpointing returns whether a run is checkpointed: it takes a store,
+// and a run ID.
+func (p *Pipeline[ProcessedData, ConvertedOut]) checkpointing(runID string) bool {
+	return p.GetCheckpoint() != nil && runID != ""
+}
+
+// resume returns the output of the stages already completed by the run,
+// in stage order. The checkpoints must match the pipeline's first stages.
+func (p *Pipeline[ProcessedData, ConvertedOut]) resume(
+	ctx context.Context,
+	runID string,
+	originalTask task.Task[ProcessedData, ConvertedOut],
+) ([]task.Task[ProcessedData, ConvertedOut], error) {
+	if !p.checkpointing(runID) {
+		return nil, nil
+	}
+
+	checkpoints, err := p.GetCheckpoint().Load(ctx, p.GetName(), runID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasksOut := make([]task.Task[ProcessedData, ConvertedOut], 0, len(checkpoints))
+
+	for i, c := range checkpoints {
+		if i >= len(p.Stages) || c.Index != i || c.Stage != p.Stages[i].GetName() {
+			return nil, customerror.NewInvalidError(
+				fmt.Sprintf("checkpoint %d (stage %q) of run %q, it doesn't match the pipeline's stages", c.Index, c.Stage, runID),
+			)
+		}
+
+		// Loggers aren't persisted.
+		if c.Task.Logger == nil {
+			c.Task.Logger = originalTask.Logger
+		}
+
+		tasksOut = append(tasksOut, c.Task)
+	}
+
+	return tasksOut, nil
+}
+
+// save persists the output of a completed stage.
+func (p *Pipeline[ProcessedData, ConvertedOut]) save(
+	ctx context.Context,
+	runID string,
+	index int,
+	tsk task.Task[ProcessedData, ConvertedOut],
+) error {
+	if !p.checkpointing(runID) {
+		return nil
+	}
+
+	return p.GetCheckpoint().Save(ctx, p.GetName(), runID, checkpoint.Checkpoint[ProcessedData, ConvertedOut]{
+		Index: index,
+		Stage: p.Stages[index].GetName(),
+		Task:  tsk,
+	})
+}
+
+// clear removes the checkpoints of a completed run: there's nothing left to
+// resume.
+func (p *Pipeline[ProcessedData, ConvertedOut]) clear(ctx context.Context, runID string) error {
+	if !p.checkpointing(runID) {
+		return nil
+	}
+
+	return p.GetCheckpoint().Delete(ctx, p.GetName(), runID)
+}
@@ -0,0 +1,250 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/event"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/report"
+	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/status"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// ErrCancelled is the cause of the context of a run cancelled with `Cancel`.
+var ErrCancelled = errors.New("cancelled")
+
+// activeRun is a run in progress, controllable by its ID.
+type activeRun struct {
+	// cancel cancels the context of the run.
+	cancel context.CancelCauseFunc
+
+	// pause is the pause controller of the run, combined with the
+	// pipeline's.
+	pause *shared.PauseController
+
+	// drainer drains the run.
+	drainer *shared.Drainer
+
+	// recorder records the report of the run.
+	recorder *report.Recorder
+}
+
+//////
+// Helpers.
+//////
+
+// track registers the run `runID` until the returned function is called.
+// The returned context is cancelled by `Cancel`, and carries the run's pause
+// controller, and drainer.
+func (p *Pipeline[ProcessedData, ConvertedOut]) track(
+	ctx context.Context,
+	runID string,
+	recorder *report.Recorder,
+) (context.Context, func(), error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	r := &activeRun{
+		cancel:   cancel,
+		pause:    shared.NewPauseController(),
+		drainer:  shared.NewDrainer(),
+		recorder: recorder,
+	}
+
+	p.runsMu.Lock()
+	defer p.runsMu.Unlock()
+
+	if _, ok := p.runs[runID]; ok {
+		cancel(nil)
+
+		return nil, nil, customerror.NewInvalidError(
+			fmt.Sprintf("run ID %q, a run with the same ID is in progress", runID),
+		)
+	}
+
+	if p.runs == nil {
+		p.runs = make(map[string]*activeRun)
+	}
+
+	p.runs[runID] = r
+
+	ctx = shared.ContextWithDrainer(shared.ContextWithPause(ctx, r.pause), r.drainer)
+
+	return ctx, func() {
+		p.runsMu.Lock()
+		delete(p.runs, runID)
+		p.runsMu.Unlock()
+
+		cancel(nil)
+	}, nil
+}
+
+// activeRun returns the run `runID`, if in progress.
+func (p *Pipeline[ProcessedData, ConvertedOut]) activeRun(runID string) (*activeRun, error) {
+	p.runsMu.Lock()
+	defer p.runsMu.Unlock()
+
+	r, ok := p.runs[runID]
+	if !ok {
+		return nil, customerror.NewNotFoundError(fmt.Sprintf("run %q of pipeline %q", runID, p.GetName()))
+	}
+
+	return r, nil
+}
+
+// interrupted returns whether a run stopped only because it drains.
+func interrupted(errs ...error) bool {
+	found := false
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, shared.ErrInterrupted) {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
+
+// interrupt stops the run because it drains. It returns the tasks of the
+// stages completed so far, in stage order, and `ErrInterrupted`.
+func (p *Pipeline[ProcessedData, ConvertedOut]) interrupt(
+	tasksOut []task.Task[ProcessedData, ConvertedOut],
+) ([]task.Task[ProcessedData, ConvertedOut], error) {
+	// Stages which didn't complete left a zero task.
+	completed := slices.DeleteFunc(slices.Clone(tasksOut), func(t task.Task[ProcessedData, ConvertedOut]) bool {
+		return t.ID == ""
+	})
+
+	//////
+	// Observability: tracing, metrics, status, logging, etc.
+	//////
+
+	p.SetProgressPercent()
+
+	return completed, shared.OnInterruptedHandler(p, p.GetCounterInterrupted(), p.GetLogger())
+}
+
+//////
+// Methods.
+//////
+
+// GetRuns returns the reports of the runs in progress, as recorded so far,
+// oldest first.
+func (p *Pipeline[ProcessedData, ConvertedOut]) GetRuns() []*RunReport {
+	p.runsMu.Lock()
+
+	reports := make([]*RunReport, 0, len(p.runs))
+
+	for _, r := range p.runs {
+		reports = append(reports, r.recorder.Report())
+	}
+
+	p.runsMu.Unlock()
+
+	slices.SortFunc(reports, func(a, b *RunReport) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	return reports
+}
+
+// GetRun returns the report of the run `runID`, as recorded so far, if in
+// progress.
+func (p *Pipeline[ProcessedData, ConvertedOut]) GetRun(runID string) (*RunReport, error) {
+	r, err := p.activeRun(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.recorder.Report(), nil
+}
+
+// SetRunPause sets the Paused status of the run `runID` only. Its processors
+// pause before their next execution and resume immediately on unpause, as
+// when the whole pipeline is paused.
+func (p *Pipeline[ProcessedData, ConvertedOut]) SetRunPause(runID string, state bool) error {
+	r, err := p.activeRun(runID)
+	if err != nil {
+		return err
+	}
+
+	e := event.Event{
+		Type:      event.Paused,
+		RunID:     runID,
+		Pipeline:  p.GetName(),
+		Component: Type,
+		Name:      p.GetName(),
+	}
+
+	if state {
+		r.pause.Pause()
+
+		r.recorder.SetStatus(status.Paused)
+	} else {
+		r.pause.Resume()
+
+		r.recorder.SetStatus(status.Runnning)
+
+		e.Type = event.Resumed
+	}
+
+	p.GetEventBus().Publish(context.Background(), e)
+
+	return nil
+}
+
+// Cancel cancels the run `runID`: its context is cancelled, with
+// `ErrCancelled` as the cause, and the run fails as soon as its running
+// components give up.
+func (p *Pipeline[ProcessedData, ConvertedOut]) Cancel(runID string) error {
+	r, err := p.activeRun(runID)
+	if err != nil {
+		return err
+	}
+
+	r.cancel(ErrCancelled)
+
+	return nil
+}
+
+// DrainRun drains the run `runID`: its processors, and conversions, in
+// flight finish, but no processor starts afterwards. The run returns the
+// tasks of the stages it completed, and `ErrInterrupted`; its report, and
+// the pipeline's status, are interrupted, and counted by
+// `CounterInterrupted`.
+//
+// NOTE: A paused run drains right away.
+func (p *Pipeline[ProcessedData, ConvertedOut]) DrainRun(runID string) error {
+	r, err := p.activeRun(runID)
+	if err != nil {
+		return err
+	}
+
+	r.drainer.Drain()
+
+	return nil
+}
+
+// Drain drains all the runs in progress, see `DrainRun`, e.g., before
+// shutting down. Runs started afterwards aren't affected.
+func (p *Pipeline[ProcessedData, ConvertedOut]) Drain() {
+	p.runsMu.Lock()
+	defer p.runsMu.Unlock()
+
+	for _, r := range p.runs {
+		r.drainer.Drain()
+	}
+}
@@ -0,0 +1,277 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/task"
+)
+
+//////
+// Helpers.
+//////
+
+// buildDAG resolves `Dependencies` into, for each stage (by index), the
+// indexes of its upstream stages. It fails on duplicated stage names, unknown
+// stages, and cycles.
+func (p *Pipeline[ProcessedData, ConvertedOut]) buildDAG() ([][]int, error) {
+	index := make(map[string]int, len(p.Stages))
+
+	for i, s := range p.Stages {
+		if _, ok := index[s.GetName()]; ok {
+			return nil, customerror.NewInvalidError(
+				fmt.Sprintf("DAG, duplicated stage name %q", s.GetName()),
+			)
+		}
+
+		index[s.GetName()] = i
+	}
+
+	upstreams := make([][]int, len(p.Stages))
+
+	for name, deps := range p.GetDependencies() {
+		i, ok := index[name]
+		if !ok {
+			return nil, customerror.NewMissingError(
+				fmt.Sprintf("stage %q, declared in the DAG dependencies", name),
+			)
+		}
+
+		for _, dep := range deps {
+			u, ok := index[dep]
+			if !ok {
+				return nil, customerror.NewMissingError(
+					fmt.Sprintf("upstream stage %q of stage %q", dep, name),
+				)
+			}
+
+			upstreams[i] = append(upstreams[i], u)
+		}
+	}
+
+	// Kahn's algorithm: if not every stage can be ordered, there's a cycle.
+	inDegree := make([]int, len(p.Stages))
+	downstreams := make([][]int, len(p.Stages))
+
+	for i, ups := range upstreams {
+		inDegree[i] = len(ups)
+
+		for _, u := range ups {
+			downstreams[u] = append(downstreams[u], i)
+		}
+	}
+
+	queue := make([]int, 0, len(p.Stages))
+
+	for i, d := range inDegree {
+		if d == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	ordered := 0
+
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+
+		ordered++
+
+		for _, d := range downstreams[i] {
+			inDegree[d]--
+
+			if inDegree[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	if ordered != len(p.Stages) {
+		return nil, customerror.NewInvalidError("DAG, stage dependencies form a cycle")
+	}
+
+	return upstreams, nil
+}
+
+// joinInput builds the input task of a stage from its upstream stages'
+// output: their processed data concatenated, in order, on top of `base`.
+// Without upstream stages it's `base` itself. Every stage gets its own copy
+// of the processed data, so fanned-out branches never share a backing array.
+func joinInput[ProcessedData, ConvertedOut any](
+	base task.Task[ProcessedData, ConvertedOut],
+	upstreamOut []task.Task[ProcessedData, ConvertedOut],
+) task.Task[ProcessedData, ConvertedOut] {
+	in := base
+
+	if len(upstreamOut) == 0 {
+		in.ProcessingData = append([]ProcessedData(nil), base.ProcessingData...)
+
+		return in
+	}
+
+	size := 0
+
+	for _, u := range upstreamOut {
+		size += len(u.ProcessingData)
+	}
+
+	in.ProcessingData = make([]ProcessedData, 0, size)
+
+	for _, u := range upstreamOut {
+		in.ProcessingData = append(in.ProcessingData, u.ProcessingData...)
+	}
+
+	in.ConvertedData = make([]ConvertedOut, 0)
+
+	return in
+}
+
+// runDAG runs the stages following their declared dependencies. Each stage
+// starts as soon as all its upstream stages are done. The first failure
+// cancels every stage that hasn't started yet.
+func (p *Pipeline[ProcessedData, ConvertedOut]) runDAG(
+	ctx context.Context,
+	tracedContext context.Context,
+	now time.Time,
+	originalTask task.Task[ProcessedData, ConvertedOut],
+) ([]task.Task[ProcessedData, ConvertedOut], error) {
+	upstreams, err := p.buildDAG()
+	if err != nil {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return nil, shared.OnErrorHandler(
+			tracedContext,
+			p,
+			p.GetLogger(),
+			err,
+			"build DAG",
+			Type,
+			p.GetName(),
+		)
+	}
+
+	dagContext, cancel := context.WithCancel(tracedContext)
+	defer cancel()
+
+	// One task per stage, in stage order — same contract as the other modes.
+	// `tasksOut[i]` and `failed[i]` are written by stage i's goroutine
+	// before it closes `done[i]`, and only read after that.
+	tasksOut := make([]task.Task[ProcessedData, ConvertedOut], len(p.Stages))
+	failed := make([]bool, len(p.Stages))
+	done := make([]chan struct{}, len(p.Stages))
+
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	var (
+		wg     sync.WaitGroup
+		errsMu sync.Mutex
+		errs   []error
+	)
+
+	for i, s := range p.Stages {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+
+			upstreamOut := make([]task.Task[ProcessedData, ConvertedOut], 0, len(upstreams[i]))
+
+			// Join: wait for every upstream stage. A failed upstream fails
+			// this branch without running it.
+			for _, u := range upstreams[i] {
+				select {
+				case <-done[u]:
+				case <-dagContext.Done():
+					failed[i] = true
+
+					return
+				}
+
+				if failed[u] {
+					failed[i] = true
+
+					return
+				}
+
+				upstreamOut = append(upstreamOut, tasksOut[u])
+			}
+
+			stageOut, err := s.Run(dagContext, joinInput(originalTask, upstreamOut))
+			if err != nil {
+				failed[i] = true
+
+				// The stage already traced, logged, and counted its own
+				// failure. The pipeline-level handling happens once, below.
+				errsMu.Lock()
+				errs = append(errs, err)
+				errsMu.Unlock()
+
+				// Other branches stop by themselves when the run drains.
+				if !errors.Is(err, shared.ErrInterrupted) {
+					cancel()
+				}
+
+				return
+			}
+
+			tasksOut[i] = stageOut
+
+			//////
+			// Observability: tracing, metrics, status, logging, etc.
+			//////
+
+			p.GetProgress().Add(1)
+
+			p.SetProgressPercent()
+		}()
+	}
+
+	wg.Wait()
+
+	// Stages may have been skipped only because the caller's context is done.
+	if len(errs) == 0 && tracedContext.Err() != nil {
+		errs = append(errs, tracedContext.Err())
+	}
+
+	if interrupted(errs...) {
+		return p.interrupt(tasksOut)
+	}
+
+	if len(errs) > 0 {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return nil, shared.OnErrorHandler(
+			tracedContext,
+			p,
+			p.GetLogger(),
+			errors.Join(errs...),
+			"run stage",
+			Type,
+			p.GetName(),
+		)
+	}
+
+	//////
+	// Observability: tracing, metrics, status, logging, etc.
+	//////
+
+	// Recompute the final percentage once: concurrent branches' updates can
+	// interleave and leave a stale value.
+	p.SetProgressPercent()
+
+	p.UpdateObservability(ctx, now, originalTask, tasksOut)
+
+	return tasksOut, nil
+}
@@ -68,6 +68,20 @@
 //
 // When `ConcurrentStage` is set to `false`, the pipeline executes stages synchronously, processing data sequentially through each stage. This mode is useful for scenarios where data dependencies or ordering are important. In this mode, the output of one stage becomes the input of the next stage, and so on.
 //
+// Stages can also declare which upstream stages they consume, using `WithDependencies` (or `SetDependencies`). Any declared dependency switches the pipeline to DAG mode, and `ConcurrentStage` is ignored. Stages without upstream stages receive the original data, independent branches run concurrently, and a stage with several upstream stages (a join) waits for all of them and receives their processed data concatenated, in the declared order. Duplicated stage names, unknown stages and cycles are rejected before any stage runs. If a stage fails, its downstream stages don't run, and the pipeline fails.
+//
+// For datasets that don't fit in memory, `RunStream` runs the pipeline over a channel instead of a slice. Items are grouped into micro-batches (`WithStreamBatchSize`, `WithStreamFlushInterval`), each one becoming a task. Every stage works on its own micro-batch concurrently with the others, and bounded buffers between stages (`WithStreamBufferSize`) apply backpressure up to the input channel. Stages are wired according to the pipeline's mode, and, for each micro-batch, in input order, the tasks of the terminal stages are sent to the output channel. The first failure stops the whole stream.
+//
+// With a checkpoint store set (`WithCheckpoint`, see the `checkpoint` package), a sequential pipeline run with a run ID (`ContextWithRunID`) saves the output of each completed stage. Running it again with the same run ID, after a failure, resumes from the last completed stage instead of starting from scratch. Checkpoints are deleted once the run completes. Checkpoints that don't match the pipeline's stages are rejected.
+//
+// Each run has a run ID, the caller's (`ContextWithRunID`), or a new one, carried by the context of its stages and processors (`RunIDFromCtx`). Pipelines' metrics aggregate all runs, so concurrent runs of the same pipeline overwrite each other's progress, status, and duration. `RunWithReport` also returns the run's `RunReport`, isolated from other runs: its status, task ID, and the timings, item counts, and errors of each stage and processor (see the `report` package).
+//
+// Every pipeline has an event bus (`GetEventBus`, see the `event` package). Runs publish typed lifecycle events, e.g., `RunStarted`, `StageFinished`, `ProcessorFailed`, or `ConverterFailed`, and pausing publishes `Paused`, and `Resumed`, to any number of subscribers (`WithSubscriber`).
+//
+// Runs in progress can be controlled by run ID: `GetRuns`, and `GetRun`, report them as recorded so far, `SetRunPause` pauses, or resumes, a single run, on top of the pipeline-wide `SetPause`, and of the stages', and processors', own `SetPause`, `Cancel` cancels its context, failing it, and `DrainRun`, or `Drain` for all runs, drains it: processors, and conversions, in flight finish, but no processor starts afterwards. A drained run returns the tasks of the stages it completed, and `ErrInterrupted`; its report, and the pipeline's status, are interrupted, and counted by `CounterInterrupted`. A paused run drains right away. Converters, and loaders, pause before each item too, and transform functions can pause midway with `pause.Checkpoint`. Two runs with the same ID can't be in progress at once. The `control` package exposes all of it over HTTP.
+//
+// `WithLoader` makes a loader (`loader.ILoader[In, []ProcessedData]`) and its source the pipeline's extract step: each run first loads the source, then processes the loaded data, appended to the data passed to `Run`, if any. The loader traces, counts, and calls its `OnFinished` as usual, the extract step waits while the pipeline is paused, and counts toward the pipeline's progress. If it fails, no stage runs. `WithLoaderSource` is like `WithLoader`, but each run opens its own source, e.g. an `io.Reader`, which can only be read once.
+//
 // The package handles concurrency safely, ensuring proper synchronization and avoiding race conditions. Errors that occur during concurrent execution are propagated and handled appropriately.
 //
 // ## Applied Best Practices
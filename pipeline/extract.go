@@ -0,0 +1,128 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/status"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Extract is the extract step of a pipeline: it returns the data to be
+// processed. See `WithLoader`.
+type Extract[ProcessedData any] func(ctx context.Context) ([]ProcessedData, error)
+
+// Source opens the source of a loader, for a run. See `WithLoaderSource`.
+type Source[In any] func(ctx context.Context) (In, error)
+
+//////
+// Helpers.
+//////
+
+// steps returns the number of steps of a run, used to compute the progress:
+// the stages, plus the extract step, if any.
+func (p *Pipeline[ProcessedData, ConvertedOut]) steps() int {
+	if p.GetExtract() != nil {
+		return len(p.Stages) + 1
+	}
+
+	return len(p.Stages)
+}
+
+// extract runs the extract step, if any, appending its output to
+// `processingData`. Like processors, it waits while the pipeline, or the
+// run, is paused.
+func (p *Pipeline[ProcessedData, ConvertedOut]) extract(
+	ctx context.Context,
+	processingData []ProcessedData,
+) ([]ProcessedData, error) {
+	if p.GetExtract() == nil {
+		return processingData, nil
+	}
+
+	if shared.IsDraining(ctx) {
+		return nil, shared.ErrInterrupted
+	}
+
+	if shared.IsPaused(ctx) {
+		p.GetLogger().Tracelnf("Pipeline %s is paused. Waiting to be resumed before extracting...", p.GetName())
+
+		if err := shared.WaitResumed(ctx); err != nil {
+			return nil, err
+		}
+
+		p.GetStatus().Set(status.Runnning.String())
+	}
+
+	extracted, err := p.GetExtract()(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	//////
+	// Observability: tracing, metrics, status, logging, etc.
+	//////
+
+	p.GetProgress().Add(1)
+
+	p.SetProgressPercent()
+
+	return append(processingData, extracted...), nil
+}
+
+//////
+// Built-in options.
+//////
+
+// WithLoader sets the extract step of the pipeline: each run loads `source`
+// with `l`, and processes the loaded data, appended to the data passed to
+// `Run`, if any. The loader traces, counts, and calls its `OnFinished` as
+// when run by hand; the extract step waits while the pipeline is paused, and
+// counts toward the pipeline's progress.
+//
+// NOTE: Every run loads the same `source`. Sources read once, such as an
+// `io.Reader`, require `WithLoaderSource`.
+//
+// NOTE: `RunStream` processes its input channel, it doesn't extract.
+func WithLoader[In, ProcessedData, ConvertedOut any](
+	l loader.ILoader[In, []ProcessedData],
+	source In,
+) Func[ProcessedData, ConvertedOut] {
+	return func(p IPipeline[ProcessedData, ConvertedOut]) IPipeline[ProcessedData, ConvertedOut] {
+		p.SetExtract(func(ctx context.Context) ([]ProcessedData, error) {
+			return l.Run(ctx, source)
+		})
+
+		return p
+	}
+}
+
+// WithLoaderSource is like `WithLoader`, but each run opens its own source
+// with `open`, then loads it with `l`. If the opened source is an
+// `io.Closer`, it's closed once loaded.
+func WithLoaderSource[In, ProcessedData, ConvertedOut any](
+	l loader.ILoader[In, []ProcessedData],
+	open Source[In],
+) Func[ProcessedData, ConvertedOut] {
+	return func(p IPipeline[ProcessedData, ConvertedOut]) IPipeline[ProcessedData, ConvertedOut] {
+		p.SetExtract(func(ctx context.Context) ([]ProcessedData, error) {
+			source, err := open(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			if closer, ok := any(source).(io.Closer); ok {
+				defer closer.Close()
+			}
+
+			return l.Run(ctx, source)
+		})
+
+		return p
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"expvar"
 
+	"github.com/thalesfsp/etler/v3/checkpoint"
+	"github.com/thalesfsp/etler/v3/event"
 	"github.com/thalesfsp/etler/v3/internal/shared"
 	"github.com/thalesfsp/etler/v3/task"
 	"github.com/thalesfsp/status"
@@ -30,6 +32,53 @@ type IPipeline[ProcessedData, ConvertedOut any] interface {
 	// SetPause the pipeline.
 	SetPause(state bool)
 
+	// GetRuns returns the reports of the runs in progress.
+	GetRuns() []*RunReport
+
+	// GetRun returns the report of the run in progress `runID`.
+	GetRun(runID string) (*RunReport, error)
+
+	// SetRunPause pauses, or resumes, the run in progress `runID` only.
+	SetRunPause(runID string, state bool) error
+
+	// Cancel cancels the run in progress `runID`.
+	Cancel(runID string) error
+
+	// DrainRun drains the run in progress `runID`.
+	DrainRun(runID string) error
+
+	// Drain drains all the runs in progress.
+	Drain()
+
+	// GetCounterInterrupted returns the `CounterInterrupted` of the pipeline.
+	GetCounterInterrupted() *expvar.Int
+
+	// GetCheckpoint returns the `Checkpoint` store of the pipeline.
+	GetCheckpoint() checkpoint.IStore[ProcessedData, ConvertedOut]
+
+	// SetCheckpoint sets the `Checkpoint` store of the pipeline.
+	SetCheckpoint(store checkpoint.IStore[ProcessedData, ConvertedOut])
+
+	// GetDependencies returns the `Dependencies` of the pipeline.
+	GetDependencies() map[string][]string
+
+	// SetDependencies declares the upstream stages of a stage.
+	SetDependencies(stageName string, upstream ...string)
+
+	// GetExtract returns the `Extract` step of the pipeline.
+	GetExtract() Extract[ProcessedData]
+
+	// SetExtract sets the `Extract` step of the pipeline.
+	SetExtract(extract Extract[ProcessedData])
+
+	// SetHistogram sets the buckets, and percentiles, of the duration
+	// histogram, replacing it.
+	SetHistogram(buckets, percentiles []float64) error
+
+	// GetEventBus returns the bus the pipeline, and its components, publish
+	// their lifecycle events to.
+	GetEventBus() *event.Bus
+
 	// GetOnFinished returns the `OnFinished` function.
 	GetOnFinished() OnFinished[ProcessedData, ConvertedOut]
 
@@ -38,4 +87,17 @@ type IPipeline[ProcessedData, ConvertedOut any] interface {
 
 	// Run the pipeline.
 	Run(ctx context.Context, processedData []ProcessedData) ([]task.Task[ProcessedData, ConvertedOut], error)
+
+	// RunWithReport runs the pipeline, and returns the report of the run.
+	RunWithReport(
+		ctx context.Context,
+		processedData []ProcessedData,
+	) ([]task.Task[ProcessedData, ConvertedOut], *RunReport, error)
+
+	// RunStream runs the pipeline over a stream, in micro-batches.
+	RunStream(
+		ctx context.Context,
+		in <-chan ProcessedData,
+		opts ...StreamFunc,
+	) (<-chan task.Task[ProcessedData, ConvertedOut], <-chan error)
 }
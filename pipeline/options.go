@@ -3,6 +3,8 @@ package pipeline
 import (
 	"context"
 
+	"github.com/thalesfsp/etler/v3/checkpoint"
+	"github.com/thalesfsp/etler/v3/event"
 	"github.com/thalesfsp/etler/v3/task"
 )
 
@@ -31,3 +33,39 @@ func WithOnFinished[ProcessedData, ConvertedOut any](onFinished OnFinished[Proce
 		return p
 	}
 }
+
+// WithDependencies declares that the stage named `stageName` consumes the
+// output of the `upstream` stages. Any declared dependency switches the
+// pipeline to DAG mode: stages without upstream stages receive the original
+// task, independent branches run concurrently, and a stage with several
+// upstream stages (a join) waits for all of them and receives their
+// processed data concatenated, in the declared order.
+func WithDependencies[ProcessedData, ConvertedOut any](stageName string, upstream ...string) Func[ProcessedData, ConvertedOut] {
+	return func(p IPipeline[ProcessedData, ConvertedOut]) IPipeline[ProcessedData, ConvertedOut] {
+		p.SetDependencies(stageName, upstream...)
+
+		return p
+	}
+}
+
+// WithCheckpoint sets the store where sequential runs persist each completed
+// stage's output. A run started with a run ID (see `ContextWithRunID`)
+// resumes from the last stage a previous run with the same ID completed.
+// Checkpoints are deleted once the run completes.
+func WithCheckpoint[ProcessedData, ConvertedOut any](store checkpoint.IStore[ProcessedData, ConvertedOut]) Func[ProcessedData, ConvertedOut] {
+	return func(p IPipeline[ProcessedData, ConvertedOut]) IPipeline[ProcessedData, ConvertedOut] {
+		p.SetCheckpoint(store)
+
+		return p
+	}
+}
+
+// WithSubscriber subscribes `h` to the pipeline's events of `types`, or all
+// if none. See `IPipeline.GetEventBus`.
+func WithSubscriber[ProcessedData, ConvertedOut any](h event.Handler, types ...event.Type) Func[ProcessedData, ConvertedOut] {
+	return func(p IPipeline[ProcessedData, ConvertedOut]) IPipeline[ProcessedData, ConvertedOut] {
+		p.GetEventBus().Subscribe(h, types...)
+
+		return p
+	}
+}
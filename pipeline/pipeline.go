@@ -2,17 +2,24 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"expvar"
 	"fmt"
+	"maps"
+	"sync"
 	"time"
 
 	"github.com/thalesfsp/concurrentloop"
+	"github.com/thalesfsp/etler/v3/checkpoint"
+	"github.com/thalesfsp/etler/v3/event"
 	"github.com/thalesfsp/etler/v3/internal/customapm"
 	"github.com/thalesfsp/etler/v3/internal/logging"
 	"github.com/thalesfsp/etler/v3/internal/metrics"
 	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/report"
 	"github.com/thalesfsp/etler/v3/stage"
 	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/etler/v3/tracing"
 	"github.com/thalesfsp/status"
 	"github.com/thalesfsp/sypl/v2"
 	"github.com/thalesfsp/sypl/v2/level"
@@ -26,17 +33,36 @@ import (
 // Type of the entity.
 const Type = "pipeline"
 
+// ErrInterrupted is the error of runs stopped by a drain. See `Drain`.
+var ErrInterrupted = shared.ErrInterrupted
+
+// RunReport is the report of a run: its status, and the timings, item
+// counts, and errors of its stages, and processors. See `RunWithReport`.
+type RunReport = report.Run
+
 // Pipeline definition.
 type Pipeline[ProcessedData any, ConvertedOut any] struct {
 	// Concurrent determines whether the stage should be run concurrently.
 	ConcurrentStage bool `json:"concurrentStage"`
 
+	// Checkpoint is the store where sequential runs persist each completed
+	// stage's output. See `WithCheckpoint`.
+	Checkpoint checkpoint.IStore[ProcessedData, ConvertedOut] `json:"-"`
+
+	// Dependencies maps a stage name to the names of the upstream stages it
+	// consumes. When set, the pipeline runs as a DAG, and `ConcurrentStage`
+	// is ignored. See `WithDependencies`.
+	Dependencies map[string][]string `json:"dependencies,omitempty"`
+
 	// Logger is the internal logger.
 	Logger sypl.ISypl `json:"-" validate:"required"`
 
 	// Description of the processor.
 	Description string `json:"description"`
 
+	// Extract is the extract step of the pipeline. See `WithLoader`.
+	Extract Extract[ProcessedData] `json:"-"`
+
 	// Name of the processor.
 	Name string `json:"name" validate:"required"`
 
@@ -47,21 +73,40 @@ type Pipeline[ProcessedData any, ConvertedOut any] struct {
 	// Stages to be used ProcessedData the pipeline.
 	Stages []stage.IStage[ProcessedData, ConvertedOut] `json:"stages" validate:"required,gt=0"`
 
+	// Buckets of the duration histogram, in milliseconds. See
+	// `SetHistogram`.
+	Buckets []float64 `json:"buckets,omitempty"`
+
+	// Percentiles reported by the duration histogram. See `SetHistogram`.
+	Percentiles []float64 `json:"percentiles,omitempty"`
+
 	// Metrics.
 	CounterCreated *expvar.Int `json:"counterCreated"`
 	CounterRunning *expvar.Int `json:"counterRunning"`
 	CounterFailed  *expvar.Int `json:"counterFailed"`
 	CounterDone    *expvar.Int `json:"counterDone"`
 
-	CreatedAt       time.Time      `json:"createdAt"`
-	Duration        *expvar.Int    `json:"duration"`
-	Progress        *expvar.Int    `json:"progress"`
-	ProgressPercent *expvar.String `json:"progressPercent"`
-	Status          *expvar.String `json:"status"`
+	// CounterInterrupted counts the runs stopped by a drain.
+	CounterInterrupted *expvar.Int `json:"counterInterrupted"`
+
+	CreatedAt         time.Time          `json:"createdAt"`
+	Duration          *expvar.Int        `json:"duration"`
+	DurationHistogram *metrics.Histogram `json:"durationHistogram"`
+	Progress          *expvar.Int        `json:"progress"`
+	ProgressPercent   *expvar.String     `json:"progressPercent"`
+	Status            *expvar.String     `json:"status"`
 
 	// pause is this pipeline's pause controller. Pausing one pipeline does
 	// not affect any other.
 	pause *shared.PauseController
+
+	// bus is where the pipeline, and its components, publish their
+	// lifecycle events.
+	bus *event.Bus
+
+	// runs in progress, by run ID. See `GetRuns`.
+	runsMu sync.Mutex
+	runs   map[string]*activeRun
 }
 
 //////
@@ -103,6 +148,11 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) GetCounterDone() *expvar.Int {
 	return p.CounterDone
 }
 
+// GetCounterInterrupted returns the `CounterInterrupted` of the pipeline.
+func (p *Pipeline[ProcessedData, ConvertedOut]) GetCounterInterrupted() *expvar.Int {
+	return p.CounterInterrupted
+}
+
 // GetStatus returns the `Status` metric.
 func (p *Pipeline[ProcessedData, ConvertedOut]) GetStatus() *expvar.String {
 	return p.Status
@@ -120,11 +170,20 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) GetPaused() status.Status {
 // SetPause sets the Paused status of THIS pipeline. Its processors pause
 // before their next execution and resume immediately on unpause.
 func (p *Pipeline[ProcessedData, ConvertedOut]) SetPause(state bool) {
+	e := event.Event{
+		Type:      event.Paused,
+		Pipeline:  p.GetName(),
+		Component: Type,
+		Name:      p.GetName(),
+	}
+
 	if state {
 		p.GetStatus().Set(status.Paused.String())
 
 		p.pause.Pause()
 
+		p.GetEventBus().Publish(context.Background(), e)
+
 		return
 	}
 
@@ -132,6 +191,52 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) SetPause(state bool) {
 	p.GetStatus().Set(status.Runnning.String())
 
 	p.pause.Resume()
+
+	e.Type = event.Resumed
+
+	p.GetEventBus().Publish(context.Background(), e)
+}
+
+// GetEventBus returns the bus the pipeline, and its components, publish
+// their lifecycle events to.
+func (p *Pipeline[ProcessedData, ConvertedOut]) GetEventBus() *event.Bus {
+	return p.bus
+}
+
+// GetCheckpoint returns the `Checkpoint` store of the pipeline.
+func (p *Pipeline[ProcessedData, ConvertedOut]) GetCheckpoint() checkpoint.IStore[ProcessedData, ConvertedOut] {
+	return p.Checkpoint
+}
+
+// SetCheckpoint sets the `Checkpoint` store of the pipeline.
+func (p *Pipeline[ProcessedData, ConvertedOut]) SetCheckpoint(store checkpoint.IStore[ProcessedData, ConvertedOut]) {
+	p.Checkpoint = store
+}
+
+// GetDependencies returns the `Dependencies` of the pipeline.
+func (p *Pipeline[ProcessedData, ConvertedOut]) GetDependencies() map[string][]string {
+	return p.Dependencies
+}
+
+// SetDependencies declares that the stage named `stageName` consumes the
+// output of the `upstream` stages. Calling it again for the same stage
+// replaces its upstream stages.
+func (p *Pipeline[ProcessedData, ConvertedOut]) SetDependencies(stageName string, upstream ...string) {
+	if p.Dependencies == nil {
+		p.Dependencies = make(map[string][]string)
+	}
+
+	p.Dependencies[stageName] = upstream
+}
+
+// GetExtract returns the `Extract` step of the pipeline.
+func (p *Pipeline[ProcessedData, ConvertedOut]) GetExtract() Extract[ProcessedData] {
+	return p.Extract
+}
+
+// SetExtract sets the `Extract` step of the pipeline.
+func (p *Pipeline[ProcessedData, ConvertedOut]) SetExtract(extract Extract[ProcessedData]) {
+	p.Extract = extract
 }
 
 // GetOnFinished returns the `OnFinished` function.
@@ -159,17 +264,57 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) GetDuration() *expvar.Int {
 	return p.Duration
 }
 
+// GetDurationHistogram returns the `DurationHistogram` of the pipeline.
+func (p *Pipeline[ProcessedData, ConvertedOut]) GetDurationHistogram() *metrics.Histogram {
+	return p.DurationHistogram
+}
+
+// SetHistogram sets the buckets, in milliseconds, and the percentiles of the
+// duration histogram, replacing it. If none, the defaults, or those set
+// through the `ETLER_METRICS_BUCKETS`, and `ETLER_METRICS_PERCENTILES`, env
+// vars, are used.
+func (p *Pipeline[ProcessedData, ConvertedOut]) SetHistogram(buckets, percentiles []float64) error {
+	p.Buckets = buckets
+	p.Percentiles = percentiles
+
+	durationHistogram, err := p.newDurationHistogram()
+	if err != nil {
+		return err
+	}
+
+	p.DurationHistogram = durationHistogram
+
+	return nil
+}
+
+// newDurationHistogram returns a new duration histogram, with the pipeline's
+// buckets, and percentiles.
+func (p *Pipeline[ProcessedData, ConvertedOut]) newDurationHistogram() (*metrics.Histogram, error) {
+	return metrics.NewHistogramWithPattern(
+		Type,
+		p.GetName(),
+		"duration",
+		metrics.WithBuckets(p.Buckets...),
+		metrics.WithPercentiles(p.Percentiles...),
+	)
+}
+
 // GetMetrics returns the stage's metrics.
 func (p *Pipeline[ProcessedData, ConvertedOut]) GetMetrics() map[string]string {
-	return map[string]string{
-		"createdAt":      p.GetCreatedAt().String(),
-		"counterCreated": p.GetCounterCreated().String(),
-		"counterDone":    p.GetCounterDone().String(),
-		"counterFailed":  p.GetCounterFailed().String(),
-		"counterRunning": p.GetCounterRunning().String(),
-		"duration":       p.GetDuration().String(),
-		"status":         p.GetStatus().String(),
+	m := map[string]string{
+		"createdAt":          p.GetCreatedAt().String(),
+		"counterCreated":     p.GetCounterCreated().String(),
+		"counterDone":        p.GetCounterDone().String(),
+		"counterFailed":      p.GetCounterFailed().String(),
+		"counterInterrupted": p.GetCounterInterrupted().String(),
+		"counterRunning":     p.GetCounterRunning().String(),
+		"duration":           p.GetDuration().String(),
+		"status":             p.GetStatus().String(),
 	}
+
+	maps.Copy(m, p.GetDurationHistogram().Metrics("duration"))
+
+	return m
 }
 
 // UpdateObservability updates the observability of the pipeline. `tasksOut`
@@ -188,7 +333,11 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) UpdateObservability(
 
 	p.GetCounterDone().Add(1)
 
-	p.GetDuration().Set(time.Since(now).Milliseconds())
+	elapsed := time.Since(now)
+
+	p.GetDuration().Set(elapsed.Milliseconds())
+
+	p.GetDurationHistogram().Observe(float64(elapsed.Microseconds()) / 1000)
 
 	if p.GetOnFinished() != nil {
 		p.GetOnFinished()(ctx, p, originalTask, tasksOut)
@@ -223,7 +372,7 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) GetProgressPercent() *expvar.Str
 func (p *Pipeline[ProcessedData, ConvertedOut]) SetProgressPercent() {
 	currentProgress := p.GetProgress().Value()
 
-	totalProgress := len(p.Stages)
+	totalProgress := p.steps()
 	if totalProgress == 0 {
 		p.GetProgressPercent().Set("0%")
 
@@ -235,8 +384,74 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) SetProgressPercent() {
 	p.GetProgressPercent().Set(fmt.Sprintf("%d%%", int(percentage)))
 }
 
-// Run the pipeline.
+// Run the pipeline. See `RunWithReport`.
 func (p *Pipeline[ProcessedData, ConvertedOut]) Run(ctx context.Context, processingData []ProcessedData) ([]task.Task[ProcessedData, ConvertedOut], error) {
+	tasksOut, _, err := p.RunWithReport(ctx, processingData)
+
+	return tasksOut, err
+}
+
+// RunWithReport runs the pipeline, and returns the report of the run next to
+// the tasks, even if it failed. The run ID is the one carried by `ctx`, see
+// `ContextWithRunID`, or a new one. Either way, it's carried by the context
+// of the stages, and processors, and the run can be paused, or cancelled, by
+// ID while in progress, see `SetRunPause`, and `Cancel`. Two runs with the
+// same ID can't be in progress at once.
+//
+// NOTE: Only runs with a run ID set by the caller are checkpointed.
+func (p *Pipeline[ProcessedData, ConvertedOut]) RunWithReport(
+	ctx context.Context,
+	processingData []ProcessedData,
+) ([]task.Task[ProcessedData, ConvertedOut], *RunReport, error) {
+	checkpointID := RunIDFromCtx(ctx)
+
+	runID := checkpointID
+	if runID == "" {
+		runID = shared.GenerateUUID()
+	}
+
+	r := report.New(runID, p.GetName())
+
+	ctx, untrack, err := p.track(ctx, runID, r)
+	if err != nil {
+		return nil, r.Finish(err), err
+	}
+
+	defer untrack()
+
+	ctx = report.ContextWithRecorder(ContextWithRunID(ctx, runID), r)
+	ctx = event.ContextWithBus(ctx, p.GetEventBus(), runID, p.GetName())
+
+	event.Publish(ctx, event.Event{
+		Type:      event.RunStarted,
+		Component: Type,
+		Name:      p.GetName(),
+		ItemsIn:   len(processingData),
+	})
+
+	tasksOut, err := p.run(ctx, processingData, checkpointID)
+
+	runReport := r.Finish(err)
+
+	event.Publish(ctx, event.Event{
+		Type:      event.Outcome(err, event.RunFinished, event.RunFailed, event.RunInterrupted),
+		Component: Type,
+		Name:      p.GetName(),
+		TaskID:    runReport.TaskID,
+		Duration:  runReport.Duration,
+		ItemsIn:   runReport.ItemsIn,
+		Err:       err,
+	})
+
+	return tasksOut, runReport, err
+}
+
+// run is `RunWithReport`, checkpointing under `checkpointID`, if set.
+func (p *Pipeline[ProcessedData, ConvertedOut]) run(
+	ctx context.Context,
+	processingData []ProcessedData,
+	checkpointID string,
+) ([]task.Task[ProcessedData, ConvertedOut], error) {
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -254,17 +469,6 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) Run(ctx context.Context, process
 	// Make this pipeline's pause controller visible to its processors.
 	tracedContext = shared.ContextWithPause(tracedContext, p.pause)
 
-	// Task initialization.
-	tsk, err := task.New[ProcessedData, ConvertedOut](processingData)
-	if err != nil {
-		return nil, customapm.TraceError(
-			tracedContext,
-			err,
-			p.GetLogger(),
-			p.GetCounterFailed(),
-		)
-	}
-
 	// A paused pipeline keeps reporting paused — its processors are about to
 	// block on the pause controller.
 	if !p.pause.Paused() {
@@ -280,6 +484,53 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) Run(ctx context.Context, process
 
 	now := time.Now()
 
+	//////
+	// Extract, if the pipeline has a loader.
+	//////
+
+	processingData, err := p.extract(tracedContext, processingData)
+	if errors.Is(err, shared.ErrInterrupted) {
+		return p.interrupt(nil)
+	}
+
+	if err != nil {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return nil, shared.OnErrorHandler(
+			tracedContext,
+			p,
+			p.GetLogger(),
+			err,
+			"extract",
+			Type,
+			p.GetName(),
+		)
+	}
+
+	// Task initialization.
+	tsk, err := task.New[ProcessedData, ConvertedOut](processingData)
+	if err != nil {
+		return nil, customapm.TraceError(
+			tracedContext,
+			err,
+			p.GetLogger(),
+			p.GetCounterFailed(),
+		)
+	}
+
+	tracedContext = customapm.Annotate(
+		tracedContext,
+		span,
+		tracing.String(tracing.KeyRunID, RunIDFromCtx(ctx)),
+		tracing.String(tracing.KeyTaskID, tsk.ID),
+	)
+
+	report.FromContext(ctx).SetTask(tsk.ID, len(processingData))
+
+	span.SetAttributes(tracing.Int(tracing.KeyItemsIn, len(processingData)))
+
 	//////
 	// Run the pipeline.
 	//////
@@ -290,6 +541,11 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) Run(ctx context.Context, process
 	// Store as reference to be used as the input of the next stage.
 	retroFeedIn := originalTask
 
+	// Declared dependencies take precedence over the concurrency mode.
+	if len(p.GetDependencies()) > 0 {
+		return p.runDAG(ctx, tracedContext, now, originalTask)
+	}
+
 	if p.ConcurrentStage {
 		stagesOut, errs := concurrentloop.Map(tracedContext, p.Stages, func(ctx context.Context, s stage.IStage[ProcessedData, ConvertedOut]) (task.Task[ProcessedData, ConvertedOut], error) {
 			stageOut, err := s.Run(tracedContext, originalTask)
@@ -315,6 +571,10 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) Run(ctx context.Context, process
 
 			return stageOut, nil
 		}, concurrentloop.WithRemoveZeroValues(false))
+		if interrupted(errs...) {
+			return p.interrupt(stagesOut)
+		}
+
 		if errs != nil {
 			//////
 			// Observability: tracing, metrics, status, logging, etc.
@@ -350,8 +610,55 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) Run(ctx context.Context, process
 	// stage order. The final task is the last element.
 	tasksOut := make([]task.Task[ProcessedData, ConvertedOut], 0, len(p.Stages))
 
-	for _, s := range p.Stages {
+	// With a checkpoint store, and a run ID, a run resumes from its last
+	// completed stage.
+	runID := checkpointID
+
+	resumed, err := p.resume(tracedContext, runID, originalTask)
+	if err != nil {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return nil, shared.OnErrorHandler(
+			tracedContext,
+			p,
+			p.GetLogger(),
+			err,
+			"resume from checkpoint",
+			Type,
+			p.GetName(),
+		)
+	}
+
+	if len(resumed) > 0 {
+		tasksOut = append(tasksOut, resumed...)
+
+		retroFeedIn = resumed[len(resumed)-1]
+
+		p.GetProgress().Add(int64(len(resumed)))
+
+		p.SetProgressPercent()
+
+		p.GetLogger().PrintlnWithOptions(
+			level.Debug,
+			fmt.Sprintf("resuming run %s after %d completed stage(s)", runID, len(resumed)),
+		)
+	}
+
+	for i := len(resumed); i < len(p.Stages); i++ {
+		// A draining run stops before its next stage.
+		if shared.IsDraining(tracedContext) {
+			return p.interrupt(tasksOut)
+		}
+
+		s := p.Stages[i]
+
 		rFI, err := s.Run(tracedContext, retroFeedIn)
+		if errors.Is(err, shared.ErrInterrupted) {
+			return p.interrupt(tasksOut)
+		}
+
 		if err != nil {
 			//////
 			// Observability: tracing, metrics, status, logging, etc.
@@ -368,6 +675,22 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) Run(ctx context.Context, process
 			)
 		}
 
+		if err := p.save(tracedContext, runID, i, rFI); err != nil {
+			//////
+			// Observability: tracing, metrics, status, logging, etc.
+			//////
+
+			return nil, shared.OnErrorHandler(
+				tracedContext,
+				p,
+				p.GetLogger(),
+				err,
+				"save checkpoint",
+				Type,
+				p.GetName(),
+			)
+		}
+
 		// Update reference to be used as the input of the next stage.
 		retroFeedIn = rFI
 
@@ -387,6 +710,23 @@ func (p *Pipeline[ProcessedData, ConvertedOut]) Run(ctx context.Context, process
 		p.SetProgressPercent()
 	}
 
+	// The run is complete: there's nothing left to resume.
+	if err := p.clear(tracedContext, runID); err != nil {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return nil, shared.OnErrorHandler(
+			tracedContext,
+			p,
+			p.GetLogger(),
+			err,
+			"clear checkpoints",
+			Type,
+			p.GetName(),
+		)
+	}
+
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -412,6 +752,8 @@ func New[ProcessedData, ConvertedOut any](
 		Stages:          stages,
 		Logger:          logging.Get().New(name).SetTags(Type, name),
 		pause:           shared.NewPauseController(),
+		bus:             event.NewBus(),
+		runs:            make(map[string]*activeRun),
 
 		CreatedAt:   time.Now(),
 		Name:        name,
@@ -422,6 +764,8 @@ func New[ProcessedData, ConvertedOut any](
 		CounterFailed:  metrics.NewIntWithPattern(Type, name, status.Failed),
 		CounterRunning: metrics.NewIntWithPattern(Type, name, status.Runnning),
 
+		CounterInterrupted: metrics.NewIntWithPattern(Type, name, status.Interrupted),
+
 		Duration:        metrics.NewIntWithPattern(Type, name, "duration"),
 		Progress:        metrics.NewIntWithPattern(Type, name, "progress"),
 		ProgressPercent: metrics.NewStringWithPattern(Type, name, "progressPercent"),
@@ -433,6 +777,19 @@ func New[ProcessedData, ConvertedOut any](
 		return nil, err
 	}
 
+	durationHistogram, err := p.newDurationHistogram()
+	if err != nil {
+		return nil, err
+	}
+
+	p.DurationHistogram = durationHistogram
+
+	// Stages, and processors, paused on their own publish to the pipeline's
+	// bus too.
+	for _, s := range p.Stages {
+		s.SetEventBus(p.GetEventBus(), p.GetName())
+	}
+
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -0,0 +1,171 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/checkpoint"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/stage"
+	"github.com/thalesfsp/status"
+)
+
+// newFlakyStage returns a stage that fails while `fail` is set, and counts
+// its runs.
+func newFlakyStage(t *testing.T, name string, fail *atomic.Bool, runs *atomic.Int32) stage.IStage[int, int] {
+	t.Helper()
+
+	flaky, err := processor.New(
+		name+"-flaky",
+		"fails on demand",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			runs.Add(1)
+
+			if fail.Load() {
+				return nil, errors.New("boom-flaky")
+			}
+
+			out := make([]int, len(processingData))
+
+			for i, v := range processingData {
+				out[i] = v * 10
+			}
+
+			return out, nil
+		},
+	)
+	require.NoError(t, err)
+
+	stg, err := stage.New(name, "flaky stage", converter.MustDefault(
+		func(ctx context.Context, in int) (int, error) { return in, nil },
+	), flaky)
+	require.NoError(t, err)
+
+	return stg
+}
+
+// Happy path: a run failing at the last stage resumes from it, without
+// redoing the completed stages; checkpoints are cleared once it completes.
+func TestPipeline_checkpoint_resumesFromLastCompletedStage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for name, store := range map[string]func(t *testing.T) checkpoint.IStore[int, int]{
+		"memory": func(t *testing.T) checkpoint.IStore[int, int] {
+			return checkpoint.NewMemory[int, int]()
+		},
+		"file": func(t *testing.T) checkpoint.IStore[int, int] {
+			f, err := checkpoint.NewFile[int, int](t.TempDir())
+			require.NoError(t, err)
+
+			return f
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var (
+				fail      atomic.Bool
+				firstRuns atomic.Int32
+				lastRuns  atomic.Int32
+				never     atomic.Bool
+			)
+
+			p, err := New("checkpoint-"+name, "resumable pipeline", false,
+				newFlakyStage(t, "checkpoint-first-"+name, &never, &firstRuns),
+				newMapStage(t, "checkpoint-second-"+name, func(v int) int { return v + 1 }),
+				newFlakyStage(t, "checkpoint-last-"+name, &fail, &lastRuns),
+			)
+			require.NoError(t, err)
+
+			cp := store(t)
+
+			WithCheckpoint[int, int](cp)(p)
+
+			runCtx := ContextWithRunID(ctx, "nightly-1")
+
+			fail.Store(true)
+
+			_, err = p.Run(runCtx, []int{1, 2})
+			require.Error(t, err)
+
+			saved, err := cp.Load(ctx, p.GetName(), "nightly-1")
+			require.NoError(t, err)
+			require.Len(t, saved, 2, "the two completed stages must be checkpointed")
+
+			fail.Store(false)
+
+			out, err := p.Run(runCtx, []int{1, 2})
+			require.NoError(t, err)
+			require.Len(t, out, 3)
+
+			assert.Equal(t, []int{10, 20}, out[0].ConvertedData)
+			assert.Equal(t, []int{11, 21}, out[1].ConvertedData)
+			assert.Equal(t, []int{110, 210}, out[2].ConvertedData)
+
+			assert.Equal(t, int32(1), firstRuns.Load(), "completed stages must not run again")
+			assert.Equal(t, int32(2), lastRuns.Load())
+			assert.Equal(t, "100%", p.GetProgressPercent().Value())
+
+			saved, err = cp.Load(ctx, p.GetName(), "nightly-1")
+			require.NoError(t, err)
+			assert.Empty(t, saved, "checkpoints must be cleared once the run completes")
+		})
+	}
+}
+
+// Edge case: without a run ID, nothing is checkpointed.
+func TestPipeline_checkpoint_noRunID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var (
+		fail atomic.Bool
+		runs atomic.Int32
+	)
+
+	p, err := New("checkpoint-no-run-id", "no run id", false,
+		newMapStage(t, "checkpoint-no-run-id-a", func(v int) int { return v }),
+		newFlakyStage(t, "checkpoint-no-run-id-b", &fail, &runs),
+	)
+	require.NoError(t, err)
+
+	cp := checkpoint.NewMemory[int, int]()
+
+	WithCheckpoint[int, int](cp)(p)
+
+	fail.Store(true)
+
+	_, err = p.Run(ctx, []int{1})
+	require.Error(t, err)
+
+	saved, err := cp.Load(ctx, p.GetName(), "")
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+}
+
+// Bad path: checkpoints that don't match the pipeline's stages are rejected.
+func TestPipeline_checkpoint_mismatchedStages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := New("checkpoint-mismatch", "mismatched checkpoints", false,
+		newMapStage(t, "checkpoint-mismatch-a", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	cp := checkpoint.NewMemory[int, int]()
+
+	require.NoError(t, cp.Save(ctx, p.GetName(), "run", checkpoint.Checkpoint[int, int]{Index: 0, Stage: "renamed"}))
+
+	WithCheckpoint[int, int](cp)(p)
+
+	_, err = p.Run(ContextWithRunID(ctx, "run"), []int{1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "renamed")
+	assert.Equal(t, status.Failed.String(), p.GetStatus().Value())
+}
@@ -0,0 +1,335 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/event"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/stage"
+	"github.com/thalesfsp/status"
+)
+
+// newBlockingStage returns a stage whose processor signals `started`, then
+// blocks until `release` is closed, or its context is done.
+func newBlockingStage(t *testing.T, name string, started chan<- string, release <-chan struct{}) stage.IStage[int, int] {
+	t.Helper()
+
+	blocker, err := processor.New(
+		name+"-block",
+		"blocks until released",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			started <- RunIDFromCtx(ctx)
+
+			select {
+			case <-release:
+				return processingData, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	)
+	require.NoError(t, err)
+
+	stg, err := stage.New(name, "blocking stage", converter.MustDefault(
+		func(ctx context.Context, in int) (int, error) { return in, nil },
+	), blocker)
+	require.NoError(t, err)
+
+	return stg
+}
+
+// Happy path: a run in progress is listed, and cancelled, by ID.
+func TestPipeline_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	started := make(chan string, 1)
+
+	p, err := New("control-cancel", "cancelled pipeline", false,
+		newBlockingStage(t, "control-cancel-block", started, make(chan struct{})),
+	)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := p.Run(ContextWithRunID(ctx, "cancel-1"), []int{1})
+
+		done <- err
+	}()
+
+	assert.Equal(t, "cancel-1", <-started)
+
+	runs := p.GetRuns()
+	require.Len(t, runs, 1)
+	assert.Equal(t, "cancel-1", runs[0].RunID)
+	assert.Equal(t, status.Runnning.String(), runs[0].Status)
+
+	r, err := p.GetRun("cancel-1")
+	require.NoError(t, err)
+	assert.Equal(t, "control-cancel", r.Pipeline)
+
+	// Bad path: two runs with the same ID can't be in progress at once.
+	_, err = p.Run(ContextWithRunID(ctx, "cancel-1"), []int{1})
+	require.Error(t, err)
+
+	require.NoError(t, p.Cancel("cancel-1"))
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run wasn't cancelled")
+	}
+
+	assert.Empty(t, p.GetRuns())
+
+	// Bad path: finished, or unknown, runs can't be controlled.
+	err = p.Cancel("cancel-1")
+	require.Error(t, err)
+	assert.True(t, customerror.IsHTTPStatus(err, 404))
+
+	_, err = p.GetRun("cancel-1")
+	require.Error(t, err)
+
+	require.Error(t, p.SetRunPause("cancel-1", true))
+}
+
+// Happy path: pausing a run doesn't pause the others.
+func TestPipeline_SetRunPause(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	started := make(chan string, 2)
+	release := make(chan struct{})
+
+	p, err := New("control-pause", "paused pipeline", false,
+		newBlockingStage(t, "control-pause-block", started, release),
+		newMapStage(t, "control-pause-map", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	done := map[string]chan error{"paused": make(chan error, 1), "running": make(chan error, 1)}
+
+	for runID, ch := range done {
+		go func() {
+			_, err := p.Run(ContextWithRunID(ctx, runID), []int{1})
+
+			ch <- err
+		}()
+	}
+
+	<-started
+	<-started
+
+	require.NoError(t, p.SetRunPause("paused", true))
+
+	r, err := p.GetRun("paused")
+	require.NoError(t, err)
+	assert.Equal(t, status.Paused.String(), r.Status)
+
+	close(release)
+
+	// The other run isn't paused.
+	select {
+	case err := <-done["running"]:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run which isn't paused didn't finish")
+	}
+
+	// The paused run waits before its next processor.
+	select {
+	case <-done["paused"]:
+		t.Fatal("the paused run finished")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	require.NoError(t, p.SetRunPause("paused", false))
+
+	select {
+	case err := <-done["paused"]:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the resumed run didn't finish")
+	}
+}
+
+// Happy path: a drained run finishes the stage in flight, and returns its
+// task, interrupted.
+func TestPipeline_Drain(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	started := make(chan string, 1)
+	release := make(chan struct{})
+
+	p, err := New("control-drain", "drained pipeline", false,
+		newBlockingStage(t, "control-drain-block", started, release),
+		newMapStage(t, "control-drain-map", func(v int) int { return v * 2 }),
+	)
+	require.NoError(t, err)
+
+	interruptedBefore := p.GetCounterInterrupted().Value()
+
+	outcomes := &recorder{}
+
+	WithSubscriber[int, int](
+		outcomes.handle,
+		event.RunFailed, event.RunInterrupted, event.StageFailed, event.StageInterrupted,
+	)(p)
+
+	type result struct {
+		tasks []int
+		r     *RunReport
+		err   error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		tasks, r, err := p.RunWithReport(ctx, []int{1, 2})
+
+		res := result{r: r, err: err}
+
+		for _, tsk := range tasks {
+			res.tasks = append(res.tasks, tsk.ConvertedData...)
+		}
+
+		done <- res
+	}()
+
+	<-started
+
+	p.Drain()
+
+	// The processor in flight finishes.
+	close(release)
+
+	var res result
+
+	select {
+	case res = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the drained run didn't return")
+	}
+
+	require.ErrorIs(t, res.err, ErrInterrupted)
+
+	// Only the first stage ran, and completed.
+	assert.Equal(t, []int{1, 2}, res.tasks)
+
+	require.NotNil(t, res.r)
+	assert.Equal(t, status.Interrupted.String(), res.r.Status)
+	require.Len(t, res.r.Stages, 1)
+	assert.Equal(t, status.Done.String(), res.r.Stages[0].Status)
+
+	assert.Equal(t, status.Interrupted.String(), p.GetStatus().Value())
+	assert.Equal(t, interruptedBefore+1, p.GetCounterInterrupted().Value())
+
+	// A drain isn't published as a failure.
+	assert.Equal(t, []event.Type{event.RunInterrupted}, outcomes.types())
+
+	// Edge case: runs started afterwards aren't affected.
+	tasks, err := p.Run(ctx, []int{3})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+}
+
+// Happy path: a stage draining stops before its next processor.
+func TestPipeline_DrainRun_stage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	started := make(chan string, 1)
+	release := make(chan struct{})
+
+	blocking := newBlockingStage(t, "control-drain-stage", started, release)
+
+	next, err := processor.New("control-drain-stage-next", "never runs", func(ctx context.Context, in []int) ([]int, error) {
+		t.Error("a processor started after the drain")
+
+		return in, nil
+	})
+	require.NoError(t, err)
+
+	stg, err := stage.New("control-drain-stage", "two processors", converter.MustDefault(
+		func(ctx context.Context, in int) (int, error) { return in, nil },
+	), append(blocking.(*stage.Stage[int, int]).Processors, next)...)
+	require.NoError(t, err)
+
+	p, err := New("control-drain-stage", "drained stage", false, stg)
+	require.NoError(t, err)
+
+	interruptedBefore := stg.GetCounterInterrupted().Value()
+
+	done := make(chan error, 1)
+
+	go func() {
+		tasks, err := p.Run(ContextWithRunID(ctx, "drain-stage-1"), []int{1})
+		assert.Empty(t, tasks)
+
+		done <- err
+	}()
+
+	<-started
+
+	require.NoError(t, p.DrainRun("drain-stage-1"))
+
+	close(release)
+
+	require.ErrorIs(t, <-done, ErrInterrupted)
+
+	assert.Equal(t, status.Interrupted.String(), stg.GetStatus().Value())
+	assert.Equal(t, interruptedBefore+1, stg.GetCounterInterrupted().Value())
+
+	// Bad path: finished runs can't be drained.
+	require.Error(t, p.DrainRun("drain-stage-1"))
+}
+
+// Edge case: a paused run drains right away.
+func TestPipeline_DrainRun_paused(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mapped := newMapStage(t, "control-drain-paused", func(v int) int { return v })
+
+	p, err := New("control-drain-paused", "paused pipeline", false, mapped)
+	require.NoError(t, err)
+
+	p.SetPause(true)
+	defer p.SetPause(false)
+
+	done := make(chan error, 1)
+	reports := make(chan *RunReport, 1)
+
+	go func() {
+		_, r, err := p.RunWithReport(ContextWithRunID(ctx, "drain-paused-1"), []int{1})
+
+		reports <- r
+		done <- err
+	}()
+
+	proc := mapped.(*stage.Stage[int, int]).Processors[0]
+
+	require.Eventually(t, func() bool {
+		return proc.GetStatus().Value() == status.Paused.String()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, p.DrainRun("drain-paused-1"))
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, ErrInterrupted)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the paused run didn't drain")
+	}
+
+	assert.Equal(t, status.Interrupted.String(), proc.GetStatus().Value())
+}
@@ -0,0 +1,226 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/stage"
+	"github.com/thalesfsp/status"
+)
+
+// newMapStage returns a stage whose single processor applies `fn` to every
+// item, and an identity converter.
+func newMapStage(t *testing.T, name string, fn func(int) int) stage.IStage[int, int] {
+	t.Helper()
+
+	mapper, err := processor.New(
+		name+"-map",
+		"maps every item",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			out := make([]int, len(processingData))
+
+			for i, v := range processingData {
+				out[i] = fn(v)
+			}
+
+			return out, nil
+		},
+	)
+	require.NoError(t, err)
+
+	stg, err := stage.New(
+		name,
+		"map stage",
+		converter.MustDefault(
+			func(ctx context.Context, in int) (int, error) {
+				return in, nil
+			},
+		),
+		mapper,
+	)
+	require.NoError(t, err)
+
+	return stg
+}
+
+// Happy path: a diamond (a -> b, a -> c, {b, c} -> d). Branches get a's
+// output, and the join gets both branches' output, in the declared order.
+func TestPipeline_dag_diamond(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := New("dag-diamond", "diamond-shaped pipeline", false,
+		newMapStage(t, "dag-a", func(v int) int { return v + 1 }),
+		newMapStage(t, "dag-b", func(v int) int { return v * 2 }),
+		newMapStage(t, "dag-c", func(v int) int { return v * 10 }),
+		newMapStage(t, "dag-d", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	WithDependencies[int, int]("dag-b", "dag-a")(p)
+	WithDependencies[int, int]("dag-c", "dag-a")(p)
+	WithDependencies[int, int]("dag-d", "dag-b", "dag-c")(p)
+
+	out, err := p.Run(ctx, []int{1, 2})
+	require.NoError(t, err)
+
+	// One task per stage, in stage order.
+	require.Len(t, out, 4)
+
+	assert.Equal(t, []int{2, 3}, out[0].ConvertedData)
+	assert.Equal(t, []int{4, 6}, out[1].ConvertedData)
+	assert.Equal(t, []int{20, 30}, out[2].ConvertedData)
+	assert.Equal(t, []int{4, 6, 20, 30}, out[3].ConvertedData)
+
+	// Every stage works on the same task.
+	for _, tsk := range out {
+		assert.Equal(t, out[0].ID, tsk.ID)
+	}
+
+	assert.Equal(t, status.Done.String(), p.GetStatus().Value())
+	assert.Equal(t, "100%", p.GetProgressPercent().Value())
+	assert.Equal(t, int64(1), p.GetCounterDone().Value())
+}
+
+// Independent branches run concurrently: two roots that each wait for the
+// other to start can only finish if they overlap.
+func TestPipeline_dag_independentBranchesRunConcurrently(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var started atomic.Int32
+
+	rendezvous := func(name string) stage.IStage[int, int] {
+		proc, err := processor.New(
+			name+"-rendezvous",
+			"waits for the other branch",
+			func(ctx context.Context, processingData []int) ([]int, error) {
+				started.Add(1)
+
+				for started.Load() < 2 {
+					select {
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					case <-time.After(5 * time.Millisecond):
+					}
+				}
+
+				return processingData, nil
+			},
+		)
+		require.NoError(t, err)
+
+		stg, err := stage.New(name, "rendezvous stage", converter.MustDefault(
+			func(ctx context.Context, in int) (int, error) { return in, nil },
+		), proc)
+		require.NoError(t, err)
+
+		return stg
+	}
+
+	p, err := New("dag-concurrent", "independent branches", false,
+		rendezvous("dag-left"),
+		rendezvous("dag-right"),
+		newMapStage(t, "dag-join", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	WithDependencies[int, int]("dag-join", "dag-left", "dag-right")(p)
+
+	out, err := p.Run(ctx, []int{7})
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+	assert.Equal(t, []int{7, 7}, out[2].ConvertedData)
+}
+
+// Bad path: a failing branch fails the pipeline, and its downstream join
+// never runs.
+func TestPipeline_dag_failingBranch_skipsDownstream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	boom := errors.New("boom-dag")
+
+	var joinRan atomic.Bool
+
+	p, err := New("dag-failing", "failing branch", false,
+		newMapStage(t, "dag-fail-ok", func(v int) int { return v }),
+		newFailingStage(t, "dag-fail-bad", boom),
+		newMapStage(t, "dag-fail-join", func(v int) int {
+			joinRan.Store(true)
+
+			return v
+		}),
+	)
+	require.NoError(t, err)
+
+	WithDependencies[int, int]("dag-fail-join", "dag-fail-ok", "dag-fail-bad")(p)
+
+	out, err := p.Run(ctx, []int{1})
+	assert.Nil(t, out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom-dag")
+
+	assert.False(t, joinRan.Load(), "a join must not run when an upstream failed")
+	assert.Equal(t, status.Failed.String(), p.GetStatus().Value())
+}
+
+// Bad path: invalid graphs are rejected before any stage runs.
+func TestPipeline_dag_invalidGraphs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		deps    map[string][]string
+		wantErr string
+	}{
+		{
+			name:    "cycle",
+			deps:    map[string][]string{"dag-inv-x": {"dag-inv-y"}, "dag-inv-y": {"dag-inv-x"}},
+			wantErr: "cycle",
+		},
+		{
+			name:    "unknown upstream",
+			deps:    map[string][]string{"dag-inv-x": {"nope"}},
+			wantErr: `"nope"`,
+		},
+		{
+			name:    "unknown stage",
+			deps:    map[string][]string{"nope": {"dag-inv-x"}},
+			wantErr: `"nope"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran atomic.Bool
+
+			p, err := New("dag-invalid-"+tt.name, "invalid graph", false,
+				newMapStage(t, "dag-inv-x", func(v int) int {
+					ran.Store(true)
+
+					return v
+				}),
+				newMapStage(t, "dag-inv-y", func(v int) int { return v }),
+			)
+			require.NoError(t, err)
+
+			for stageName, upstream := range tt.deps {
+				p.SetDependencies(stageName, upstream...)
+			}
+
+			_, err = p.Run(ctx, []int{1})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.False(t, ran.Load())
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/event"
+	"github.com/thalesfsp/etler/v3/stage"
+)
+
+// recorder records the events it handles.
+type recorder struct {
+	mu     sync.Mutex
+	events []event.Event
+}
+
+func (r *recorder) handle(_ context.Context, e event.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, e)
+}
+
+// lifecycle returns the recorded events, except converters', which are per
+// item.
+func (r *recorder) lifecycle() []event.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []event.Event{}
+
+	for _, e := range r.events {
+		if e.Component != "converter" {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// types returns the types of the recorded events, except converters'.
+func (r *recorder) types() []event.Type {
+	types := []event.Type{}
+
+	for _, e := range r.lifecycle() {
+		types = append(types, e.Type)
+	}
+
+	return types
+}
+
+// Happy path: a run publishes the lifecycle events of the pipeline, its
+// stages, processors, and converters, to every subscriber.
+func TestPipeline_events(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	all := &recorder{}
+	converters := &recorder{}
+
+	p, err := New("events", "evented pipeline", false,
+		newMapStage(t, "events-first", func(v int) int { return v + 1 }),
+		newMapStage(t, "events-second", func(v int) int { return v * 2 }),
+	)
+	require.NoError(t, err)
+
+	WithSubscriber[int, int](all.handle)(p)
+	WithSubscriber[int, int](converters.handle, event.ConverterFinished)(p)
+
+	_, r, err := p.RunWithReport(ctx, []int{1, 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, []event.Type{
+		event.RunStarted,
+		event.StageStarted,
+		event.ProcessorStarted,
+		event.ProcessorFinished,
+		event.StageFinished,
+		event.StageStarted,
+		event.ProcessorStarted,
+		event.ProcessorFinished,
+		event.StageFinished,
+		event.RunFinished,
+	}, all.types())
+
+	for _, e := range all.events {
+		assert.Equal(t, r.RunID, e.RunID)
+		assert.Equal(t, "events", e.Pipeline)
+	}
+
+	events := all.lifecycle()
+
+	stageFinished := events[4]
+
+	assert.Equal(t, "stage", stageFinished.Component)
+	assert.Equal(t, "events-first", stageFinished.Name)
+	assert.Equal(t, r.TaskID, stageFinished.TaskID)
+	assert.Equal(t, 2, stageFinished.ItemsIn)
+	assert.Equal(t, 2, stageFinished.ItemsOut)
+	assert.Positive(t, stageFinished.Duration)
+
+	processorFinished := events[3]
+
+	assert.Equal(t, "events-first-map", processorFinished.Name)
+	assert.Equal(t, r.TaskID, processorFinished.TaskID)
+
+	runFinished := events[len(events)-1]
+
+	assert.Equal(t, r.TaskID, runFinished.TaskID)
+
+	// Two items, two stages.
+	require.Len(t, converters.events, 4)
+	assert.Equal(t, event.ConverterFinished, converters.events[0].Type)
+}
+
+// Bad path: failures are published, with their error.
+func TestPipeline_events_failed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var fail atomic.Bool
+
+	fail.Store(true)
+
+	failed := &recorder{}
+
+	p, err := New("events-failed", "failing pipeline", false,
+		newFlakyStage(t, "events-failed-stage", &fail, new(atomic.Int32)),
+	)
+	require.NoError(t, err)
+
+	WithSubscriber[int, int](failed.handle, event.ProcessorFailed, event.StageFailed, event.RunFailed)(p)
+
+	_, err = p.Run(ctx, []int{1})
+	require.Error(t, err)
+
+	assert.Equal(t, []event.Type{event.ProcessorFailed, event.StageFailed, event.RunFailed}, failed.types())
+
+	for _, e := range failed.events {
+		assert.Error(t, e.Err)
+		assert.Contains(t, e.Error, "boom-flaky")
+	}
+}
+
+// Happy path: pausing, and resuming, are published.
+func TestPipeline_events_pause(t *testing.T) {
+	paused := &recorder{}
+
+	p, err := New("events-pause", "paused pipeline", false,
+		newMapStage(t, "events-pause-stage", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	unsubscribe := p.GetEventBus().Subscribe(paused.handle, event.Paused, event.Resumed)
+
+	p.SetPause(true)
+	p.SetPause(false)
+
+	unsubscribe()
+
+	p.SetPause(true)
+	p.SetPause(false)
+
+	assert.Equal(t, []event.Type{event.Paused, event.Resumed}, paused.types())
+	assert.Equal(t, "events-pause", paused.events[0].Name)
+}
+
+// Happy path: pausing, and resuming, a stage, or a processor, on its own is
+// published to its pipeline's bus.
+func TestPipeline_events_pauseComponents(t *testing.T) {
+	paused := &recorder{}
+
+	stg := newMapStage(t, "events-pause-components-stage", func(v int) int { return v })
+
+	p, err := New("events-pause-components", "paused components", false, stg)
+	require.NoError(t, err)
+
+	p.GetEventBus().Subscribe(paused.handle, event.Paused, event.Resumed)
+
+	s, ok := stg.(*stage.Stage[int, int])
+	require.True(t, ok)
+
+	stg.SetPause(true)
+	s.Processors[0].SetPause(true)
+	s.Processors[0].SetPause(false)
+	stg.SetPause(false)
+
+	assert.Equal(t, []event.Type{event.Paused, event.Paused, event.Resumed, event.Resumed}, paused.types())
+
+	assert.Equal(t, "stage", paused.events[0].Component)
+	assert.Equal(t, "events-pause-components-stage", paused.events[0].Name)
+	assert.Equal(t, "processor", paused.events[1].Component)
+	assert.Equal(t, "events-pause-components-stage-map", paused.events[1].Name)
+
+	for _, e := range paused.events {
+		assert.Equal(t, "events-pause-components", e.Pipeline)
+	}
+}
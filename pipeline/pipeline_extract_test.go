@@ -0,0 +1,210 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/status"
+)
+
+// newCountingLoader returns a loader producing `n` items (1 to `n`) for any
+// source, which records the source it loaded.
+func newCountingLoader(t *testing.T, name string, loaded *atomic.Value) loader.ILoader[int, []int] {
+	t.Helper()
+
+	l, err := loader.New(
+		name,
+		"produces items",
+		func(ctx context.Context, n int) ([]int, error) {
+			loaded.Store(n)
+
+			out := make([]int, 0, n)
+
+			for i := 1; i <= n; i++ {
+				out = append(out, i)
+			}
+
+			return out, nil
+		},
+	)
+	require.NoError(t, err)
+
+	return l
+}
+
+// Happy path: the loader's output feeds the stages, and the extract step
+// counts toward the progress.
+func TestPipeline_extract(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var (
+		loaded       atomic.Value
+		originalData []int
+	)
+
+	l := newCountingLoader(t, "extract-loader", &loaded)
+
+	p, err := New("extract", "pipeline with a loader", false,
+		newMapStage(t, "extract-double", func(v int) int { return v * 2 }),
+	)
+	require.NoError(t, err)
+
+	WithLoader[int, int, int](l, 3)(p)
+	WithOnFinished(func(ctx context.Context, p IPipeline[int, int], originalTask task.Task[int, int], tasksOut []task.Task[int, int]) {
+		originalData = originalTask.ProcessingData
+	})(p)
+
+	out, err := p.Run(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+
+	assert.Equal(t, 3, loaded.Load())
+	assert.Equal(t, []int{2, 4, 6}, out[0].ConvertedData)
+	assert.Equal(t, []int{1, 2, 3}, originalData)
+
+	// Extract + 1 stage.
+	assert.Equal(t, int64(2), p.GetProgress().Value())
+	assert.Equal(t, "100%", p.GetProgressPercent().Value())
+
+	assert.Equal(t, int64(1), l.GetCounterDone().Value())
+	assert.Equal(t, status.Done.String(), p.GetStatus().Value())
+
+	// Data passed to `Run` comes first.
+	out, err = p.Run(ctx, []int{10})
+	require.NoError(t, err)
+	assert.Equal(t, []int{20, 2, 4, 6}, out[0].ConvertedData)
+}
+
+// closingSource is a source, which records whether it was closed.
+type closingSource struct {
+	n      int
+	closed *atomic.Int32
+}
+
+// Close records the source was closed.
+func (c closingSource) Close() error {
+	c.closed.Add(1)
+
+	return nil
+}
+
+// Happy path: every run opens, loads, and closes its own source.
+func TestPipeline_extract_source(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var opened, closed atomic.Int32
+
+	l, err := loader.New(
+		"extract-source-loader",
+		"produces items",
+		func(ctx context.Context, source closingSource) ([]int, error) {
+			return []int{source.n}, nil
+		},
+	)
+	require.NoError(t, err)
+
+	p, err := New("extract-source", "pipeline with a loader source", false,
+		newMapStage(t, "extract-source-double", func(v int) int { return v * 2 }),
+	)
+	require.NoError(t, err)
+
+	WithLoaderSource[closingSource, int, int](l, func(ctx context.Context) (closingSource, error) {
+		return closingSource{n: int(opened.Add(1)), closed: &closed}, nil
+	})(p)
+
+	for run := 1; run <= 2; run++ {
+		out, err := p.Run(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, out, 1)
+
+		assert.Equal(t, []int{run * 2}, out[0].ConvertedData)
+		assert.Equal(t, int32(run), closed.Load())
+	}
+
+	// Bad path: failing to open the source fails the run.
+	WithLoaderSource[closingSource, int, int](l, func(ctx context.Context) (closingSource, error) {
+		return closingSource{}, errors.New("boom-open")
+	})(p)
+
+	_, err = p.Run(ctx, nil)
+	assert.ErrorContains(t, err, "boom-open")
+}
+
+// The extract step waits while the pipeline is paused.
+func TestPipeline_extract_paused(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var loaded atomic.Value
+
+	p, err := New("extract-paused", "paused pipeline with a loader", false,
+		newMapStage(t, "extract-paused-identity", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	WithLoader[int, int, int](newCountingLoader(t, "extract-paused-loader", &loaded), 1)(p)
+
+	p.SetPause(true)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := p.Run(ctx, nil)
+
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Nil(t, loaded.Load(), "a paused pipeline must not extract")
+
+	p.SetPause(false)
+
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, loaded.Load())
+}
+
+// Bad path: a failing loader fails the pipeline before any stage runs.
+func TestPipeline_extract_failingLoader(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	l, err := loader.New(
+		"extract-failing-loader",
+		"fails",
+		func(ctx context.Context, source string) ([]int, error) {
+			return nil, errors.New("boom-loader")
+		},
+	)
+	require.NoError(t, err)
+
+	var ran atomic.Bool
+
+	p, err := New("extract-failing", "pipeline with a failing loader", false,
+		newMapStage(t, "extract-failing-stage", func(v int) int {
+			ran.Store(true)
+
+			return v
+		}),
+	)
+	require.NoError(t, err)
+
+	WithLoader[string, int, int](l, "source")(p)
+
+	_, err = p.Run(ctx, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom-loader")
+
+	assert.False(t, ran.Load())
+	assert.Equal(t, status.Failed.String(), p.GetStatus().Value())
+	assert.Equal(t, int64(1), l.GetCounterFailed().Value())
+}
@@ -0,0 +1,177 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/stage"
+	"github.com/thalesfsp/status"
+)
+
+// Happy path: the report holds the run's status, task, stages, and
+// processors, and the run ID is carried by the processors' context.
+func TestPipeline_RunWithReport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var seen sync.Map
+
+	spy, err := processor.New(
+		"report-spy",
+		"records the run ID",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			seen.Store(RunIDFromCtx(ctx), true)
+
+			return processingData[:1], nil
+		},
+	)
+	require.NoError(t, err)
+
+	spyStage, err := stage.New("report-spy-stage", "spy stage", converter.MustDefault(
+		func(ctx context.Context, in int) (int, error) { return in, nil },
+	), spy)
+	require.NoError(t, err)
+
+	p, err := New("report", "reported pipeline", false,
+		newMapStage(t, "report-first", func(v int) int { return v + 1 }),
+		spyStage,
+	)
+	require.NoError(t, err)
+
+	tasks, r, err := p.RunWithReport(ctx, []int{1, 2})
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	require.Len(t, tasks, 2)
+
+	assert.NotEmpty(t, r.RunID)
+	assert.Equal(t, "report", r.Pipeline)
+	assert.Equal(t, status.Done.String(), r.Status)
+	assert.NotEmpty(t, r.TaskID)
+	assert.Equal(t, 2, r.ItemsIn)
+	assert.Positive(t, r.Duration)
+
+	_, ok := seen.Load(r.RunID)
+	assert.True(t, ok, "the run ID must be carried by the context")
+
+	require.Len(t, r.Stages, 2)
+
+	first, second := r.Stages[0], r.Stages[1]
+
+	assert.Equal(t, "report-first", first.Name)
+	assert.Equal(t, r.TaskID, first.TaskID)
+	assert.Equal(t, status.Done.String(), first.Status)
+	assert.Equal(t, 2, first.ItemsIn)
+	assert.Equal(t, 2, first.ItemsOut)
+
+	require.Len(t, first.Processors, 1)
+	assert.Equal(t, "report-first-map", first.Processors[0].Name)
+	assert.Equal(t, 1, first.Processors[0].Attempts)
+	assert.Equal(t, 2, first.Processors[0].ItemsOut)
+
+	assert.Equal(t, "report-spy-stage", second.Name)
+	assert.Equal(t, 1, second.ItemsOut)
+	assert.Equal(t, 1, second.Processors[0].ItemsOut)
+
+	// Happy path: it serializes.
+	b, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"runID":"`+r.RunID+`"`)
+
+	// Happy path: the caller's run ID is used.
+	_, r, err = p.RunWithReport(ContextWithRunID(ctx, "nightly-1"), []int{1})
+	require.NoError(t, err)
+	assert.Equal(t, "nightly-1", r.RunID)
+}
+
+// Bad path: a failed run is reported, up to the failing processor.
+func TestPipeline_RunWithReport_failed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var fail atomic.Bool
+
+	fail.Store(true)
+
+	p, err := New("report-failed", "failing pipeline", false,
+		newMapStage(t, "report-failed-first", func(v int) int { return v }),
+		newFlakyStage(t, "report-failed-last", &fail, new(atomic.Int32)),
+	)
+	require.NoError(t, err)
+
+	tasks, r, err := p.RunWithReport(ctx, []int{1})
+	require.Error(t, err)
+	assert.Nil(t, tasks)
+
+	require.NotNil(t, r)
+	assert.Equal(t, status.Failed.String(), r.Status)
+	assert.Equal(t, err, r.Err)
+	assert.NotEmpty(t, r.Error)
+
+	require.Len(t, r.Stages, 2)
+	assert.Equal(t, status.Done.String(), r.Stages[0].Status)
+	assert.Equal(t, status.Failed.String(), r.Stages[1].Status)
+	assert.Contains(t, r.Stages[1].Processors[0].Error, "boom-flaky")
+	assert.Equal(t, status.Failed.String(), r.Stages[1].Processors[0].Status)
+}
+
+// Edge case: concurrent runs of the same pipeline are reported apart.
+func TestPipeline_RunWithReport_concurrentRuns(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := New("report-concurrent", "shared pipeline", true,
+		newMapStage(t, "report-concurrent-a", func(v int) int { return v }),
+		newMapStage(t, "report-concurrent-b", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	const runs = 8
+
+	reports := make([]*RunReport, runs)
+
+	var wg sync.WaitGroup
+
+	for i := range runs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			items := make([]int, i+1)
+
+			_, r, err := p.RunWithReport(ctx, items)
+			assert.NoError(t, err)
+
+			reports[i] = r
+		}()
+	}
+
+	wg.Wait()
+
+	ids := map[string]bool{}
+
+	for i, r := range reports {
+		require.NotNil(t, r)
+
+		ids[r.RunID] = true
+
+		assert.Equal(t, i+1, r.ItemsIn)
+		require.Len(t, r.Stages, 2)
+
+		for _, s := range r.Stages {
+			assert.Equal(t, i+1, s.ItemsIn)
+			assert.Equal(t, i+1, s.ItemsOut)
+			assert.Len(t, s.Processors, 1)
+		}
+	}
+
+	assert.Len(t, ids, runs)
+}
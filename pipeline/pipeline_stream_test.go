@@ -0,0 +1,239 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/status"
+)
+
+// feed returns a closed channel holding `items`.
+func feed(items ...int) <-chan int {
+	in := make(chan int, len(items))
+
+	for _, i := range items {
+		in <- i
+	}
+
+	close(in)
+
+	return in
+}
+
+// drain reads every task and the error of a stream.
+func drain(out <-chan task.Task[int, int], errCh <-chan error) ([]task.Task[int, int], error) {
+	var tasks []task.Task[int, int]
+
+	for tsk := range out {
+		tasks = append(tasks, tsk)
+	}
+
+	return tasks, <-errCh
+}
+
+// Happy path: sequential stages over micro-batches, emitted in input order.
+func TestPipeline_RunStream_sequential(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := New("stream-sequential", "streams through a chain", false,
+		newMapStage(t, "stream-seq-inc", func(v int) int { return v + 1 }),
+		newMapStage(t, "stream-seq-double", func(v int) int { return v * 2 }),
+	)
+	require.NoError(t, err)
+
+	finished := 0
+
+	WithOnFinished[int, int](func(ctx context.Context, pl IPipeline[int, int], original task.Task[int, int], tasksOut []task.Task[int, int]) {
+		finished++
+
+		assert.Len(t, tasksOut, 2)
+	})(p)
+
+	tasks, err := drain(p.RunStream(ctx, feed(1, 2, 3, 4, 5, 6, 7), WithStreamBatchSize(3)))
+	require.NoError(t, err)
+
+	// Only the last stage is terminal: one task per micro-batch.
+	require.Len(t, tasks, 3)
+
+	var got []int
+
+	for _, tsk := range tasks {
+		got = append(got, tsk.ConvertedData...)
+	}
+
+	assert.Equal(t, []int{4, 6, 8, 10, 12, 14, 16}, got)
+	assert.Len(t, tasks[2].ConvertedData, 1)
+
+	assert.Equal(t, 3, finished, "OnFinished must be called once per micro-batch")
+	assert.Equal(t, status.Done.String(), p.GetStatus().Value())
+	assert.Equal(t, int64(1), p.GetCounterDone().Value())
+}
+
+// Concurrent mode: every stage is terminal, so each micro-batch yields one
+// task per stage.
+func TestPipeline_RunStream_concurrent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := New("stream-concurrent", "streams to every stage", true,
+		newMapStage(t, "stream-conc-inc", func(v int) int { return v + 1 }),
+		newMapStage(t, "stream-conc-neg", func(v int) int { return -v }),
+	)
+	require.NoError(t, err)
+
+	tasks, err := drain(p.RunStream(ctx, feed(1, 2, 3), WithStreamBatchSize(2)))
+	require.NoError(t, err)
+	require.Len(t, tasks, 4)
+
+	assert.Equal(t, []int{2, 3}, tasks[0].ConvertedData)
+	assert.Equal(t, []int{-1, -2}, tasks[1].ConvertedData)
+	assert.Equal(t, []int{4}, tasks[2].ConvertedData)
+	assert.Equal(t, []int{-3}, tasks[3].ConvertedData)
+}
+
+// DAG mode: the join is the only terminal stage.
+func TestPipeline_RunStream_dag(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := New("stream-dag", "streams through a diamond", false,
+		newMapStage(t, "stream-dag-a", func(v int) int { return v }),
+		newMapStage(t, "stream-dag-b", func(v int) int { return v * 2 }),
+		newMapStage(t, "stream-dag-c", func(v int) int { return v * 3 }),
+		newMapStage(t, "stream-dag-d", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	p.SetDependencies("stream-dag-b", "stream-dag-a")
+	p.SetDependencies("stream-dag-c", "stream-dag-a")
+	p.SetDependencies("stream-dag-d", "stream-dag-b", "stream-dag-c")
+
+	tasks, err := drain(p.RunStream(ctx, feed(1, 2, 3), WithStreamBatchSize(2), WithStreamBufferSize(0)))
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	assert.Equal(t, []int{2, 4, 3, 6}, tasks[0].ConvertedData)
+	assert.Equal(t, []int{6, 9}, tasks[1].ConvertedData)
+}
+
+// Edge case: an incomplete micro-batch is flushed when the input goes idle,
+// before the input is closed.
+func TestPipeline_RunStream_flushInterval(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := New("stream-flush", "flushes idle batches", false,
+		newMapStage(t, "stream-flush-id", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	in := make(chan int)
+
+	out, errCh := p.RunStream(ctx, in,
+		WithStreamBatchSize(100),
+		WithStreamFlushInterval(50*time.Millisecond),
+	)
+
+	in <- 1
+	in <- 2
+
+	select {
+	case tsk := <-out:
+		assert.Equal(t, []int{1, 2}, tsk.ConvertedData)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the idle micro-batch was not flushed")
+	}
+
+	close(in)
+
+	tasks, err := drain(out, errCh)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+// Bad path: a failing stage stops the stream and surfaces its cause.
+func TestPipeline_RunStream_stageError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	boom := errors.New("boom-stream")
+
+	p, err := New("stream-failing", "fails mid-stream", false,
+		newMapStage(t, "stream-fail-ok", func(v int) int { return v }),
+		newFailingStage(t, "stream-fail-bad", boom),
+	)
+	require.NoError(t, err)
+
+	// An input that's never closed: the failure alone must end the stream.
+	in := make(chan int, 1)
+	in <- 1
+
+	tasks, err := drain(p.RunStream(ctx, in, WithStreamBatchSize(1)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom-stream")
+	assert.Empty(t, tasks)
+	assert.Equal(t, status.Failed.String(), p.GetStatus().Value())
+}
+
+// Bad path: cancelling the caller's context ends the stream with its error.
+func TestPipeline_RunStream_contextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p, err := New("stream-canceled", "canceled stream", false,
+		newMapStage(t, "stream-cancel-id", func(v int) int { return v }),
+	)
+	require.NoError(t, err)
+
+	out, errCh := p.RunStream(ctx, make(chan int))
+
+	cancel()
+
+	_, err = drain(out, errCh)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// Happy path: a drained stream stops reading its input, and is interrupted,
+// not failed.
+func TestPipeline_RunStream_drain(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := New("stream-drain", "drained stream", false,
+		newMapStage(t, "stream-drain-inc", func(v int) int { return v + 1 }),
+	)
+	require.NoError(t, err)
+
+	failedBefore := p.GetCounterFailed().Value()
+	interruptedBefore := p.GetCounterInterrupted().Value()
+
+	// Never closed: only the drain stops the stream.
+	in := make(chan int)
+
+	out, errCh := p.RunStream(ctx, in, WithStreamBatchSize(1))
+
+	in <- 1
+
+	select {
+	case tsk := <-out:
+		assert.Equal(t, []int{2}, tsk.ConvertedData)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream didn't emit")
+	}
+
+	p.Drain()
+
+	tasks, err := drain(out, errCh)
+	require.ErrorIs(t, err, ErrInterrupted)
+	assert.Empty(t, tasks)
+
+	assert.Equal(t, status.Interrupted.String(), p.GetStatus().Value())
+	assert.Equal(t, interruptedBefore+1, p.GetCounterInterrupted().Value())
+	assert.Equal(t, failedBefore, p.GetCounterFailed().Value())
+}
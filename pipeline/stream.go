@@ -0,0 +1,614 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/etler/v3/event"
+	"github.com/thalesfsp/etler/v3/internal/customapm"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/report"
+	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/etler/v3/tracing"
+	"github.com/thalesfsp/status"
+	"github.com/thalesfsp/sypl/v2"
+	"github.com/thalesfsp/sypl/v2/level"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+const (
+	// DefaultStreamBatchSize is the default number of items per micro-batch.
+	DefaultStreamBatchSize = 100
+
+	// DefaultStreamBufferSize is the default number of micro-batches buffered
+	// between two stages.
+	DefaultStreamBufferSize = 1
+)
+
+// StreamOption configures `RunStream`.
+type StreamOption struct {
+	// BatchSize is the maximum number of items per micro-batch.
+	BatchSize int
+
+	// BufferSize is the number of micro-batches buffered between two stages,
+	// and in the output channel. Once full, the upstream blocks — that's the
+	// backpressure that bounds memory.
+	BufferSize int
+
+	// FlushInterval, if set, flushes an incomplete micro-batch when no new
+	// item arrived for that long. Otherwise, an incomplete micro-batch waits
+	// until it's full, or the input channel is closed.
+	FlushInterval time.Duration
+}
+
+// StreamFunc allows to specify stream's options.
+type StreamFunc func(o StreamOption) StreamOption
+
+// stageResult is a stage's output for one micro-batch.
+type stageResult[ProcessedData, ConvertedOut any] struct {
+	stage int
+	tsk   task.Task[ProcessedData, ConvertedOut]
+}
+
+//////
+// Built-in stream options.
+//////
+
+// WithStreamBatchSize sets the maximum number of items per micro-batch.
+func WithStreamBatchSize(size int) StreamFunc {
+	return func(o StreamOption) StreamOption {
+		o.BatchSize = size
+
+		return o
+	}
+}
+
+// WithStreamBufferSize sets the number of micro-batches buffered between two
+// stages.
+func WithStreamBufferSize(size int) StreamFunc {
+	return func(o StreamOption) StreamOption {
+		o.BufferSize = size
+
+		return o
+	}
+}
+
+// WithStreamFlushInterval flushes an incomplete micro-batch when no new item
+// arrived for `d`.
+func WithStreamFlushInterval(d time.Duration) StreamFunc {
+	return func(o StreamOption) StreamOption {
+		o.FlushInterval = d
+
+		return o
+	}
+}
+
+//////
+// Helpers.
+//////
+
+// graph returns, for each stage (by index), the indexes of its upstream
+// stages, according to the pipeline's mode.
+func (p *Pipeline[ProcessedData, ConvertedOut]) graph() ([][]int, error) {
+	if len(p.GetDependencies()) > 0 {
+		return p.buildDAG()
+	}
+
+	upstreams := make([][]int, len(p.Stages))
+
+	// Concurrent: every stage is a root. Sequential: a chain.
+	if !p.ConcurrentStage {
+		for i := 1; i < len(p.Stages); i++ {
+			upstreams[i] = []int{i - 1}
+		}
+	}
+
+	return upstreams, nil
+}
+
+// batch reads `in` into micro-batches, sending each one to `batches`, and
+// to every root stage. It closes them all when `in` is exhausted, or the
+// context is done — the latter isn't an error of its own — or the run
+// drains, returning `ErrInterrupted`. Items of a micro-batch not filled yet
+// when the run drains are dropped.
+func batch[ProcessedData, ConvertedOut any](
+	ctx context.Context,
+	in <-chan ProcessedData,
+	o StreamOption,
+	batches chan<- task.Task[ProcessedData, ConvertedOut],
+	roots []chan task.Task[ProcessedData, ConvertedOut],
+) error {
+	defer func() {
+		close(batches)
+
+		for _, r := range roots {
+			close(r)
+		}
+	}()
+
+	var (
+		flush   <-chan time.Time
+		drained <-chan struct{}
+	)
+
+	if d := shared.DrainerFromCtx(ctx); d != nil {
+		drained = d.Done()
+	}
+
+	for {
+		items := make([]ProcessedData, 0, o.BatchSize)
+
+		closed := false
+
+	fill:
+		for len(items) < o.BatchSize {
+			if o.FlushInterval > 0 && len(items) > 0 {
+				flush = time.After(o.FlushInterval)
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-drained:
+				return shared.ErrInterrupted
+			case item, ok := <-in:
+				if !ok {
+					closed = true
+
+					break fill
+				}
+
+				items = append(items, item)
+			case <-flush:
+				break fill
+			}
+		}
+
+		flush = nil
+
+		if len(items) > 0 {
+			tsk, err := task.New[ProcessedData, ConvertedOut](items)
+			if err != nil {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case batches <- tsk:
+			}
+
+			for _, r := range roots {
+				select {
+				case <-ctx.Done():
+					return nil
+				case r <- tsk:
+				}
+			}
+		}
+
+		if closed {
+			return nil
+		}
+	}
+}
+
+//////
+// Methods.
+//////
+
+// RunStream runs the pipeline over a stream: items read from `in` are
+// grouped into micro-batches, each one becoming a task that flows through
+// the stages. Every stage works on its own micro-batch concurrently with
+// the others, and bounded buffers between stages apply backpressure up to
+// `in`. Stages are wired according to the pipeline's mode: a chain
+// (sequential), all fed from the input (concurrent), or the declared
+// dependencies (DAG).
+//
+// For each micro-batch, in input order, the tasks of the terminal stages
+// (stages no other stage consumes) are sent to the returned task channel,
+// and `OnFinished` is called with all stages' tasks. Both returned channels
+// are closed once the stream is over. The error channel receives at most one
+// error: the first failure stops the whole stream. A drained stream stops
+// reading `in`, and receives `ErrInterrupted`, as a drained run returns it.
+func (p *Pipeline[ProcessedData, ConvertedOut]) RunStream(
+	ctx context.Context,
+	in <-chan ProcessedData,
+	opts ...StreamFunc,
+) (<-chan task.Task[ProcessedData, ConvertedOut], <-chan error) {
+	o := StreamOption{
+		BatchSize:  DefaultStreamBatchSize,
+		BufferSize: DefaultStreamBufferSize,
+	}
+
+	for _, opt := range opts {
+		o = opt(o)
+	}
+
+	if o.BatchSize < 1 {
+		o.BatchSize = 1
+	}
+
+	if o.BufferSize < 0 {
+		o.BufferSize = 0
+	}
+
+	out := make(chan task.Task[ProcessedData, ConvertedOut], o.BufferSize)
+	errCh := make(chan error, 1)
+
+	// Streams carry a run ID too, though they aren't reported.
+	runID := RunIDFromCtx(ctx)
+	if runID == "" {
+		runID = shared.GenerateUUID()
+	}
+
+	ctx = event.ContextWithBus(ContextWithRunID(ctx, runID), p.GetEventBus(), runID, p.GetName())
+
+	go func() {
+		defer close(errCh)
+		defer close(out)
+
+		// Streams aren't reported, the recorder only keeps their status.
+		ctx, untrack, err := p.track(ctx, runID, report.New(runID, p.GetName()))
+		if err != nil {
+			errCh <- err
+
+			return
+		}
+
+		defer untrack()
+
+		event.Publish(ctx, event.Event{Type: event.RunStarted, Component: Type, Name: p.GetName()})
+
+		now := time.Now()
+
+		err = p.stream(ctx, in, o, out)
+
+		event.Publish(ctx, event.Event{
+			Type:      event.Outcome(err, event.RunFinished, event.RunFailed, event.RunInterrupted),
+			Component: Type,
+			Name:      p.GetName(),
+			Duration:  time.Since(now),
+			Err:       err,
+		})
+
+		if err != nil {
+			errCh <- err
+		}
+	}()
+
+	return out, errCh
+}
+
+// stream is the body of `RunStream`.
+//
+//nolint:gocognit
+func (p *Pipeline[ProcessedData, ConvertedOut]) stream(
+	ctx context.Context,
+	in <-chan ProcessedData,
+	o StreamOption,
+	out chan<- task.Task[ProcessedData, ConvertedOut],
+) error {
+	//////
+	// Observability: tracing, metrics, status, logging, etc.
+	//////
+
+	tracedContext, span := customapm.Trace(
+		ctx,
+		Type,
+		p.GetName(),
+		status.Runnning,
+		p.GetLogger(),
+		p.CounterRunning,
+	)
+	defer span.End()
+
+	tracedContext = customapm.Annotate(tracedContext, span, tracing.String(tracing.KeyRunID, RunIDFromCtx(ctx)))
+
+	// Make this pipeline's pause controller visible to its processors.
+	tracedContext = shared.ContextWithPause(tracedContext, p.pause)
+
+	// A paused pipeline keeps reporting paused — its processors are about to
+	// block on the pause controller.
+	if !p.pause.Paused() {
+		p.GetStatus().Set(status.Runnning.String())
+	}
+
+	p.GetLogger().PrintlnWithOptions(level.Trace, status.Runnning.String())
+
+	now := time.Now()
+
+	upstreams, err := p.graph()
+	if err != nil {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return shared.OnErrorHandler(
+			tracedContext,
+			p,
+			p.GetLogger(),
+			err,
+			"build DAG",
+			Type,
+			p.GetName(),
+		)
+	}
+
+	//////
+	// Wire the stages.
+	//////
+
+	streamContext, cancel := context.WithCancel(tracedContext)
+	defer cancel()
+
+	// inbound[i][k] carries the output of stage i's k-th upstream stage — or,
+	// for root stages, the micro-batches.
+	inbound := make([][]chan task.Task[ProcessedData, ConvertedOut], len(p.Stages))
+	outbound := make([][]chan task.Task[ProcessedData, ConvertedOut], len(p.Stages))
+	roots := make([]chan task.Task[ProcessedData, ConvertedOut], 0, len(p.Stages))
+
+	for i, ups := range upstreams {
+		if len(ups) == 0 {
+			ch := make(chan task.Task[ProcessedData, ConvertedOut], o.BufferSize)
+
+			inbound[i] = append(inbound[i], ch)
+			roots = append(roots, ch)
+
+			continue
+		}
+
+		for _, u := range ups {
+			ch := make(chan task.Task[ProcessedData, ConvertedOut], o.BufferSize)
+
+			inbound[i] = append(inbound[i], ch)
+			outbound[u] = append(outbound[u], ch)
+		}
+	}
+
+	batches := make(chan task.Task[ProcessedData, ConvertedOut], o.BufferSize+1)
+	results := make(chan stageResult[ProcessedData, ConvertedOut], len(p.Stages)*(o.BufferSize+1))
+
+	var (
+		batcherWG sync.WaitGroup
+		stagesWG  sync.WaitGroup
+		errsMu    sync.Mutex
+		errs      []error
+	)
+
+	fail := func(err error) {
+		errsMu.Lock()
+		errs = append(errs, err)
+		errsMu.Unlock()
+
+		cancel()
+	}
+
+	batcherWG.Add(1)
+
+	go func() {
+		defer batcherWG.Done()
+
+		err := batch(streamContext, in, o, batches, roots)
+
+		// Micro-batches in flight finish, or are interrupted by their
+		// stages, so the stream isn't cancelled.
+		if errors.Is(err, shared.ErrInterrupted) {
+			errsMu.Lock()
+			errs = append(errs, err)
+			errsMu.Unlock()
+
+			return
+		}
+
+		if err != nil {
+			fail(err)
+		}
+	}()
+
+	for i, s := range p.Stages {
+		stagesWG.Add(1)
+
+		go func() {
+			defer stagesWG.Done()
+
+			// Closing its outbound channels lets downstream stages finish.
+			defer func() {
+				for _, ch := range outbound[i] {
+					close(ch)
+				}
+			}()
+
+			for {
+				upstreamOut := make([]task.Task[ProcessedData, ConvertedOut], 0, len(inbound[i]))
+
+				// Every upstream produces exactly one task per micro-batch,
+				// in order, so reading one from each joins the same batch.
+				for _, ch := range inbound[i] {
+					select {
+					case <-streamContext.Done():
+						return
+					case t, ok := <-ch:
+						if !ok {
+							return
+						}
+
+						upstreamOut = append(upstreamOut, t)
+					}
+				}
+
+				var stageIn task.Task[ProcessedData, ConvertedOut]
+
+				if len(upstreams[i]) == 0 {
+					stageIn = joinInput(upstreamOut[0], nil)
+				} else {
+					stageIn = joinInput(upstreamOut[0], upstreamOut)
+				}
+
+				stageOut, err := s.Run(streamContext, stageIn)
+				if err != nil {
+					// Already traced, logged, and counted by the stage.
+					fail(err)
+
+					return
+				}
+
+				select {
+				case <-streamContext.Done():
+					return
+				case results <- stageResult[ProcessedData, ConvertedOut]{stage: i, tsk: stageOut}:
+				}
+
+				for _, ch := range outbound[i] {
+					select {
+					case <-streamContext.Done():
+						return
+					case ch <- stageOut:
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		stagesWG.Wait()
+
+		close(results)
+	}()
+
+	//////
+	// Collect: emit each micro-batch, in input order, once every stage is
+	// done with it.
+	//////
+
+	var (
+		order   []task.Task[ProcessedData, ConvertedOut]
+		pending = make(map[string][]task.Task[ProcessedData, ConvertedOut])
+		counts  = make(map[string]int)
+	)
+
+	batchesIn := (<-chan task.Task[ProcessedData, ConvertedOut])(batches)
+	resultsIn := (<-chan stageResult[ProcessedData, ConvertedOut])(results)
+
+collect:
+	for batchesIn != nil || resultsIn != nil {
+		select {
+		case b, ok := <-batchesIn:
+			if !ok {
+				batchesIn = nil
+
+				continue
+			}
+
+			order = append(order, b)
+		case r, ok := <-resultsIn:
+			if !ok {
+				resultsIn = nil
+
+				continue
+			}
+
+			if pending[r.tsk.ID] == nil {
+				pending[r.tsk.ID] = make([]task.Task[ProcessedData, ConvertedOut], len(p.Stages))
+			}
+
+			pending[r.tsk.ID][r.stage] = r.tsk
+
+			counts[r.tsk.ID]++
+		}
+
+		for len(order) > 0 && counts[order[0].ID] == len(p.Stages) {
+			originalTask := order[0]
+			tasksOut := pending[originalTask.ID]
+
+			order = order[1:]
+
+			delete(pending, originalTask.ID)
+			delete(counts, originalTask.ID)
+
+			if p.GetOnFinished() != nil {
+				p.GetOnFinished()(ctx, p, originalTask, tasksOut)
+			}
+
+			for i := range p.Stages {
+				if len(outbound[i]) > 0 {
+					continue
+				}
+
+				select {
+				case <-streamContext.Done():
+					break collect
+				case out <- tasksOut[i]:
+				}
+			}
+		}
+	}
+
+	// Unblock, and wait for, every goroutine before reporting.
+	cancel()
+
+	batcherWG.Wait()
+
+	stagesWG.Wait()
+
+	// Stopped early only because the caller's context is done.
+	if len(errs) == 0 && tracedContext.Err() != nil {
+		errs = append(errs, tracedContext.Err())
+	}
+
+	// Stopped only because the run drains.
+	if interrupted(errs...) {
+		_, err := p.interrupt(nil)
+
+		return err
+	}
+
+	if len(errs) > 0 {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return shared.OnErrorHandler(
+			tracedContext,
+			p,
+			p.GetLogger(),
+			errors.Join(errs...),
+			"run stage",
+			Type,
+			p.GetName(),
+		)
+	}
+
+	//////
+	// Observability: tracing, metrics, status, logging, etc.
+	//////
+
+	p.GetStatus().Set(status.Done.String())
+
+	p.GetCounterDone().Add(1)
+
+	elapsed := time.Since(now)
+
+	p.GetDuration().Set(elapsed.Milliseconds())
+
+	p.GetDurationHistogram().Observe(float64(elapsed.Microseconds()) / 1000)
+
+	p.GetLogger().PrintWithOptions(
+		level.Debug,
+		status.Done.String(),
+		sypl.WithField("createdAt", p.GetCreatedAt().String()),
+		sypl.WithField("counterCreated", p.GetCounterCreated().String()),
+		sypl.WithField("counterDone", p.GetCounterDone().String()),
+		sypl.WithField("counterFailed", p.GetCounterFailed().String()),
+		sypl.WithField("counterRunning", p.GetCounterRunning().String()),
+		sypl.WithField("duration", p.GetDuration().String()),
+		sypl.WithField("status", p.GetStatus().String()),
+	)
+
+	return nil
+}
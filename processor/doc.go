@@ -20,7 +20,7 @@
 //
 // When the `Run` method is called on a processor, it executes the following steps:
 //
-// 1. It checks if the pipeline is paused. If paused, the processor waits until it is resumed or the context is done. This allows for graceful handling of pipeline pauses during processor execution.
+// 1. It checks if the processor, or its pipeline, run, or stage, is paused. If paused, the processor waits until it is resumed or the context is done. This allows for graceful handling of pipeline pauses during processor execution.
 //
 // 2. Once the pipeline is resumed or if it was not paused, the processor invokes the transform function, passing the input data and the context. The transform function performs the necessary data transformations and returns the processed data.
 //
@@ -40,11 +40,11 @@
 //
 // 2. **Flexibility**: Processors can encapsulate any data transformation logic, from simple arithmetic operations to complex business rules and data enrichment.
 //
-// 3. **Pause and Resume**: Processors support pipeline pausing and resuming. If the pipeline is paused during a processor's execution, the processor gracefully waits until it is resumed or the context is done, ensuring proper handling of pauses.
+// 3. **Pause and Resume**: Processors support pipeline pausing and resuming. If the pipeline is paused during a processor's execution, the processor gracefully waits until it is resumed or the context is done, ensuring proper handling of pauses. A processor, or a stage, can also be paused on its own with `SetPause`, e.g., to hold the processor calling a rate-limited API while the others keep working. A transform function looping over many items can pause between them with `pause.Checkpoint`.
 //
 // 4. **Observability**: The processor package provides comprehensive observability features, including metrics, logging, and tracing, to monitor and debug the processor's execution.
 //
-// 5. **Metrics**: Processor metrics are tracked with `expvar` variables (registered globally on `/debug/vars` only when `ETLER_METRICS_PUBLISH=true`; unpublished per-instance variables otherwise), allowing for easy integration with monitoring systems. Metrics include counters for created, running, failed, done, and interrupted processors, as well as duration.
+// 5. **Metrics**: Processor metrics are tracked with `expvar` variables (registered globally on `/debug/vars` only when `ETLER_METRICS_PUBLISH=true`; unpublished per-instance variables otherwise), allowing for easy integration with monitoring systems. Metrics include counters for created, running, failed, done, and interrupted processors, items in and out, and items per second, as well as duration, and a duration histogram, with percentiles.
 //
 // 6. **Logging**: The package utilizes the `sypl` library for structured logging, providing rich context and consistent log levels throughout the codebase. Log messages include relevant information such as processor status, counters, and duration.
 //
@@ -3,8 +3,12 @@ package processor
 import (
 	"context"
 	"expvar"
+	"time"
 
+	"github.com/thalesfsp/etler/v3/event"
 	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/retry"
+	"github.com/thalesfsp/status"
 )
 
 //////
@@ -23,6 +27,22 @@ type IProcessor[ProcessingData any] interface {
 	// SetOnFinished sets the `OnFinished` function.
 	SetOnFinished(onFinished OnFinished[ProcessingData])
 
+	// GetTimeout returns the `Timeout` of a run.
+	GetTimeout() time.Duration
+
+	// SetTimeout sets the `Timeout` of a run.
+	SetTimeout(timeout time.Duration)
+
+	// GetRetry returns the `Retry` policy.
+	GetRetry() *retry.Policy
+
+	// SetRetry sets the `Retry` policy.
+	SetRetry(policy *retry.Policy)
+
+	// SetHistogram sets the buckets, and percentiles, of the duration
+	// histogram, replacing it.
+	SetHistogram(buckets, percentiles []float64) error
+
 	// GetCounterProcessed returns the `CounterProcessed` variable.
 	GetCounterInterrupted() *expvar.Int
 
@@ -34,6 +54,18 @@ type IProcessor[ProcessingData any] interface {
 	// GetAsync returns if the processor is running in a go routine.
 	GetAsync() bool
 
+	// GetPaused returns the Paused status of the processor.
+	GetPaused() status.Status
+
+	// SetPause the processor only.
+	SetPause(state bool)
+
+	// GetEventBus returns the bus the processor publishes its pause events to.
+	GetEventBus() *event.Bus
+
+	// SetEventBus sets the bus the processor publishes its pause events to.
+	SetEventBus(bus *event.Bus, pipeline string)
+
 	// Run the transform function.
 	Run(ctx context.Context, processingData []ProcessingData) (processedOut []ProcessingData, err error)
 }
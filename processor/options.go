@@ -2,6 +2,9 @@ package processor
 
 import (
 	"context"
+	"time"
+
+	"github.com/thalesfsp/etler/v3/retry"
 )
 
 //////
@@ -30,6 +33,17 @@ func WithAsync[T any](async bool) Func[T] {
 	}
 }
 
+// WithHistogram sets the buckets, in milliseconds, and the percentiles of
+// the duration histogram. Invalid ones fail the factory.
+func WithHistogram[T any](buckets, percentiles []float64) Func[T] {
+	return func(p IProcessor[T]) IProcessor[T] {
+		// NOTE: The factory reports errors, once options are applied.
+		_ = p.SetHistogram(buckets, percentiles)
+
+		return p
+	}
+}
+
 // WithOnFinished sets the OnFinished function.
 func WithOnFinished[T any](onFinished OnFinished[T]) Func[T] {
 	return func(p IProcessor[T]) IProcessor[T] {
@@ -38,3 +52,22 @@ func WithOnFinished[T any](onFinished OnFinished[T]) Func[T] {
 		return p
 	}
 }
+
+// WithRetry sets the retry policy, see the `retry` package.
+func WithRetry[T any](policy *retry.Policy) Func[T] {
+	return func(p IProcessor[T]) IProcessor[T] {
+		p.SetRetry(policy)
+
+		return p
+	}
+}
+
+// WithTimeout sets the timeout of a run. Runs taking longer fail with
+// `ErrTimeout`, and count as interrupted.
+func WithTimeout[T any](timeout time.Duration) Func[T] {
+	return func(p IProcessor[T]) IProcessor[T] {
+		p.SetTimeout(timeout)
+
+		return p
+	}
+}
@@ -2,13 +2,21 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"expvar"
+	"fmt"
+	"maps"
+	"strconv"
 	"time"
 
+	"github.com/thalesfsp/etler/v3/event"
 	"github.com/thalesfsp/etler/v3/internal/customapm"
 	"github.com/thalesfsp/etler/v3/internal/logging"
 	"github.com/thalesfsp/etler/v3/internal/metrics"
 	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/report"
+	"github.com/thalesfsp/etler/v3/retry"
+	"github.com/thalesfsp/etler/v3/tracing"
 	"github.com/thalesfsp/status"
 	"github.com/thalesfsp/sypl/v2"
 	"github.com/thalesfsp/sypl/v2/level"
@@ -22,6 +30,14 @@ import (
 // Type of the entity.
 const Type = "processor"
 
+// ErrTimeout is the cause of runs failing because they exceeded their
+// `Timeout`.
+var ErrTimeout = shared.ErrTimeout
+
+// ErrInterrupted is the cause of runs stopped, before processing, because
+// their pipeline run drains.
+var ErrInterrupted = shared.ErrInterrupted
+
 // Transform is a function that transforms (`processingData`) into
 // (`processingData`), returning any errors that occurred during processing.
 type Transform[ProcessedData any] func(ctx context.Context, processingData []ProcessedData) (processedOut []ProcessedData, err error)
@@ -45,20 +61,45 @@ type Processor[ProcessingData any] struct {
 	// WARN: The output of the processing will not be forwarded!
 	Async bool `default:"false" json:"async"`
 
+	// Timeout of a run. If set, runs taking longer fail with `ErrTimeout`.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// Retry policy. If set, failed runs are retried.
+	Retry *retry.Policy `json:"retry,omitempty"`
+
 	// OnFinished is the function that is called when a processor finishes its
 	// execution.
 	OnFinished OnFinished[ProcessingData] `json:"-"`
 
+	// Buckets of the duration histogram, in milliseconds. See
+	// `SetHistogram`.
+	Buckets []float64 `json:"buckets,omitempty"`
+
+	// Percentiles reported by the duration histogram. See `SetHistogram`.
+	Percentiles []float64 `json:"percentiles,omitempty"`
+
 	// Metrics.
 	CounterCreated     *expvar.Int `json:"counterCreated"`
 	CounterDone        *expvar.Int `json:"counterDone"`
 	CounterFailed      *expvar.Int `json:"counterFailed"`
+	CounterItemsIn     *expvar.Int `json:"counterItemsIn"`
+	CounterItemsOut    *expvar.Int `json:"counterItemsOut"`
 	CounterInterrupted *expvar.Int `json:"counterInterrupted"`
 	CounterRunning     *expvar.Int `json:"counterRunning"`
 
-	CreatedAt time.Time      `json:"createdAt"`
-	Duration  *expvar.Int    `json:"duration"`
-	Status    *expvar.String `json:"status"`
+	CreatedAt         time.Time          `json:"createdAt"`
+	Duration          *expvar.Int        `json:"duration"`
+	DurationHistogram *metrics.Histogram `json:"durationHistogram"`
+	Status            *expvar.String     `json:"status"`
+
+	// pause is this processor's pause controller, combined with those of
+	// its pipeline, run, and stage.
+	pause *shared.PauseController
+
+	// bus is where the processor publishes its pause events, on behalf of the
+	// pipeline named `pipeline`.
+	bus      *event.Bus
+	pipeline string
 }
 
 //////
@@ -135,17 +176,131 @@ func (p *Processor[ProcessingData]) GetDuration() *expvar.Int {
 	return p.Duration
 }
 
+// GetDurationHistogram returns the `DurationHistogram` of the processor.
+func (p *Processor[ProcessingData]) GetDurationHistogram() *metrics.Histogram {
+	return p.DurationHistogram
+}
+
+// SetHistogram sets the buckets, in milliseconds, and the percentiles of the
+// duration histogram, replacing it. If none, the defaults, or those set
+// through the `ETLER_METRICS_BUCKETS`, and `ETLER_METRICS_PERCENTILES`, env
+// vars, are used.
+func (p *Processor[ProcessingData]) SetHistogram(buckets, percentiles []float64) error {
+	p.Buckets = buckets
+	p.Percentiles = percentiles
+
+	durationHistogram, err := p.newDurationHistogram()
+	if err != nil {
+		return err
+	}
+
+	p.DurationHistogram = durationHistogram
+
+	return nil
+}
+
+// newDurationHistogram returns a new duration histogram, with the processor's
+// buckets, and percentiles.
+func (p *Processor[ProcessingData]) newDurationHistogram() (*metrics.Histogram, error) {
+	return metrics.NewHistogramWithPattern(
+		Type,
+		p.GetName(),
+		"duration",
+		metrics.WithBuckets(p.Buckets...),
+		metrics.WithPercentiles(p.Percentiles...),
+	)
+}
+
+// GetCounterItemsIn returns the `CounterItemsIn` metric: the items the
+// processor received.
+func (p *Processor[ProcessingData]) GetCounterItemsIn() *expvar.Int {
+	return p.CounterItemsIn
+}
+
+// GetCounterItemsOut returns the `CounterItemsOut` metric: the items the
+// processor returned.
+func (p *Processor[ProcessingData]) GetCounterItemsOut() *expvar.Int {
+	return p.CounterItemsOut
+}
+
 // GetMetrics returns the stage's metrics.
 func (p *Processor[ProcessingData]) GetMetrics() map[string]string {
-	return map[string]string{
-		"createdAt":      p.GetCreatedAt().String(),
-		"counterCreated": p.GetCounterCreated().String(),
-		"counterDone":    p.GetCounterDone().String(),
-		"counterFailed":  p.GetCounterFailed().String(),
-		"counterRunning": p.GetCounterRunning().String(),
-		"duration":       p.GetDuration().String(),
-		"status":         p.GetStatus().String(),
+	m := map[string]string{
+		"createdAt":          p.GetCreatedAt().String(),
+		"counterCreated":     p.GetCounterCreated().String(),
+		"counterDone":        p.GetCounterDone().String(),
+		"counterFailed":      p.GetCounterFailed().String(),
+		"counterItemsIn":     p.GetCounterItemsIn().String(),
+		"counterItemsOut":    p.GetCounterItemsOut().String(),
+		"counterInterrupted": p.GetCounterInterrupted().String(),
+		"counterRunning":     p.GetCounterRunning().String(),
+		"duration":           p.GetDuration().String(),
+		"status":             p.GetStatus().String(),
+	}
+
+	maps.Copy(m, p.GetDurationHistogram().Metrics("duration"))
+
+	// NOTE: Items out, over the time successful runs took.
+	m["itemsPerSecond"] = strconv.FormatFloat(
+		metrics.Throughput(p.GetCounterItemsOut().Value(), p.GetDurationHistogram().Sum()),
+		'f', 3, 64,
+	)
+
+	return m
+}
+
+// GetPaused returns the Paused status of THIS processor.
+func (p *Processor[ProcessingData]) GetPaused() status.Status {
+	if p.pause.Paused() {
+		return status.Paused
 	}
+
+	return status.Runnning
+}
+
+// SetPause sets the Paused status of THIS processor only. It pauses before
+// its next execution, while the other processors keep running, and resumes
+// immediately on unpause, unless its pipeline, run, or stage, is paused.
+func (p *Processor[ProcessingData]) SetPause(state bool) {
+	e := event.Event{
+		Type:      event.Paused,
+		Pipeline:  p.pipeline,
+		Component: Type,
+		Name:      p.GetName(),
+	}
+
+	if state {
+		p.GetStatus().Set(status.Paused.String())
+
+		p.pause.Pause()
+
+		p.GetEventBus().Publish(context.Background(), e)
+
+		return
+	}
+
+	// Updates the processor's status.
+	p.GetStatus().Set(status.Runnning.String())
+
+	p.pause.Resume()
+
+	e.Type = event.Resumed
+
+	p.GetEventBus().Publish(context.Background(), e)
+}
+
+// GetEventBus returns the bus the processor publishes its pause events to, if
+// any.
+func (p *Processor[ProcessingData]) GetEventBus() *event.Bus {
+	return p.bus
+}
+
+// SetEventBus sets the bus the processor publishes its pause events to, on
+// behalf of the pipeline named `pipeline`. Pipelines set theirs on their
+// stages, and processors, when created.
+func (p *Processor[ProcessingData]) SetEventBus(bus *event.Bus, pipeline string) {
+	p.bus = bus
+	p.pipeline = pipeline
 }
 
 // SetAsync if set will run the processor in a go routine.
@@ -160,8 +315,85 @@ func (p *Processor[ProcessingData]) GetAsync() bool {
 	return p.Async
 }
 
-// Run the transform function.
+// GetTimeout returns the `Timeout` of a run.
+func (p *Processor[ProcessingData]) GetTimeout() time.Duration {
+	return p.Timeout
+}
+
+// SetTimeout sets the `Timeout` of a run.
+func (p *Processor[ProcessingData]) SetTimeout(timeout time.Duration) {
+	p.Timeout = timeout
+}
+
+// GetRetry returns the `Retry` policy.
+func (p *Processor[ProcessingData]) GetRetry() *retry.Policy {
+	return p.Retry
+}
+
+// SetRetry sets the `Retry` policy.
+func (p *Processor[ProcessingData]) SetRetry(policy *retry.Policy) {
+	p.Retry = policy
+}
+
+// Run the transform function. With a retry policy set, failed runs are retried,
+// each attempt traced and counted on its own.
 func (p *Processor[ProcessingData]) Run(ctx context.Context, processingData []ProcessingData) ([]ProcessingData, error) {
+	// NOTE: Counted once, whatever the number of attempts.
+	p.GetCounterItemsIn().Add(int64(len(processingData)))
+
+	event.Publish(ctx, event.Event{
+		Type:      event.ProcessorStarted,
+		Component: Type,
+		Name:      p.GetName(),
+		ItemsIn:   len(processingData),
+	})
+
+	now := time.Now()
+
+	attempts := 0
+
+	out, err := retry.Do(ctx, p.GetRetry(), func(ctx context.Context, attempt int) ([]ProcessingData, error) {
+		attempts = attempt
+
+		return p.run(ctx, processingData, attempt)
+	})
+
+	// Reports the run to the stage running the processor, if any.
+	r := report.Processor{
+		Name:     p.GetName(),
+		Async:    p.GetAsync(),
+		Status:   status.Done.String(),
+		Attempts: attempts,
+		Duration: time.Since(now),
+		ItemsIn:  len(processingData),
+		ItemsOut: len(out),
+		Err:      err,
+	}
+
+	switch {
+	case errors.Is(err, ErrInterrupted):
+		r.Status = status.Interrupted.String()
+	case err != nil:
+		r.Status = status.Failed.String()
+	}
+
+	report.StageFromContext(ctx).RecordProcessor(r)
+
+	event.Publish(ctx, event.Event{
+		Type:      event.Outcome(err, event.ProcessorFinished, event.ProcessorFailed, event.ProcessorInterrupted),
+		Component: Type,
+		Name:      p.GetName(),
+		Duration:  r.Duration,
+		ItemsIn:   r.ItemsIn,
+		ItemsOut:  r.ItemsOut,
+		Err:       err,
+	})
+
+	return out, err
+}
+
+// run is a single attempt of `Run`.
+func (p *Processor[ProcessingData]) run(ctx context.Context, processingData []ProcessingData, attempt int) ([]ProcessingData, error) {
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -176,6 +408,14 @@ func (p *Processor[ProcessingData]) Run(ctx context.Context, processingData []Pr
 	)
 	defer span.End()
 
+	span.SetAttributes(tracing.Int(tracing.KeyItemsIn, len(processingData)))
+
+	if attempt > 1 {
+		span.SetAttributes(tracing.Int(tracing.KeyAttempt, attempt))
+
+		p.GetLogger().PrintlnWithOptions(level.Debug, fmt.Sprintf("retrying, attempt %d", attempt))
+	}
+
 	p.GetStatus().Set(status.Runnning.String())
 
 	p.GetLogger().PrintlnWithOptions(level.Trace, status.Runnning.String())
@@ -184,12 +424,15 @@ func (p *Processor[ProcessingData]) Run(ctx context.Context, processingData []Pr
 	originalProcessingData := processingData
 
 	//////
-	// Pause if the owning pipeline is paused.
+	// Pause if the processor, or what owns it, is paused.
 	//////
 
-	// The pipeline injects its pause controller via the context. A processor
-	// running standalone (no controller in the context) never pauses.
-	if pc := shared.PauseFromCtx(tracedContext); pc != nil && pc.Paused() {
+	// The pipeline injects its pause controllers, its own, the run's, and
+	// the stage's, via the context, combined with the processor's own. The
+	// transform function sees them all.
+	tracedContext = shared.ContextWithPause(tracedContext, p.pause)
+
+	if shared.IsPaused(tracedContext) {
 		//////
 		// Observability: tracing, metrics, status, logging, etc.
 		//////
@@ -200,8 +443,18 @@ func (p *Processor[ProcessingData]) Run(ctx context.Context, processingData []Pr
 		// Notifiy user.
 		p.GetLogger().Tracelnf("Processor %s is paused. Waiting to be resumed...", p.GetName())
 
-		// Blocks until resumed, or until the context is done.
-		if err := pc.Wait(tracedContext); err != nil {
+		// Blocks until resumed, or until the run drains, or the context is
+		// done.
+		err := shared.WaitResumed(tracedContext)
+		if errors.Is(err, shared.ErrInterrupted) {
+			//////
+			// Observability: tracing, metrics, status, logging, etc.
+			//////
+
+			return nil, shared.OnInterruptedHandler(p, p.GetCounterInterrupted(), p.GetLogger())
+		}
+
+		if err != nil {
 			//////
 			// Observability: tracing, metrics, status, logging, etc.
 			//////
@@ -230,7 +483,37 @@ func (p *Processor[ProcessingData]) Run(ctx context.Context, processingData []Pr
 
 	now := time.Now()
 
-	o, err := p.Func(tracedContext, processingData)
+	o, err := shared.RunWithTimeout(tracedContext, p.GetTimeout(), func(ctx context.Context) ([]ProcessingData, error) {
+		return p.Func(ctx, processingData)
+	})
+
+	// The function reached a `pause.Checkpoint` while paused, and the run
+	// drains.
+	if errors.Is(err, shared.ErrInterrupted) {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return nil, shared.OnInterruptedHandler(p, p.GetCounterInterrupted(), p.GetLogger())
+	}
+
+	if errors.Is(err, shared.ErrTimeout) {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		return nil, shared.OnTimeoutHandler(
+			tracedContext,
+			p,
+			p.GetCounterInterrupted(),
+			p.GetLogger(),
+			err,
+			"process",
+			Type,
+			p.GetName(),
+		)
+	}
+
 	if err != nil {
 		//////
 		// Observability: tracing, metrics, status, logging, etc.
@@ -262,8 +545,16 @@ func (p *Processor[ProcessingData]) Run(ctx context.Context, processingData []Pr
 	// Increment the done counter.
 	p.GetCounterDone().Add(1)
 
+	p.GetCounterItemsOut().Add(int64(len(o)))
+
+	span.SetAttributes(tracing.Int(tracing.KeyItemsOut, len(o)))
+
 	// Set duration.
-	p.GetDuration().Set(time.Since(now).Milliseconds())
+	elapsed := time.Since(now)
+
+	p.GetDuration().Set(elapsed.Milliseconds())
+
+	p.GetDurationHistogram().Observe(float64(elapsed.Microseconds()) / 1000)
 
 	// Print the stage's status.
 	p.GetLogger().PrintWithOptions(
@@ -273,6 +564,7 @@ func (p *Processor[ProcessingData]) Run(ctx context.Context, processingData []Pr
 		sypl.WithField("counterCreated", p.GetCounterCreated().String()),
 		sypl.WithField("counterDone", p.GetCounterDone().String()),
 		sypl.WithField("counterFailed", p.GetCounterFailed().String()),
+		sypl.WithField("counterInterrupted", p.GetCounterInterrupted().String()),
 		sypl.WithField("counterRunning", p.GetCounterRunning().String()),
 		sypl.WithField("duration", p.GetDuration().String()),
 		sypl.WithField("status", p.GetStatus().String()),
@@ -294,15 +586,18 @@ func New[ProcessingData any](
 	p := &Processor[ProcessingData]{
 		Func:   fn,
 		Logger: logging.Get().New(name).SetTags(Type, name),
+		pause:  shared.NewPauseController(),
 
 		CreatedAt:   time.Now(),
 		Name:        name,
 		Description: description,
 
-		CounterCreated: metrics.NewIntWithPattern(Type, name, status.Created),
-		CounterDone:    metrics.NewIntWithPattern(Type, name, status.Done),
-		CounterFailed:  metrics.NewIntWithPattern(Type, name, status.Failed),
-		CounterRunning: metrics.NewIntWithPattern(Type, name, status.Runnning),
+		CounterCreated:  metrics.NewIntWithPattern(Type, name, status.Created),
+		CounterDone:     metrics.NewIntWithPattern(Type, name, status.Done),
+		CounterFailed:   metrics.NewIntWithPattern(Type, name, status.Failed),
+		CounterItemsIn:  metrics.NewIntWithPattern(Type, name, "itemsIn"),
+		CounterItemsOut: metrics.NewIntWithPattern(Type, name, "itemsOut"),
+		CounterRunning:  metrics.NewIntWithPattern(Type, name, status.Runnning),
 
 		CounterInterrupted: metrics.NewIntWithPattern(Type, name, status.Interrupted),
 		Duration:           metrics.NewIntWithPattern(Type, name, "duration"),
@@ -319,6 +614,13 @@ func New[ProcessingData any](
 		return nil, err
 	}
 
+	durationHistogram, err := p.newDurationHistogram()
+	if err != nil {
+		return nil, err
+	}
+
+	p.DurationHistogram = durationHistogram
+
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/status"
+)
+
+// Happy path: a processor paused on its own waits, and its pause combines
+// with the one in the context.
+func TestProcessor_SetPause(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ran := make(chan struct{}, 1)
+
+	witness, err := New(
+		"pause-own-witness",
+		"signals when it runs",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			ran <- struct{}{}
+
+			return processingData, nil
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, status.Runnning, witness.GetPaused())
+
+	witness.SetPause(true)
+
+	assert.Equal(t, status.Paused, witness.GetPaused())
+	assert.Equal(t, status.Paused.String(), witness.GetStatus().Value())
+
+	// The pipeline's controller, not paused.
+	pc := shared.NewPauseController()
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := witness.Run(shared.ContextWithPause(ctx, pc), []int{1})
+
+		done <- err
+	}()
+
+	select {
+	case <-ran:
+		t.Fatal("processor ran while paused")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	assert.Equal(t, status.Paused.String(), witness.GetStatus().Value())
+
+	// Edge case: the pipeline pauses, then the processor resumes: it keeps
+	// waiting for the pipeline.
+	pc.Pause()
+	witness.SetPause(false)
+
+	assert.Equal(t, status.Runnning, witness.GetPaused())
+
+	select {
+	case <-ran:
+		t.Fatal("processor ran while its pipeline is paused")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	pc.Resume()
+
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("processor did not resume")
+	}
+
+	require.NoError(t, <-done)
+	assert.Equal(t, status.Done.String(), witness.GetStatus().Value())
+}
@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/retry"
+	"github.com/thalesfsp/status"
+)
+
+// Happy path: a transient failure is retried, every attempt counted.
+func TestProcessor_retry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	calls := 0
+
+	p, err := New(
+		"processor-retry",
+		"fails twice",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			calls++
+
+			if calls < 3 {
+				return nil, errors.New("transient")
+			}
+
+			return processingData, nil
+		},
+		WithRetry[int](retry.Must(3, retry.Constant(time.Millisecond))),
+	)
+	require.NoError(t, err)
+
+	out, err := p.Run(ctx, []int{1})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, out)
+
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, int64(3), p.GetCounterRunning().Value())
+	assert.Equal(t, int64(2), p.GetCounterFailed().Value())
+	assert.Equal(t, int64(1), p.GetCounterDone().Value())
+	assert.Equal(t, status.Done.String(), p.GetStatus().Value())
+}
+
+// Bad path: attempts exhausted, the processor fails.
+func TestProcessor_retry_exhausted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, err := New(
+		"processor-retry-exhausted",
+		"always fails",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			return nil, errors.New("boom-retry")
+		},
+		WithRetry[int](retry.Must(2, retry.Constant(0))),
+	)
+	require.NoError(t, err)
+
+	_, err = p.Run(ctx, []int{1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom-retry")
+
+	assert.Equal(t, int64(2), p.GetCounterRunning().Value())
+	assert.Equal(t, int64(2), p.GetCounterFailed().Value())
+	assert.Equal(t, int64(0), p.GetCounterDone().Value())
+	assert.Equal(t, status.Failed.String(), p.GetStatus().Value())
+}
@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/retry"
+	"github.com/thalesfsp/status"
+)
+
+// Bad path: a hung transform fails with `ErrTimeout`, counted as interrupted.
+func TestProcessor_timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	p, err := New(
+		"processor-timeout",
+		"hangs",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			<-release
+
+			return processingData, nil
+		},
+		WithTimeout[int](20*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 20*time.Millisecond, p.GetTimeout())
+
+	_, err = p.Run(context.Background(), []int{1})
+	require.ErrorIs(t, err, ErrTimeout)
+
+	assert.Equal(t, int64(1), p.GetCounterInterrupted().Value())
+	assert.Equal(t, int64(1), p.GetCounterFailed().Value())
+	assert.Equal(t, "1", p.GetMetrics()["counterInterrupted"])
+	assert.Equal(t, status.Failed.String(), p.GetStatus().Value())
+}
+
+// Happy path: timeouts are worth retrying, each timed out attempt counted.
+func TestProcessor_timeout_retried(t *testing.T) {
+	// Atomic: the timed out attempt is abandoned, not waited for.
+	var calls atomic.Int32
+
+	p, err := New(
+		"processor-timeout-retried",
+		"slow once",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			}
+
+			return processingData, nil
+		},
+		WithTimeout[int](20*time.Millisecond),
+		WithRetry[int](retry.Must(2, retry.Constant(0))),
+	)
+	require.NoError(t, err)
+
+	out, err := p.Run(context.Background(), []int{1})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, out)
+
+	assert.Equal(t, int64(1), p.GetCounterInterrupted().Value())
+	assert.Equal(t, int64(1), p.GetCounterDone().Value())
+}
@@ -0,0 +1,335 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/etler/v3/batch"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/params/v2/create"
+	"github.com/thalesfsp/params/v2/update"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// DefaultTarget is the target (e.g., collection, index, table) items are
+// written to, if not set.
+const DefaultTarget = "etl"
+
+// WriteMode defines how items are written.
+type WriteMode string
+
+const (
+	// ModeCreate creates items. It's the default.
+	ModeCreate WriteMode = "create"
+
+	// ModeUpdate updates items, which must already exist. Requires IDs
+	// derived from items.
+	ModeUpdate WriteMode = "update"
+
+	// ModeUpsert creates items, updating them instead if they already exist,
+	// see `WithConflict`. Requires IDs derived from items.
+	//
+	// NOTE: It isn't atomic: an item deleted between its create, and its
+	// update, fails, and concurrent upserts of the same item race.
+	ModeUpsert WriteMode = "upsert"
+)
+
+// IDFunc derives the ID of an item.
+type IDFunc[In any] func(in In) (string, error)
+
+// ConflictFunc returns whether `err`, creating an item, is because it
+// already exists.
+type ConflictFunc func(err error) bool
+
+// Config defines how, and where items are written.
+type Config[In any] struct {
+	// Target items are written to.
+	Target string `json:"target" validate:"required"`
+
+	// IDPrefix is prepended to every ID, e.g., "news-".
+	IDPrefix string `json:"idPrefix"`
+
+	// ID derives the ID of an item. If not set, a random UUID is generated,
+	// so writes aren't idempotent.
+	ID IDFunc[In] `json:"-"`
+
+	// Mode defines how items are written.
+	Mode WriteMode `json:"mode" validate:"omitempty,oneof=create update upsert"`
+
+	// Conflict returns whether creating an item failed because it already
+	// exists, so the `ModeUpsert` updates it instead. Defaults to
+	// `IsConflict`.
+	Conflict ConflictFunc `json:"-"`
+
+	// Batch, if set, groups items into chunks, written in bulk if the
+	// storage implements `batch.BulkWriter`, else one item at a time.
+	Batch *batch.Policy `json:"batch,omitempty"`
+}
+
+// Func allows to specify config's options.
+type Func[In any] func(c *Config[In]) *Config[In]
+
+//////
+// Built-in ID functions.
+//////
+
+// IDFromField derives IDs from the item's `field` field, falling back to its
+// `ID` field, as `shared.ExtractID` does. Items without ID fail.
+func IDFromField[In any](field string) IDFunc[In] {
+	return func(in In) (string, error) {
+		id := shared.ExtractID(in, field)
+		if id == "" {
+			return "", customerror.NewMissingError(fmt.Sprintf("item ID, from field %q", field))
+		}
+
+		return id, nil
+	}
+}
+
+// IDFromContent derives IDs from the item's content: a hash of its JSON
+// encoding. Items with the same content get the same ID.
+func IDFromContent[In any]() IDFunc[In] {
+	return func(in In) (string, error) {
+		b, err := shared.Marshal(in)
+		if err != nil {
+			return "", err
+		}
+
+		return shared.GenerateIDBasedOnContent(string(b)), nil
+	}
+}
+
+// IsConflict is the default `ConflictFunc`: the error is a
+// `customerror.CustomError` with a 409 (Conflict) status code.
+func IsConflict(err error) bool {
+	var cE *customerror.CustomError
+
+	return errors.As(err, &cE) && cE.StatusCode == http.StatusConflict
+}
+
+//////
+// Built-in options.
+//////
+
+// WithTarget sets the target items are written to.
+func WithTarget[In any](target string) Func[In] {
+	return func(c *Config[In]) *Config[In] {
+		c.Target = target
+
+		return c
+	}
+}
+
+// WithIDPrefix sets the prefix prepended to every ID.
+func WithIDPrefix[In any](idPrefix string) Func[In] {
+	return func(c *Config[In]) *Config[In] {
+		c.IDPrefix = idPrefix
+
+		return c
+	}
+}
+
+// WithID sets how the ID of an item is derived.
+func WithID[In any](id IDFunc[In]) Func[In] {
+	return func(c *Config[In]) *Config[In] {
+		c.ID = id
+
+		return c
+	}
+}
+
+// WithMode sets how items are written.
+func WithMode[In any](mode WriteMode) Func[In] {
+	return func(c *Config[In]) *Config[In] {
+		c.Mode = mode
+
+		return c
+	}
+}
+
+// WithConflict sets how the `ModeUpsert` tells items already existing
+// apart, e.g., for storages not reporting them with a 409 status code.
+func WithConflict[In any](conflict ConflictFunc) Func[In] {
+	return func(c *Config[In]) *Config[In] {
+		c.Conflict = conflict
+
+		return c
+	}
+}
+
+// WithBatch sets how items are grouped into chunks.
+func WithBatch[In any](policy *batch.Policy) Func[In] {
+	return func(c *Config[In]) *Config[In] {
+		c.Batch = policy
+
+		return c
+	}
+}
+
+//////
+// Methods.
+//////
+
+// validate checks the config beyond its tags.
+func (c *Config[In]) validate() error {
+	if c.Mode != "" && c.Mode != ModeCreate && c.ID == nil {
+		return customerror.NewRequiredError(fmt.Sprintf("ID function, for the %s mode", c.Mode))
+	}
+
+	return nil
+}
+
+// id returns the ID of `in`.
+func (c *Config[In]) id(in In) (string, error) {
+	if c.ID == nil {
+		return c.IDPrefix + shared.GenerateUUID(), nil
+	}
+
+	id, err := c.ID(in)
+	if err != nil {
+		return "", err
+	}
+
+	return c.IDPrefix + id, nil
+}
+
+// conflict returns whether creating an item failed because it already
+// exists.
+func (c *Config[In]) conflict(err error) bool {
+	if c.Conflict == nil {
+		return IsConflict(err)
+	}
+
+	return c.Conflict(err)
+}
+
+// op returns the batch operation matching the mode.
+func (c *Config[In]) op() batch.Op {
+	if c.Mode == "" {
+		return batch.OpCreate
+	}
+
+	return batch.Op(c.Mode)
+}
+
+// write writes `in` to `s`, according to the config.
+func (c *Config[In]) write(ctx context.Context, s storage.IStorage, in In) error {
+	id, err := c.id(in)
+	if err != nil {
+		return err
+	}
+
+	return c.writeDocument(ctx, s, id, in)
+}
+
+// writeDocument writes `in`, with `id`, to `s`, according to the mode.
+func (c *Config[In]) writeDocument(ctx context.Context, s storage.IStorage, id string, in any) error {
+	switch c.Mode {
+	case ModeUpdate:
+		return s.Update(ctx, id, c.Target, in, &update.Update{})
+	case ModeUpsert:
+		_, createErr := s.Create(ctx, id, c.Target, in, &create.Create{})
+		if createErr == nil {
+			return nil
+		}
+
+		// Any other failure, e.g., a timeout, is the create's own.
+		if !c.conflict(createErr) {
+			return createErr
+		}
+
+		if err := s.Update(ctx, id, c.Target, in, &update.Update{}); err != nil {
+			return errors.Join(createErr, err)
+		}
+
+		return nil
+	default:
+		_, err := s.Create(ctx, id, c.Target, in, &create.Create{})
+
+		return err
+	}
+}
+
+// writeBatch writes `items` to `s`, chunk by chunk, up to `concurrency` items
+// at once, unless the policy sets its own. It returns a `*batch.Error`
+// listing the failed items, including those without ID.
+func (c *Config[In]) writeBatch(ctx context.Context, s storage.IStorage, items []In, concurrency int) error {
+	docs := make([]batch.Document, 0, len(items))
+
+	// Maps indexes of documents to indexes of items.
+	indexes := make([]int, 0, len(items))
+
+	var failed []*batch.ItemError
+
+	for i, in := range items {
+		id, err := c.id(in)
+		if err != nil {
+			failed = append(failed, &batch.ItemError{Index: i, Err: err})
+
+			continue
+		}
+
+		docs = append(docs, batch.Document{ID: id, Item: in})
+		indexes = append(indexes, i)
+	}
+
+	policy := *c.Batch
+
+	if policy.Concurrency == 0 {
+		policy.Concurrency = concurrency
+	}
+
+	err := batch.Write(ctx, s, &policy, c.op(), c.Target, docs, func(ctx context.Context, doc batch.Document) error {
+		return c.writeDocument(ctx, s, doc.ID, doc.Item)
+	})
+
+	var batchErr *batch.Error
+
+	switch {
+	case errors.As(err, &batchErr):
+		for _, f := range batchErr.Failed {
+			f.Index = indexes[f.Index]
+		}
+
+		failed = append(failed, batchErr.Failed...)
+	case err != nil:
+		return err
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(failed, func(a, b *batch.ItemError) int {
+		return a.Index - b.Index
+	})
+
+	return &batch.Error{Failed: failed, Total: len(items)}
+}
+
+//////
+// Factory.
+//////
+
+// NewConfig returns a new config: items are created, with random IDs, in
+// the `DefaultTarget`.
+func NewConfig[In any](opts ...Func[In]) *Config[In] {
+	c := &Config[In]{
+		Target: DefaultTarget,
+		Mode:   ModeCreate,
+	}
+
+	for _, opt := range opts {
+		c = opt(c)
+	}
+
+	return c
+}
@@ -1,3 +1,14 @@
 // Package storage contains the storage processor which allows to concurrently
 // store the processed data in a storage system.
+//
+// By default, items are created with random IDs, in the "etl" target.
+// `NewWithConfig` makes writes idempotent: IDs are derived from items, from a
+// field (`IDFromField`), or from their content (`IDFromContent`), and items
+// are written with update or upsert semantics (`WithMode`), to any target
+// (`WithTarget`). Re-running a pipeline then doesn't duplicate items.
+//
+// `WithBatch` groups items into chunks, by count and by size: each chunk is
+// written in a single call if the storage implements `batch.BulkWriter`,
+// else one item at a time, concurrently. A `*batch.Error` then lists exactly
+// which items failed.
 package storage
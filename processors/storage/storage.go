@@ -6,9 +6,7 @@ import (
 
 	"github.com/thalesfsp/concurrentloop"
 	"github.com/thalesfsp/dal/v2/storage"
-	"github.com/thalesfsp/etler/v3/internal/shared"
 	"github.com/thalesfsp/etler/v3/processor"
-	"github.com/thalesfsp/params/v2/create"
 	"github.com/thalesfsp/validation"
 )
 
@@ -22,13 +20,17 @@ const Name = "storage"
 // Storage definition.
 type Storage[In any] struct {
 	processor.IProcessor[In] `json:"processor" validate:"required"`
+
+	// Config defines how, and where items are written.
+	Config *Config[In] `json:"config" validate:"required"`
 }
 
 //////
 // Factory.
 //////
 
-// New creates a new Storage processor.
+// New creates a new Storage processor, creating items with random IDs in the
+// `DefaultTarget`.
 //
 // NOTE: Mininum concurrency is 1.
 // NOTE: idPrefix example: "news-"
@@ -37,6 +39,20 @@ func New[In any](
 	concurrency int,
 	idPrefix string,
 	opts ...processor.Func[In],
+) (*Storage[In], error) {
+	return NewWithConfig(s, concurrency, NewConfig(WithIDPrefix[In](idPrefix)), opts...)
+}
+
+// NewWithConfig creates a new Storage processor, writing items according to
+// `config`. Deriving IDs from items, with `IDFromField` or `IDFromContent`,
+// makes writes idempotent: re-running a pipeline doesn't duplicate items.
+//
+// NOTE: Mininum concurrency is 1.
+func NewWithConfig[In any](
+	s storage.IStorage,
+	concurrency int,
+	config *Config[In],
+	opts ...processor.Func[In],
 ) (*Storage[In], error) {
 	// Enforces interface implementation.
 	var _ processor.IProcessor[In] = (*Storage[In])(nil)
@@ -45,13 +61,9 @@ func New[In any](
 	// Allows to control the concurrency.
 	//////
 
-	concurrentloopOpts := []concurrentloop.Func{}
+	concurrency = max(concurrency, 1)
 
-	if concurrency < 1 {
-		concurrentloopOpts = append(concurrentloopOpts, concurrentloop.WithBatchSize(1))
-	} else {
-		concurrentloopOpts = append(concurrentloopOpts, concurrentloop.WithBatchSize(concurrency))
-	}
+	concurrentloopOpts := []concurrentloop.Func{concurrentloop.WithBatchSize(concurrency)}
 
 	// NOTE: Because this processor doesn't change the input data, it's
 	// safe to use the input as the output.
@@ -59,20 +71,23 @@ func New[In any](
 		Name,
 		fmt.Sprintf("%s %s", Name, processor.Type),
 		func(tracedContext context.Context, processingData []In) ([]In, error) {
-			// Concurrently creates the data.
+			// Writes the data in chunks.
+			if config.Batch != nil {
+				if err := config.writeBatch(tracedContext, s, processingData, concurrency); err != nil {
+					return nil, err
+				}
+
+				return processingData, nil
+			}
+
+			// Concurrently writes the data.
 			if _, errs := concurrentloop.Map(
 				tracedContext,
 				processingData,
 				func(ctx context.Context, in In) (In, error) {
 					// ID is lost because `in` is Generic and the Run func
 					// signature forces to return the same type as the input.
-					if _, err := s.Create(
-						tracedContext,
-						idPrefix+shared.GenerateUUID(),
-						"etl",
-						in,
-						&create.Create{},
-					); err != nil {
+					if err := config.write(tracedContext, s, in); err != nil {
 						return *new(In), err
 					}
 
@@ -92,7 +107,8 @@ func New[In any](
 	}
 
 	str := &Storage[In]{
-		proc,
+		IProcessor: proc,
+		Config:     config,
 	}
 
 	// Validation.
@@ -100,6 +116,10 @@ func New[In any](
 		return nil, err
 	}
 
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
 	return str, nil
 }
 
@@ -117,3 +137,19 @@ func Must[In any](
 
 	return c
 }
+
+// MustWithConfig returns a new processor, writing items according to
+// `config`, or panics if an error occurs.
+func MustWithConfig[In any](
+	s storage.IStorage,
+	concurrency int,
+	config *Config[In],
+	opts ...processor.Func[In],
+) *Storage[In] {
+	c, err := NewWithConfig(s, concurrency, config, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/batch"
+)
+
+// bulkStorage is a keyed stub storage with bulk support, recording the size
+// of its chunks.
+type bulkStorage struct {
+	*keyedStorage
+
+	mu     sync.Mutex
+	chunks []int
+	ops    []batch.Op
+}
+
+func (s *bulkStorage) BulkWrite(ctx context.Context, op batch.Op, target string, docs []batch.Document) ([]error, error) {
+	s.mu.Lock()
+	s.chunks = append(s.chunks, len(docs))
+	s.ops = append(s.ops, op)
+	s.mu.Unlock()
+
+	errs := make([]error, len(docs))
+
+	for i, doc := range docs {
+		if doc.ID == "bad" {
+			errs[i] = errors.New("rejected")
+
+			continue
+		}
+
+		s.keyedStorage.mu.Lock()
+		s.items[target+"/"+doc.ID] = doc.Item
+		s.keyedStorage.mu.Unlock()
+	}
+
+	return errs, nil
+}
+
+// Happy path: items are written in bulk, chunk by chunk.
+func TestNewWithConfig_batchBulk(t *testing.T) {
+	s := &bulkStorage{keyedStorage: newKeyedStorage()}
+
+	p := MustWithConfig(s, 1, NewConfig(
+		WithID(IDFromField[keyedItem]("sku")),
+		WithMode[keyedItem](ModeUpsert),
+		WithBatch[keyedItem](batch.Must(batch.WithMaxItems(2))),
+	))
+
+	in := []keyedItem{{SKU: "a"}, {SKU: "b"}, {SKU: "c"}}
+
+	out, err := p.Run(context.Background(), in)
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+
+	assert.Len(t, s.items, 3)
+	assert.Equal(t, []int{2, 1}, s.chunks)
+	assert.Equal(t, []batch.Op{batch.OpUpsert, batch.OpUpsert}, s.ops)
+	assert.Equal(t, 0, s.creates, "no per-item writes")
+}
+
+// Bad path: the error lists exactly which items failed, including those
+// without ID, by their index in the input.
+func TestNewWithConfig_batchFailedItems(t *testing.T) {
+	s := &bulkStorage{keyedStorage: newKeyedStorage()}
+
+	p := MustWithConfig(s, 1, NewConfig(
+		WithID(IDFromField[keyedItem]("sku")),
+		WithBatch[keyedItem](batch.Must(batch.WithMaxItems(2))),
+	))
+
+	_, err := p.Run(context.Background(), []keyedItem{{SKU: "a"}, {Name: "no sku"}, {SKU: "bad"}, {SKU: "d"}})
+	require.Error(t, err)
+
+	var batchErr *batch.Error
+
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, []int{1, 2}, batchErr.Indexes())
+	assert.Equal(t, 4, batchErr.Total)
+	assert.Equal(t, "bad", batchErr.Failed[1].ID)
+
+	// Good items were written anyway.
+	assert.Len(t, s.items, 2)
+}
+
+// Happy path: without bulk support, items are written one at a time, with
+// the config's mode.
+func TestNewWithConfig_batchPerItem(t *testing.T) {
+	s := newKeyedStorage()
+	s.items["etl/a"] = keyedItem{SKU: "a"}
+
+	p := MustWithConfig(s, 1, NewConfig(
+		WithID(IDFromField[keyedItem]("sku")),
+		WithMode[keyedItem](ModeUpsert),
+		WithBatch[keyedItem](batch.Must(batch.WithMaxBytes(64))),
+	))
+
+	_, err := p.Run(context.Background(), []keyedItem{{SKU: "a", Name: "updated"}, {SKU: "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, keyedItem{SKU: "a", Name: "updated"}, s.items["etl/a"])
+	assert.Len(t, s.items, 2)
+}
+
+// Bad path: invalid batch policies are rejected.
+func TestNewWithConfig_batchInvalid(t *testing.T) {
+	_, err := NewWithConfig(newKeyedStorage(), 1, NewConfig(WithBatch[keyedItem](&batch.Policy{MaxItems: -1})))
+	require.Error(t, err)
+}
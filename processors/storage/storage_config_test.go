@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/dal/v2/storage"
+	"github.com/thalesfsp/params/v2/create"
+	"github.com/thalesfsp/params/v2/update"
+)
+
+type keyedItem struct {
+	SKU  string `json:"sku"`
+	Name string `json:"name"`
+}
+
+// keyedStorage is a stub storage, keyed by target and ID. Like most
+// databases, creating an existing item fails, and so does updating a missing
+// one.
+type keyedStorage struct {
+	storage.IStorage
+
+	mu       sync.Mutex
+	items    map[string]any
+	creates  int
+	updates  int
+	failWith error
+}
+
+func newKeyedStorage() *keyedStorage {
+	return &keyedStorage{items: map[string]any{}}
+}
+
+func (s *keyedStorage) Create(ctx context.Context, id, target string, v any, prm *create.Create, options ...storage.Func[*create.Create]) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.creates++
+
+	if s.failWith != nil {
+		return "", s.failWith
+	}
+
+	if _, ok := s.items[target+"/"+id]; ok {
+		return "", customerror.NewHTTPError(http.StatusConflict)
+	}
+
+	s.items[target+"/"+id] = v
+
+	return id, nil
+}
+
+func (s *keyedStorage) Update(ctx context.Context, id, target string, v any, prm *update.Update, options ...storage.Func[*update.Update]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updates++
+
+	if s.failWith != nil {
+		return s.failWith
+	}
+
+	if _, ok := s.items[target+"/"+id]; !ok {
+		return errors.New("not found")
+	}
+
+	s.items[target+"/"+id] = v
+
+	return nil
+}
+
+// Happy path: upserting with IDs from a field is idempotent, and updates
+// existing items.
+func TestNewWithConfig_upsertFromField(t *testing.T) {
+	s := newKeyedStorage()
+
+	p := MustWithConfig(s, 2, NewConfig(
+		WithTarget[keyedItem]("products"),
+		WithIDPrefix[keyedItem]("p-"),
+		WithID(IDFromField[keyedItem]("SKU")),
+		WithMode[keyedItem](ModeUpsert),
+	))
+
+	in := []keyedItem{{SKU: "a", Name: "apple"}, {SKU: "b", Name: "banana"}}
+
+	_, err := p.Run(context.Background(), in)
+	require.NoError(t, err)
+
+	// Re-running, e.g., a nightly job, doesn't duplicate items.
+	_, err = p.Run(context.Background(), []keyedItem{{SKU: "a", Name: "apricot"}, {SKU: "b", Name: "banana"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"products/p-a": keyedItem{SKU: "a", Name: "apricot"},
+		"products/p-b": keyedItem{SKU: "b", Name: "banana"},
+	}, s.items)
+	assert.Equal(t, 4, s.creates)
+	assert.Equal(t, 2, s.updates)
+}
+
+// Happy path: IDs from content, identical items are written once.
+func TestNewWithConfig_idFromContent(t *testing.T) {
+	s := newKeyedStorage()
+
+	p := MustWithConfig(s, 1, NewConfig(
+		WithID(IDFromContent[keyedItem]()),
+		WithMode[keyedItem](ModeUpsert),
+	))
+
+	_, err := p.Run(context.Background(), []keyedItem{{Name: "x"}, {Name: "x"}, {Name: "y"}})
+	require.NoError(t, err)
+
+	assert.Len(t, s.items, 2)
+
+	for key := range s.items {
+		assert.Regexp(t, "^"+DefaultTarget+"/[0-9a-f]{40}$", key)
+	}
+}
+
+// Happy path: update mode only updates.
+func TestNewWithConfig_update(t *testing.T) {
+	s := newKeyedStorage()
+	s.items["etl/a"] = keyedItem{SKU: "a"}
+
+	p := MustWithConfig(s, 1, NewConfig(
+		WithID(IDFromField[keyedItem]("sku")),
+		WithMode[keyedItem](ModeUpdate),
+	))
+
+	_, err := p.Run(context.Background(), []keyedItem{{SKU: "a", Name: "updated"}})
+	require.NoError(t, err)
+	assert.Equal(t, keyedItem{SKU: "a", Name: "updated"}, s.items["etl/a"])
+	assert.Equal(t, 0, s.creates)
+
+	// Bad path: missing items fail.
+	_, err = p.Run(context.Background(), []keyedItem{{SKU: "missing"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+// Bad path: items without ID fail, and so do upserts failing both ways.
+func TestNewWithConfig_writeErrors(t *testing.T) {
+	p := MustWithConfig(newKeyedStorage(), 1, NewConfig(
+		WithID(IDFromField[keyedItem]("sku")),
+		WithMode[keyedItem](ModeUpsert),
+	))
+
+	_, err := p.Run(context.Background(), []keyedItem{{Name: "no sku"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "item ID")
+
+	stub := newKeyedStorage()
+	stub.failWith = errors.New("unavailable")
+
+	p = MustWithConfig(stub, 1, NewConfig(
+		WithID(IDFromField[keyedItem]("sku")),
+		WithMode[keyedItem](ModeUpsert),
+	))
+
+	_, err = p.Run(context.Background(), []keyedItem{{SKU: "a"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unavailable")
+
+	// Not a conflict: not updated instead.
+	assert.Equal(t, 1, stub.creates)
+	assert.Equal(t, 0, stub.updates)
+}
+
+// Edge case: a custom conflict function tells items already existing apart.
+func TestNewWithConfig_upsertConflict(t *testing.T) {
+	assert.True(t, IsConflict(fmt.Errorf("create: %w", customerror.NewHTTPError(http.StatusConflict))))
+	assert.False(t, IsConflict(customerror.NewHTTPError(http.StatusUnauthorized)))
+	assert.False(t, IsConflict(errors.New("duplicate key")))
+
+	s := newKeyedStorage()
+	s.items["etl/a"] = keyedItem{SKU: "a"}
+
+	p := MustWithConfig(s, 1, NewConfig(
+		WithID(IDFromField[keyedItem]("sku")),
+		WithMode[keyedItem](ModeUpsert),
+		WithConflict[keyedItem](func(err error) bool {
+			return customerror.IsHTTPStatus(err, http.StatusConflict)
+		}),
+	))
+
+	_, err := p.Run(context.Background(), []keyedItem{{SKU: "a", Name: "apple"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, keyedItem{SKU: "a", Name: "apple"}, s.items["etl/a"])
+	assert.Equal(t, 1, s.updates)
+}
+
+// Bad path: invalid configs are rejected.
+func TestNewWithConfig_invalid(t *testing.T) {
+	s := newKeyedStorage()
+
+	tests := []struct {
+		name   string
+		config *Config[keyedItem]
+	}{
+		{name: "nil", config: nil},
+		{name: "no target", config: NewConfig(WithTarget[keyedItem](""))},
+		{name: "unknown mode", config: NewConfig(WithMode[keyedItem]("replace"))},
+		{name: "upsert without ID", config: NewConfig(WithMode[keyedItem](ModeUpsert))},
+		{name: "update without ID", config: NewConfig(WithMode[keyedItem](ModeUpdate))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWithConfig(s, 1, tt.config)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// Package prometheus exports etler metrics in the Prometheus text format,
+// without a third-party client.
+//
+// `New` returns an `http.Handler` exporting the counters, status, durations,
+// and any other numeric metric of pipelines, stages, processors, converters
+// and loaders. Rather than dotted expvar names, metrics are families,
+// labelled by `type`, `name`, and, for runs and status, `status`:
+//
+//	etler_runs_total{type="processor",name="double",status="done"} 3
+//	etler_status{type="processor",name="double",status="done"} 1
+//	etler_duration_milliseconds{type="processor",name="double"} 12
+//	etler_run_duration_milliseconds_bucket{type="processor",name="double",le="25"} 3
+//	etler_items_in_total{type="processor",name="double"} 300
+//
+// Metrics published to expvar (`ETLER_METRICS_PUBLISH=true`) are exported
+// as they are created. Components can also be registered explicitly, with
+// `WithComponents`, or `Register`, whether metrics are published or not.
+package prometheus
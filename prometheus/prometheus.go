@@ -0,0 +1,416 @@
+package prometheus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/thalesfsp/etler/v3/internal/metrics"
+	"github.com/thalesfsp/status"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// ContentType of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric families.
+const (
+	// RunsTotal counts the runs of a component, by status.
+	RunsTotal = "etler_runs_total"
+
+	// Status is set, to 1, for the current status of a component.
+	Status = "etler_status"
+
+	// DurationMilliseconds is the duration of the last run of a component.
+	DurationMilliseconds = "etler_duration_milliseconds"
+
+	// DeadLettersTotal counts the items dropped by a stage.
+	DeadLettersTotal = "etler_dead_letters_total"
+
+	// ItemsInTotal counts the items received by a processor, or a stage.
+	ItemsInTotal = "etler_items_in_total"
+
+	// ItemsOutTotal counts the items returned by a processor, or a stage.
+	ItemsOutTotal = "etler_items_out_total"
+
+	// RunDurationMilliseconds is the histogram of the durations of the runs
+	// of a component.
+	RunDurationMilliseconds = "etler_run_duration_milliseconds"
+)
+
+// Component is an entity whose metrics are exported, e.g., a pipeline, a
+// stage, a processor, a converter, or a loader.
+type Component interface {
+	// GetType returns the entity type.
+	GetType() string
+
+	// GetName returns the `Name` of the entity.
+	GetName() string
+
+	// GetMetrics returns the entity's metrics.
+	GetMetrics() map[string]string
+}
+
+// Handler exports etler metrics, in the Prometheus text format.
+type Handler struct {
+	mu         sync.RWMutex
+	components []Component
+}
+
+// Func allows to specify handler's options.
+type Func func(h *Handler) *Handler
+
+// family is a metric family.
+type family struct {
+	help string
+	typ  string
+}
+
+// sample is a sample of a metric family.
+type sample struct {
+	family string
+
+	// name of the series, if not the family's, e.g., `<family>_bucket`.
+	name string
+
+	// labels of the component.
+	labels string
+
+	// le is the upper bound of a histogram bucket.
+	le string
+
+	// order of the sample, among those of the same component, e.g., buckets.
+	order int
+
+	value float64
+}
+
+// histogrammer is implemented by components with a duration histogram.
+type histogrammer interface {
+	GetDurationHistogram() *metrics.Histogram
+}
+
+// families known in advance. Any other numeric metric is exported as a
+// gauge, named after it.
+var families = map[string]family{
+	RunsTotal:            {help: "Runs of the component, by status.", typ: "counter"},
+	Status:               {help: "Current status of the component.", typ: "gauge"},
+	DurationMilliseconds: {help: "Duration of the last run of the component, in milliseconds.", typ: "gauge"},
+	DeadLettersTotal:     {help: "Items dropped by the stage.", typ: "counter"},
+	ItemsInTotal:         {help: "Items received by the component.", typ: "counter"},
+	ItemsOutTotal:        {help: "Items returned by the component.", typ: "counter"},
+
+	RunDurationMilliseconds: {help: "Durations of the runs of the component, in milliseconds.", typ: "histogram"},
+}
+
+// histogramMetric matches the metrics derived from a duration histogram,
+// exported as the histogram itself, e.g., `durationP99`.
+var histogramMetric = regexp.MustCompile(`^duration(Count|Sum|P[0-9.]+)$`)
+
+// runStatuses are the statuses runs are counted by.
+var runStatuses = []string{
+	status.Created.String(),
+	status.Runnning.String(),
+	status.Failed.String(),
+	status.Done.String(),
+	status.Interrupted.String(),
+}
+
+//////
+// Helpers.
+//////
+
+// escape escapes a label value.
+func escape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// labels formats label pairs.
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], escape(pairs[i+1])))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// familyName returns the name of the family of a metric, e.g.,
+// `progress` -> `etler_progress`.
+func familyName(metric string) string {
+	var b strings.Builder
+
+	b.WriteString(metrics.Name + "_")
+
+	for i, r := range metric {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 {
+				b.WriteRune('_')
+			}
+
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	return b.String()
+}
+
+// normalize returns the metric a `GetMetrics` key stands for, e.g.,
+// `counterDone` -> `done`.
+func normalize(key string) string {
+	metric, ok := strings.CutPrefix(key, "counter")
+	if !ok || metric == "" {
+		return key
+	}
+
+	return strings.ToLower(metric[:1]) + metric[1:]
+}
+
+// toSample converts a metric of a component into a sample, if it's numeric,
+// or the status.
+func toSample(t, name, metric, raw string) (sample, bool) {
+	if metric == status.Name {
+		var value string
+
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+
+		return sample{family: Status, labels: labels("type", t, "name", name, "status", value), value: 1}, true
+	}
+
+	if histogramMetric.MatchString(metric) {
+		return sample{}, false
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return sample{}, false
+	}
+
+	if slices.Contains(runStatuses, metric) {
+		return sample{family: RunsTotal, labels: labels("type", t, "name", name, "status", metric), value: value}, true
+	}
+
+	f, ok := map[string]string{
+		"duration":     DurationMilliseconds,
+		"deadLettered": DeadLettersTotal,
+		"itemsIn":      ItemsInTotal,
+		"itemsOut":     ItemsOutTotal,
+	}[metric]
+	if !ok {
+		f = familyName(metric)
+	}
+
+	return sample{family: f, labels: labels("type", t, "name", name), value: value}, true
+}
+
+// histogramSamples converts a duration histogram of a component into
+// samples: cumulative buckets, sum, and count.
+func histogramSamples(t, name string, h *metrics.Histogram) []sample {
+	snapshot := h.Snapshot()
+	lbls := labels("type", t, "name", name)
+
+	samples := make([]sample, 0, len(snapshot.Buckets)+3)
+
+	for i, b := range snapshot.Buckets {
+		samples = append(samples, sample{
+			family: RunDurationMilliseconds,
+			name:   RunDurationMilliseconds + "_bucket",
+			labels: lbls,
+			le:     strconv.FormatFloat(b.UpperBound, 'g', -1, 64),
+			order:  i,
+			value:  float64(b.Count),
+		})
+	}
+
+	n := len(snapshot.Buckets)
+
+	return append(samples,
+		sample{RunDurationMilliseconds, RunDurationMilliseconds + "_bucket", lbls, "+Inf", n, float64(snapshot.Count)},
+		sample{RunDurationMilliseconds, RunDurationMilliseconds + "_sum", lbls, "", n + 1, snapshot.Sum},
+		sample{RunDurationMilliseconds, RunDurationMilliseconds + "_count", lbls, "", n + 2, float64(snapshot.Count)},
+	)
+}
+
+//////
+// Built-in options.
+//////
+
+// WithComponents exports the metrics of `components`. See `Register`.
+func WithComponents(components ...Component) Func {
+	return func(h *Handler) *Handler {
+		h.Register(components...)
+
+		return h
+	}
+}
+
+//////
+// Methods.
+//////
+
+// Register exports the metrics of `components`, even if metrics aren't
+// published. They take precedence over published metrics of the same
+// component.
+func (h *Handler) Register(components ...Component) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.components = append(h.components, components...)
+}
+
+// collect returns the samples, by family, and labels.
+func (h *Handler) collect() map[string]sample {
+	samples := map[string]sample{}
+
+	add := func(s sample, ok bool) {
+		if ok {
+			samples[s.family+s.name+s.labels+s.le] = s
+		}
+	}
+
+	addHistogram := func(t, name string, h *metrics.Histogram) {
+		for _, s := range histogramSamples(t, name, h) {
+			add(s, true)
+		}
+	}
+
+	// Published metrics, see `ETLER_METRICS_PUBLISH`.
+	expvar.Do(func(kv expvar.KeyValue) {
+		t, name, metric, ok := metrics.ParsePattern(kv.Key)
+		if !ok {
+			return
+		}
+
+		if h, isHistogram := kv.Value.(*metrics.Histogram); isHistogram {
+			addHistogram(t, name, h)
+
+			return
+		}
+
+		add(toSample(t, name, metric, kv.Value.String()))
+	})
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, c := range h.components {
+		for key, raw := range c.GetMetrics() {
+			add(toSample(c.GetType(), c.GetName(), normalize(key), raw))
+		}
+
+		if hc, ok := c.(histogrammer); ok && hc.GetDurationHistogram() != nil {
+			addHistogram(c.GetType(), c.GetName(), hc.GetDurationHistogram())
+		}
+	}
+
+	return samples
+}
+
+// Write writes the metrics to `w`, in the Prometheus text format.
+func (h *Handler) Write(w io.Writer) error {
+	byFamily := map[string][]sample{}
+
+	for _, s := range h.collect() {
+		byFamily[s.family] = append(byFamily[s.family], s)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	names := make([]string, 0, len(byFamily))
+
+	for name := range byFamily {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		f, ok := families[name]
+		if !ok {
+			f = family{help: "etler metric.", typ: "gauge"}
+		}
+
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.typ)
+
+		samples := byFamily[name]
+
+		slices.SortFunc(samples, func(a, b sample) int {
+			if c := strings.Compare(a.labels, b.labels); c != 0 {
+				return c
+			}
+
+			return a.order - b.order
+		})
+
+		for _, s := range samples {
+			name, lbls := s.family, s.labels
+
+			if s.name != "" {
+				name = s.name
+			}
+
+			if s.le != "" {
+				lbls = strings.TrimSuffix(lbls, "}") + `,le="` + s.le + `"}`
+			}
+
+			fmt.Fprintf(bw, "%s%s %s\n", name, lbls, strconv.FormatFloat(s.value, 'g', -1, 64))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ServeHTTP implements the `http.Handler` interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var buf bytes.Buffer
+
+	if err := h.Write(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+
+	_, _ = w.Write(buf.Bytes())
+}
+
+//////
+// Factory.
+//////
+
+// New returns a new handler, exporting the published metrics, see
+// `ETLER_METRICS_PUBLISH`, and those of the registered components.
+func New(opts ...Func) *Handler {
+	// Enforces interface implementation.
+	var _ http.Handler = (*Handler)(nil)
+
+	h := &Handler{}
+
+	for _, opt := range opts {
+		h = opt(h)
+	}
+
+	return h
+}
@@ -0,0 +1,128 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/etler/v3/converters/passthru"
+	"github.com/thalesfsp/etler/v3/internal/metrics"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/stage"
+)
+
+// component is a component with fixed metrics.
+type component struct {
+	t, name string
+	metrics map[string]string
+}
+
+func (c *component) GetType() string               { return c.t }
+func (c *component) GetName() string               { return c.name }
+func (c *component) GetMetrics() map[string]string { return c.metrics }
+
+func TestHandler_registered(t *testing.T) {
+	p, err := processor.New("prom-double", "doubles", func(ctx context.Context, in []int) ([]int, error) {
+		return in, nil
+	})
+	assert.NoError(t, err)
+
+	_, err = p.Run(context.Background(), []int{1})
+	assert.NoError(t, err)
+
+	h := New(WithComponents(p))
+
+	h.Register(&component{t: "stage", name: `odd"name`, metrics: map[string]string{
+		"createdAt":           "2026-01-01 00:00:00 +0000 UTC",
+		"counterDeadLettered": "2",
+		"progress":            "1",
+		"progressPercent":     `"50%"`,
+		"status":              `"running"`,
+	}})
+
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+
+	// Happy path: counters, status, and durations, labelled.
+	for _, line := range []string{
+		"# HELP etler_runs_total Runs of the component, by status.\n# TYPE etler_runs_total counter\n",
+		`etler_runs_total{type="processor",name="prom-double",status="done"} 1`,
+		`etler_runs_total{type="processor",name="prom-double",status="failed"} 0`,
+		`etler_status{type="processor",name="prom-double",status="done"} 1`,
+		"# TYPE etler_duration_milliseconds gauge\n",
+		`etler_duration_milliseconds{type="processor",name="prom-double"}`,
+		`etler_dead_letters_total{type="stage",name="odd\"name"} 2`,
+		"# TYPE etler_progress gauge\n",
+		`etler_progress{type="stage",name="odd\"name"} 1`,
+		`etler_status{type="stage",name="odd\"name",status="running"} 1`,
+		`etler_items_in_total{type="processor",name="prom-double"} 1`,
+		`etler_items_out_total{type="processor",name="prom-double"} 1`,
+		"# TYPE etler_run_duration_milliseconds histogram\n",
+		`etler_run_duration_milliseconds_bucket{type="processor",name="prom-double",le="60000"} 1`,
+		`etler_run_duration_milliseconds_bucket{type="processor",name="prom-double",le="+Inf"} 1`,
+		`etler_run_duration_milliseconds_count{type="processor",name="prom-double"} 1`,
+		`etler_run_duration_milliseconds_sum{type="processor",name="prom-double"}`,
+	} {
+		assert.Contains(t, body, line)
+	}
+
+	// Edge case: non-numeric metrics are skipped.
+	assert.NotContains(t, body, "created_at")
+	assert.NotContains(t, body, "progress_percent")
+	assert.NotContains(t, body, "etler_duration_p")
+
+	// Edge case: buckets are ordered by upper bound, then sum, and count.
+	assert.Less(t,
+		strings.Index(body, `le="5"`),
+		strings.Index(body, `le="10"`),
+	)
+	assert.Less(t,
+		strings.Index(body, `le="+Inf"`),
+		strings.Index(body, "etler_run_duration_milliseconds_sum"),
+	)
+
+	// Edge case: families are written once, sorted.
+	assert.Equal(t, 1, strings.Count(body, "# TYPE etler_runs_total"))
+	assert.Less(t, strings.Index(body, "etler_dead_letters_total"), strings.Index(body, "etler_runs_total"))
+}
+
+func TestHandler_published(t *testing.T) {
+	t.Setenv(metrics.PublishEnvVar, "true")
+
+	p, err := processor.New("prom-published", "noop", func(ctx context.Context, in []int) ([]int, error) {
+		return in, nil
+	})
+	assert.NoError(t, err)
+
+	_, err = stage.New("prom.published", "published", passthru.Must[int](), p)
+	assert.NoError(t, err)
+
+	var b strings.Builder
+
+	assert.NoError(t, New().Write(&b))
+
+	// Happy path: published metrics are exported, without registering them,
+	// dotted names included.
+	//
+	// NOTE: Published metrics persist for the process lifetime, so values
+	// aren't checked, keeping the test idempotent across -count reruns.
+	assert.Contains(t, b.String(), `etler_runs_total{type="stage",name="prom.published",status="created"} `)
+	assert.Contains(t, b.String(), `etler_runs_total{type="processor",name="prom-published",status="created"} `)
+	assert.Contains(t, b.String(), `etler_status{type="stage",name="prom.published",status="created"} 1`)
+	assert.Contains(t, b.String(), `etler_run_duration_milliseconds_count{type="stage",name="prom.published"} `)
+}
+
+func TestFamilyName(t *testing.T) {
+	assert.Equal(t, "etler_progress", familyName("progress"))
+	assert.Equal(t, "etler_items_in", familyName("itemsIn"))
+	assert.Equal(t, "etler_p_99", familyName("p.99"))
+}
@@ -0,0 +1,172 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/stage"
+	"gopkg.in/yaml.v3"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Options of a component, handed as they are to its factory. See `Decode`.
+type Options map[string]any
+
+// Component defines a converter, or a loader.
+type Component struct {
+	// Name of the component. Defaults to its type.
+	Name string `json:"name,omitempty"`
+
+	// Type of the component, as registered.
+	Type string `json:"type" validate:"required"`
+
+	// Options of the component.
+	Options Options `json:"options,omitempty"`
+}
+
+// ProcessorDefinition defines a processor of a stage.
+type ProcessorDefinition struct {
+	Component
+
+	// Async if set will run the processor in a go routine.
+	Async bool `json:"async,omitempty"`
+
+	// Disabled processors are skipped.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// StageDefinition defines a stage.
+type StageDefinition struct {
+	// Name of the stage, unique in the pipeline.
+	Name string `json:"name" validate:"required"`
+
+	// Description of the stage.
+	Description string `json:"description,omitempty"`
+
+	// Converter of the stage.
+	Converter Component `json:"converter"`
+
+	// Processors of the stage, in order.
+	Processors []ProcessorDefinition `json:"processors,omitempty" validate:"dive"`
+
+	// DependsOn lists the upstream stages the stage consumes. See
+	// `pipeline.WithDependencies`.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// ErrorPolicy of the stage. See `stage.WithErrorPolicy`.
+	//
+	// NOTE: Validated by `Registry.Validate`, which reports its path.
+	ErrorPolicy *stage.ErrorPolicy `json:"errorPolicy,omitempty" validate:"-"`
+
+	// Concurrency of the stage's conversion. See `stage.WithConcurrency`.
+	//
+	// NOTE: Validated by `Registry.Validate`, which reports its path.
+	Concurrency *stage.Concurrency `json:"concurrency,omitempty" validate:"-"`
+}
+
+// Definition defines a pipeline.
+type Definition struct {
+	// Name of the pipeline.
+	Name string `json:"name" validate:"required"`
+
+	// Description of the pipeline.
+	Description string `json:"description,omitempty"`
+
+	// ConcurrentStage if set will run the stages concurrently.
+	ConcurrentStage bool `json:"concurrentStage,omitempty"`
+
+	// Loader, if set, is the extract step of the pipeline. See
+	// `pipeline.WithLoaderSource`.
+	Loader *Component `json:"loader,omitempty"`
+
+	// Stages of the pipeline, in order.
+	Stages []StageDefinition `json:"stages" validate:"required,min=1,dive"`
+}
+
+//////
+// Exported functions.
+//////
+
+// ComponentName returns the name a built component gets: its own, or its
+// type if not set.
+func ComponentName(c Component) string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	return c.Type
+}
+
+// Decode decodes `options` into `v`, a pointer to a struct with `json` tags,
+// rejecting unknown options. Factories use it to read their options.
+func Decode(options Options, v any) error {
+	b, err := shared.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return customerror.NewFailedToError("decode options", customerror.WithError(err))
+	}
+
+	return nil
+}
+
+// Parse parses a definition, in YAML, or JSON, which is valid YAML. Unknown
+// fields are rejected.
+func Parse(r io.Reader) (*Definition, error) {
+	// NOTE: Definitions are decoded through JSON, so their `json` tags, and
+	// those of the types they reuse (e.g., `stage.ErrorPolicy`), apply to
+	// YAML too.
+	var raw any
+
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, customerror.NewMissingError("definition")
+		}
+
+		return nil, customerror.NewFailedToError("parse definition", customerror.WithError(err))
+	}
+
+	b, err := shared.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+
+	decoder.DisallowUnknownFields()
+
+	var def Definition
+
+	if err := decoder.Decode(&def); err != nil {
+		return nil, customerror.NewFailedToError("decode definition", customerror.WithError(err))
+	}
+
+	return &def, nil
+}
+
+// ParseFile parses the definition in the file at `path`.
+func ParseFile(path string) (*Definition, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, customerror.NewFailedToError("open definition", customerror.WithError(err))
+	}
+
+	defer f.Close()
+
+	return Parse(f)
+}
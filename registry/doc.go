@@ -0,0 +1,13 @@
+// Package registry builds pipelines from declarative definitions.
+//
+// A `Registry` holds named factories of processors, converters and loaders.
+// A `Definition`, parsed from YAML or JSON, describes a pipeline: its stages,
+// each with a converter and ordered processors, their async and disabled
+// flags, and their options, handed as they are to the factories, plus an
+// optional loader, as the extract step. Reordering, or disabling processors
+// is then a matter of editing the definition, not code.
+//
+// Definitions are validated against the registry when built: unknown
+// component types, duplicated stage names, and unknown or cyclic stage
+// dependencies are all reported at once, with their path in the definition.
+package registry
@@ -0,0 +1,403 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/etler/v3/pipeline"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/stage"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// ProcessorFactory returns a new processor named `name`, configured by
+// `options`.
+type ProcessorFactory[ProcessedData any] func(
+	name string,
+	options Options,
+) (processor.IProcessor[ProcessedData], error)
+
+// ConverterFactory returns a new converter named `name`, configured by
+// `options`.
+type ConverterFactory[ProcessedData, ConvertedOut any] func(
+	name string,
+	options Options,
+) (converter.IConverter[ProcessedData, ConvertedOut], error)
+
+// LoaderFactory returns a new loader named `name`, configured by `options`.
+// Loaders read the source a pipeline is built with, e.g., a file.
+type LoaderFactory[ProcessedData any] func(
+	name string,
+	options Options,
+) (loader.ILoader[io.Reader, []ProcessedData], error)
+
+// Registry holds the named factories definitions are built with, for
+// pipelines of `ProcessedData`, converted into `ConvertedOut`.
+type Registry[ProcessedData, ConvertedOut any] struct {
+	mu sync.RWMutex
+
+	processors map[string]ProcessorFactory[ProcessedData]
+	converters map[string]ConverterFactory[ProcessedData, ConvertedOut]
+	loaders    map[string]LoaderFactory[ProcessedData]
+}
+
+//////
+// Helpers.
+//////
+
+// register registers `factory` as `name` in `factories`.
+func register[F any](mu *sync.RWMutex, factories map[string]F, kind, name string, factory F) error {
+	if name == "" {
+		return customerror.NewRequiredError(kind + " type")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := factories[name]; ok {
+		return customerror.NewInvalidError(fmt.Sprintf("%s type %q, already registered", kind, name))
+	}
+
+	factories[name] = factory
+
+	return nil
+}
+
+// lookup returns the factory registered as `name` in `factories`.
+func lookup[F any](mu *sync.RWMutex, factories map[string]F, kind, name string) (F, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	factory, ok := factories[name]
+	if !ok {
+		return factory, customerror.NewNotFoundError(fmt.Sprintf("%s type %q", kind, name))
+	}
+
+	return factory, nil
+}
+
+// names returns the names registered in `factories`, sorted.
+func names[F any](mu *sync.RWMutex, factories map[string]F) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	out := make([]string, 0, len(factories))
+
+	for name := range factories {
+		out = append(out, name)
+	}
+
+	slices.Sort(out)
+
+	return out
+}
+
+// cyclic returns the name of a stage depending on itself, through
+// `dependsOn`, if any.
+func cyclic(dependsOn map[string][]string) string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := make(map[string]int, len(dependsOn))
+
+	var visit func(name string) bool
+
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+
+		state[name] = visiting
+
+		for _, upstream := range dependsOn[name] {
+			if visit(upstream) {
+				return true
+			}
+		}
+
+		state[name] = visited
+
+		return false
+	}
+
+	stageNames := make([]string, 0, len(dependsOn))
+
+	for name := range dependsOn {
+		stageNames = append(stageNames, name)
+	}
+
+	slices.Sort(stageNames)
+
+	for _, name := range stageNames {
+		if visit(name) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+//////
+// Methods.
+//////
+
+// RegisterProcessor registers a processor factory as `name`.
+func (r *Registry[ProcessedData, ConvertedOut]) RegisterProcessor(
+	name string,
+	factory ProcessorFactory[ProcessedData],
+) error {
+	return register(&r.mu, r.processors, "processor", name, factory)
+}
+
+// RegisterConverter registers a converter factory as `name`.
+func (r *Registry[ProcessedData, ConvertedOut]) RegisterConverter(
+	name string,
+	factory ConverterFactory[ProcessedData, ConvertedOut],
+) error {
+	return register(&r.mu, r.converters, "converter", name, factory)
+}
+
+// RegisterLoader registers a loader factory as `name`.
+func (r *Registry[ProcessedData, ConvertedOut]) RegisterLoader(
+	name string,
+	factory LoaderFactory[ProcessedData],
+) error {
+	return register(&r.mu, r.loaders, "loader", name, factory)
+}
+
+// Processors returns the registered processor types, sorted.
+func (r *Registry[ProcessedData, ConvertedOut]) Processors() []string {
+	return names(&r.mu, r.processors)
+}
+
+// Converters returns the registered converter types, sorted.
+func (r *Registry[ProcessedData, ConvertedOut]) Converters() []string {
+	return names(&r.mu, r.converters)
+}
+
+// Loaders returns the registered loader types, sorted.
+func (r *Registry[ProcessedData, ConvertedOut]) Loaders() []string {
+	return names(&r.mu, r.loaders)
+}
+
+// Validate validates `def` against the registry, without building anything.
+// Every problem is reported, with its path in the definition.
+func (r *Registry[ProcessedData, ConvertedOut]) Validate(def *Definition) error {
+	if def == nil {
+		return customerror.NewRequiredError("definition")
+	}
+
+	if err := validation.Validate(def); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	if def.Loader != nil {
+		if _, err := lookup(&r.mu, r.loaders, "loader", def.Loader.Type); err != nil {
+			errs = append(errs, fmt.Errorf("loader: %w", err))
+		}
+	}
+
+	stageNames := make(map[string]bool, len(def.Stages))
+	dependsOn := make(map[string][]string)
+
+	for i, s := range def.Stages {
+		if stageNames[s.Name] {
+			errs = append(errs, fmt.Errorf("stages[%d]: duplicated stage name %q", i, s.Name))
+		}
+
+		stageNames[s.Name] = true
+
+		if len(s.DependsOn) > 0 {
+			dependsOn[s.Name] = s.DependsOn
+		}
+
+		if _, err := lookup(&r.mu, r.converters, "converter", s.Converter.Type); err != nil {
+			errs = append(errs, fmt.Errorf("stages[%d].converter: %w", i, err))
+		}
+
+		if s.ErrorPolicy != nil {
+			if err := validation.Validate(s.ErrorPolicy); err != nil {
+				errs = append(errs, fmt.Errorf("stages[%d].errorPolicy: %w", i, err))
+			}
+		}
+
+		if s.Concurrency != nil {
+			if err := validation.Validate(s.Concurrency); err != nil {
+				errs = append(errs, fmt.Errorf("stages[%d].concurrency: %w", i, err))
+			}
+		}
+
+		enabled := 0
+
+		for j, p := range s.Processors {
+			if _, err := lookup(&r.mu, r.processors, "processor", p.Type); err != nil {
+				errs = append(errs, fmt.Errorf("stages[%d].processors[%d]: %w", i, j, err))
+			}
+
+			if !p.Disabled {
+				enabled++
+			}
+		}
+
+		// NOTE: Stages require at least a processor.
+		if enabled == 0 {
+			errs = append(errs, fmt.Errorf("stages[%d]: no enabled processor", i))
+		}
+	}
+
+	for i, s := range def.Stages {
+		for _, upstream := range s.DependsOn {
+			if !stageNames[upstream] {
+				errs = append(errs, fmt.Errorf("stages[%d].dependsOn: unknown stage %q", i, upstream))
+			}
+		}
+	}
+
+	if name := cyclic(dependsOn); name != "" {
+		errs = append(errs, fmt.Errorf("stage %q: cyclic dependencies", name))
+	}
+
+	if len(errs) > 0 {
+		return customerror.NewInvalidError("definition", customerror.WithError(errors.Join(errs...)))
+	}
+
+	return nil
+}
+
+// Build validates `def` against the registry, and builds its pipeline. If
+// `def` has a loader, it's the pipeline's extract step: every run opens its
+// source with `open`, and loads it. See `pipeline.WithLoaderSource`.
+func (r *Registry[ProcessedData, ConvertedOut]) Build(
+	def *Definition,
+	open pipeline.Source[io.Reader],
+) (pipeline.IPipeline[ProcessedData, ConvertedOut], error) {
+	if err := r.Validate(def); err != nil {
+		return nil, err
+	}
+
+	stages := make([]stage.IStage[ProcessedData, ConvertedOut], 0, len(def.Stages))
+
+	for i, s := range def.Stages {
+		stg, err := r.buildStage(s)
+		if err != nil {
+			return nil, fmt.Errorf("stages[%d]: %w", i, err)
+		}
+
+		stages = append(stages, stg)
+	}
+
+	p, err := pipeline.New(def.Name, def.Description, def.ConcurrentStage, stages...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range def.Stages {
+		if len(s.DependsOn) > 0 {
+			pipeline.WithDependencies[ProcessedData, ConvertedOut](s.Name, s.DependsOn...)(p)
+		}
+	}
+
+	if def.Loader != nil {
+		if open == nil {
+			return nil, customerror.NewRequiredError("source, for the loader")
+		}
+
+		factory, err := lookup(&r.mu, r.loaders, "loader", def.Loader.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		l, err := factory(ComponentName(*def.Loader), def.Loader.Options)
+		if err != nil {
+			return nil, fmt.Errorf("loader: %w", err)
+		}
+
+		pipeline.WithLoaderSource[io.Reader, ProcessedData, ConvertedOut](l, open)(p)
+	}
+
+	return p, nil
+}
+
+// buildStage builds a stage of a definition, already validated.
+func (r *Registry[ProcessedData, ConvertedOut]) buildStage(
+	s StageDefinition,
+) (stage.IStage[ProcessedData, ConvertedOut], error) {
+	converterFactory, err := lookup(&r.mu, r.converters, "converter", s.Converter.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	conv, err := converterFactory(ComponentName(s.Converter), s.Converter.Options)
+	if err != nil {
+		return nil, fmt.Errorf("converter: %w", err)
+	}
+
+	processors := make([]processor.IProcessor[ProcessedData], 0, len(s.Processors))
+
+	for j, p := range s.Processors {
+		if p.Disabled {
+			continue
+		}
+
+		processorFactory, err := lookup(&r.mu, r.processors, "processor", p.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		proc, err := processorFactory(ComponentName(p.Component), p.Options)
+		if err != nil {
+			return nil, fmt.Errorf("processors[%d]: %w", j, err)
+		}
+
+		if p.Async {
+			proc.SetAsync(true)
+		}
+
+		processors = append(processors, proc)
+	}
+
+	stg, err := stage.New(s.Name, s.Description, conv, processors...)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ErrorPolicy != nil {
+		stg.SetErrorPolicy(*s.ErrorPolicy)
+	}
+
+	if s.Concurrency != nil {
+		stg.SetConcurrency(*s.Concurrency)
+	}
+
+	return stg, nil
+}
+
+//////
+// Factory.
+//////
+
+// New returns a new, empty registry.
+func New[ProcessedData, ConvertedOut any]() *Registry[ProcessedData, ConvertedOut] {
+	return &Registry[ProcessedData, ConvertedOut]{
+		processors: make(map[string]ProcessorFactory[ProcessedData]),
+		converters: make(map[string]ConverterFactory[ProcessedData, ConvertedOut]),
+		loaders:    make(map[string]LoaderFactory[ProcessedData]),
+	}
+}
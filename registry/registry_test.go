@@ -0,0 +1,369 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/converters/passthru"
+	"github.com/thalesfsp/etler/v3/loader"
+	"github.com/thalesfsp/etler/v3/loaders/json"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/stage"
+)
+
+type testUser struct {
+	Name string `json:"name"`
+}
+
+type suffixOptions struct {
+	Suffix string `json:"suffix"`
+}
+
+// newTestRegistry returns a registry with a `suffix` processor, a `passthru`
+// converter, and a `json` loader.
+func newTestRegistry(t *testing.T) *Registry[testUser, testUser] {
+	t.Helper()
+
+	r := New[testUser, testUser]()
+
+	assert.NoError(t, r.RegisterProcessor("suffix", func(name string, options Options) (processor.IProcessor[testUser], error) {
+		var o suffixOptions
+
+		if err := Decode(options, &o); err != nil {
+			return nil, err
+		}
+
+		return processor.New(name, "appends a suffix", func(ctx context.Context, in []testUser) ([]testUser, error) {
+			out := make([]testUser, 0, len(in))
+
+			for _, u := range in {
+				out = append(out, testUser{Name: u.Name + o.Suffix})
+			}
+
+			return out, nil
+		})
+	}))
+
+	assert.NoError(t, r.RegisterConverter("passthru", func(name string, options Options) (converter.IConverter[testUser, testUser], error) {
+		return passthru.New[testUser]()
+	}))
+
+	assert.NoError(t, r.RegisterLoader("json", func(name string, options Options) (loader.ILoader[io.Reader, []testUser], error) {
+		return json.New[testUser]()
+	}))
+
+	return r
+}
+
+func TestParse(t *testing.T) {
+	yamlDef := `
+name: users
+loader:
+  type: json
+stages:
+  - name: enrich
+    converter:
+      type: passthru
+    processors:
+      - type: suffix
+        options:
+          suffix: "-a"
+      - type: suffix
+        name: async-b
+        async: true
+        options:
+          suffix: "-b"
+    errorPolicy:
+      mode: skipAndCollect
+    concurrency:
+      workers: 4
+      rateLimit: 100
+  - name: load
+    dependsOn: [enrich]
+    converter:
+      type: passthru
+    processors:
+      - type: suffix
+`
+
+	jsonDef := `{
+  "name": "users",
+  "loader": {"type": "json"},
+  "stages": [
+    {
+      "name": "enrich",
+      "converter": {"type": "passthru"},
+      "processors": [
+        {"type": "suffix", "options": {"suffix": "-a"}},
+        {"type": "suffix", "name": "async-b", "async": true, "options": {"suffix": "-b"}}
+      ],
+      "errorPolicy": {"mode": "skipAndCollect"},
+      "concurrency": {"workers": 4, "rateLimit": 100}
+    },
+    {
+      "name": "load",
+      "dependsOn": ["enrich"],
+      "converter": {"type": "passthru"},
+      "processors": [{"type": "suffix"}]
+    }
+  ]
+}`
+
+	// Happy path: YAML and JSON parse into the same definition.
+	fromYAML, err := Parse(strings.NewReader(yamlDef))
+	assert.NoError(t, err)
+
+	fromJSON, err := Parse(strings.NewReader(jsonDef))
+	assert.NoError(t, err)
+
+	assert.Equal(t, fromYAML, fromJSON)
+	assert.Equal(t, "users", fromYAML.Name)
+	assert.Equal(t, "json", fromYAML.Loader.Type)
+	assert.Len(t, fromYAML.Stages, 2)
+	assert.Equal(t, "async-b", fromYAML.Stages[0].Processors[1].Name)
+	assert.True(t, fromYAML.Stages[0].Processors[1].Async)
+	assert.Equal(t, Options{"suffix": "-a"}, fromYAML.Stages[0].Processors[0].Options)
+	assert.Equal(t, stage.SkipAndCollect, fromYAML.Stages[0].ErrorPolicy.Mode)
+	assert.Equal(t, &stage.Concurrency{Workers: 4, RateLimit: 100}, fromYAML.Stages[0].Concurrency)
+	assert.Equal(t, []string{"enrich"}, fromYAML.Stages[1].DependsOn)
+
+	// Bad path: unknown fields are rejected.
+	_, err = Parse(strings.NewReader("name: users\nstagez: []\n"))
+	assert.ErrorContains(t, err, "stagez")
+
+	// Bad path: invalid YAML.
+	_, err = Parse(strings.NewReader("name: [users"))
+	assert.ErrorContains(t, err, "parse definition")
+
+	// Edge case: empty definition.
+	_, err = Parse(strings.NewReader(""))
+	assert.ErrorContains(t, err, "missing")
+
+	// Bad path: missing file.
+	_, err = ParseFile("testdata/missing.yaml")
+	assert.ErrorContains(t, err, "open definition")
+}
+
+func TestDecode(t *testing.T) {
+	// Happy path.
+	var o suffixOptions
+
+	assert.NoError(t, Decode(Options{"suffix": "-a"}, &o))
+	assert.Equal(t, "-a", o.Suffix)
+
+	// Edge case: no options.
+	o = suffixOptions{}
+
+	assert.NoError(t, Decode(nil, &o))
+	assert.Empty(t, o.Suffix)
+
+	// Bad path: unknown option.
+	assert.ErrorContains(t, Decode(Options{"prefix": "-a"}, &o), "prefix")
+
+	// Bad path: wrong type.
+	assert.ErrorContains(t, Decode(Options{"suffix": 1}, &o), "decode options")
+}
+
+func TestComponentName(t *testing.T) {
+	assert.Equal(t, "users", ComponentName(Component{Name: "users", Type: "json"}))
+
+	// Edge case: defaults to the type.
+	assert.Equal(t, "json", ComponentName(Component{Type: "json"}))
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := newTestRegistry(t)
+
+	// Happy path.
+	assert.Equal(t, []string{"suffix"}, r.Processors())
+	assert.Equal(t, []string{"passthru"}, r.Converters())
+	assert.Equal(t, []string{"json"}, r.Loaders())
+
+	// Bad path: already registered.
+	err := r.RegisterConverter("passthru", func(name string, options Options) (converter.IConverter[testUser, testUser], error) {
+		return passthru.New[testUser]()
+	})
+	assert.ErrorContains(t, err, "already registered")
+
+	// Bad path: no type.
+	err = r.RegisterLoader("", func(name string, options Options) (loader.ILoader[io.Reader, []testUser], error) {
+		return json.New[testUser]()
+	})
+	assert.ErrorContains(t, err, "loader type")
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	r := newTestRegistry(t)
+
+	// Bad path: every problem is reported, with its path.
+	def, err := Parse(strings.NewReader(`
+name: users
+loader:
+  type: xml
+stages:
+  - name: a
+    converter:
+      type: avro
+    processors:
+      - type: suffix
+      - type: upper
+  - name: a
+    dependsOn: [c]
+    converter:
+      type: passthru
+    processors:
+      - type: suffix
+        disabled: true
+  - name: b
+    dependsOn: [b]
+    converter:
+      type: passthru
+    processors:
+      - type: suffix
+`))
+	assert.NoError(t, err)
+
+	err = r.Validate(def)
+	assert.Error(t, err)
+
+	for _, msg := range []string{
+		`loader: loader type "xml"`,
+		`stages[0].converter: converter type "avro"`,
+		`stages[0].processors[1]: processor type "upper"`,
+		`stages[1]: duplicated stage name "a"`,
+		`stages[1]: no enabled processor`,
+		`stages[1].dependsOn: unknown stage "c"`,
+		`stage "b": cyclic dependencies`,
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+
+	// Bad path: invalid definition.
+	assert.Error(t, r.Validate(&Definition{Name: "users"}))
+
+	def.Stages = def.Stages[2:]
+	def.Stages[0].DependsOn = nil
+	def.Stages[0].ErrorPolicy = &stage.ErrorPolicy{MaxErrorRatio: 2}
+	def.Loader = nil
+
+	assert.ErrorContains(t, r.Validate(def), "stages[0].errorPolicy")
+	assert.ErrorContains(t, r.Validate(def), "MaxErrorRatio")
+
+	// Bad path: unknown error policy mode.
+	def.Stages[0].ErrorPolicy = &stage.ErrorPolicy{Mode: "bogus"}
+
+	assert.ErrorContains(t, r.Validate(def), "stages[0].errorPolicy")
+
+	def.Stages[0].ErrorPolicy = nil
+
+	// Bad path: invalid concurrencies.
+	for _, concurrency := range []stage.Concurrency{
+		{Workers: -1},
+		{RateLimit: -1},
+		{RateLimit: stage.MinRateLimit / 2},
+	} {
+		def.Stages[0].Concurrency = &concurrency
+
+		assert.ErrorContains(t, r.Validate(def), "stages[0].concurrency")
+	}
+
+	// Bad path: every problem is reported.
+	def.Stages[0].ErrorPolicy = &stage.ErrorPolicy{Mode: "bogus"}
+
+	err = r.Validate(def)
+	assert.ErrorContains(t, err, "stages[0].errorPolicy")
+	assert.ErrorContains(t, err, "stages[0].concurrency")
+
+	def.Stages[0].ErrorPolicy = nil
+	def.Stages[0].Concurrency = &stage.Concurrency{Workers: 2, RateLimit: 10}
+
+	assert.NoError(t, r.Validate(def))
+
+	// Bad path: no definition.
+	assert.ErrorContains(t, r.Validate(nil), "definition")
+}
+
+func TestRegistry_Build(t *testing.T) {
+	r := newTestRegistry(t)
+
+	def, err := Parse(strings.NewReader(`
+name: users
+loader:
+  type: json
+stages:
+  - name: enrich
+    converter:
+      type: passthru
+    processors:
+      - type: suffix
+        options:
+          suffix: "-b"
+      - type: suffix
+        disabled: true
+        options:
+          suffix: "-x"
+      - type: suffix
+        name: async-a
+        async: true
+        options:
+          suffix: "-a"
+    errorPolicy:
+      mode: skipAndCollect
+  - name: load
+    dependsOn: [enrich]
+    converter:
+      type: passthru
+    processors:
+      - type: suffix
+        options:
+          suffix: "-c"
+`))
+	assert.NoError(t, err)
+
+	// Bad path: the loader requires a source.
+	_, err = r.Build(def, nil)
+	assert.ErrorContains(t, err, "source")
+
+	// Happy path.
+	p, err := r.Build(def, func(ctx context.Context) (io.Reader, error) {
+		return strings.NewReader(`[{"name": "john"}]`), nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{"load": {"enrich"}}, p.GetDependencies())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Every run loads its own source: the built pipeline is reusable.
+	for range 2 {
+		tasks, err := p.Run(ctx, nil)
+		assert.NoError(t, err)
+
+		var names []string
+
+		for _, tsk := range tasks {
+			for _, u := range tsk.ConvertedData {
+				names = append(names, u.Name)
+			}
+		}
+
+		// Processors run in order, disabled ones are skipped, and async
+		// ones don't forward their output.
+		assert.Contains(t, names, "john-b-c")
+		assert.NotContains(t, strings.Join(names, ","), "-x")
+		assert.NotContains(t, strings.Join(names, ","), "-a")
+	}
+
+	// Bad path: a factory fails.
+	def.Stages[0].Processors[0].Options = Options{"prefix": "-b"}
+
+	_, err = r.Build(def, func(ctx context.Context) (io.Reader, error) {
+		return strings.NewReader(`[]`), nil
+	})
+	assert.ErrorContains(t, err, "stages[0]: processors[0]")
+}
@@ -0,0 +1,8 @@
+// Package report records the report of a pipeline run: its status, task, and
+// the timings, item counts, and errors of its stages, and processors.
+//
+// Components' metrics aggregate all runs, and concurrent runs of the same
+// pipeline overwrite each other's progress, status, and duration. A report
+// is isolated: `pipeline.RunWithReport` sets a `Recorder` in the run's
+// context, where stages, and processors, record themselves.
+package report
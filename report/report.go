@@ -0,0 +1,326 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/status"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Processor is the report of a processor run, within a stage.
+type Processor struct {
+	// Name of the processor.
+	Name string `json:"name"`
+
+	// Async is whether the processor ran asynchronously.
+	Async bool `json:"async,omitempty"`
+
+	// Status of the run: done, failed, or interrupted.
+	Status string `json:"status"`
+
+	// Attempts made, more than one if retried.
+	Attempts int `json:"attempts"`
+
+	// Duration of the run, including retries.
+	Duration time.Duration `json:"duration"`
+
+	// ItemsIn is the count of items received.
+	ItemsIn int `json:"itemsIn"`
+
+	// ItemsOut is the count of items returned.
+	ItemsOut int `json:"itemsOut"`
+
+	// Err is the error of a failed run.
+	Err error `json:"-"`
+
+	// Error is the message of `Err`, kept for serialization.
+	Error string `json:"error,omitempty"`
+}
+
+// Stage is the report of a stage run.
+type Stage struct {
+	// Name of the stage.
+	Name string `json:"name"`
+
+	// TaskID is the ID of the task the stage ran.
+	TaskID string `json:"taskID"`
+
+	// Status of the run: running, done, failed, or interrupted.
+	Status string `json:"status"`
+
+	// StartedAt is when the stage started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// Duration of the run.
+	Duration time.Duration `json:"duration"`
+
+	// ItemsIn is the count of items received.
+	ItemsIn int `json:"itemsIn"`
+
+	// ItemsOut is the count of items converted.
+	ItemsOut int `json:"itemsOut"`
+
+	// DeadLetters is the count of items dropped by the stage.
+	DeadLetters int `json:"deadLetters"`
+
+	// Processors that ran, in completion order.
+	Processors []Processor `json:"processors"`
+
+	// Err is the error of a failed run.
+	Err error `json:"-"`
+
+	// Error is the message of `Err`, kept for serialization.
+	Error string `json:"error,omitempty"`
+}
+
+// Run is the report of a pipeline run. Unlike components' metrics, which
+// aggregate all runs, it's isolated from concurrent runs.
+type Run struct {
+	// RunID is the ID of the run.
+	RunID string `json:"runID"`
+
+	// Pipeline is the name of the pipeline.
+	Pipeline string `json:"pipeline"`
+
+	// TaskID is the ID of the task the pipeline ran.
+	TaskID string `json:"taskID,omitempty"`
+
+	// Status of the run: running, paused, done, failed, or interrupted.
+	Status string `json:"status"`
+
+	// StartedAt is when the run started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// Duration of the run, so far if still running.
+	Duration time.Duration `json:"duration"`
+
+	// ItemsIn is the count of items the pipeline ran over.
+	ItemsIn int `json:"itemsIn"`
+
+	// Stages that ran, in start order.
+	Stages []Stage `json:"stages"`
+
+	// Err is the error of a failed run.
+	Err error `json:"-"`
+
+	// Error is the message of `Err`, kept for serialization.
+	Error string `json:"error,omitempty"`
+}
+
+// Recorder records the report of a run. It's safe for concurrent use, and
+// nil-safe: without a recorder, nothing is recorded.
+type Recorder struct {
+	mu  sync.Mutex
+	run Run
+}
+
+// StageRecorder records the report of a stage run.
+type StageRecorder struct {
+	r     *Recorder
+	index int
+}
+
+type (
+	recorderCtxKey      struct{}
+	stageRecorderCtxKey struct{}
+)
+
+//////
+// Helpers.
+//////
+
+// outcome returns the status, and error message, of a run failed with `err`,
+// if not nil, or interrupted by a drain.
+func outcome(err error) (string, string) {
+	if errors.Is(err, shared.ErrInterrupted) {
+		return status.Interrupted.String(), err.Error()
+	}
+
+	if err != nil {
+		return status.Failed.String(), err.Error()
+	}
+
+	return status.Done.String(), ""
+}
+
+//////
+// Methods.
+//////
+
+// SetTask records the task the run is over.
+func (r *Recorder) SetTask(taskID string, itemsIn int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.run.TaskID = taskID
+	r.run.ItemsIn = itemsIn
+}
+
+// SetStatus records the status of the run in progress, e.g., paused.
+func (r *Recorder) SetStatus(s status.Status) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.run.Status = s.String()
+}
+
+// StartStage records the start of the stage named `name`.
+func (r *Recorder) StartStage(name, taskID string, itemsIn int) *StageRecorder {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.run.Stages = append(r.run.Stages, Stage{
+		Name:       name,
+		TaskID:     taskID,
+		Status:     status.Runnning.String(),
+		StartedAt:  time.Now(),
+		ItemsIn:    itemsIn,
+		Processors: []Processor{},
+	})
+
+	return &StageRecorder{r: r, index: len(r.run.Stages) - 1}
+}
+
+// Finish records the end of the run, failed if `err` isn't nil, and returns
+// its report.
+func (r *Recorder) Finish(err error) *Run {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+
+	r.run.Status, r.run.Error = outcome(err)
+	r.run.Err = err
+	r.run.Duration = time.Since(r.run.StartedAt)
+
+	r.mu.Unlock()
+
+	return r.Report()
+}
+
+// Report returns a copy of the report, as recorded so far.
+func (r *Recorder) Report() *Run {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run := r.run
+
+	if run.Status == status.Runnning.String() || run.Status == status.Paused.String() {
+		run.Duration = time.Since(run.StartedAt)
+	}
+
+	run.Stages = slices.Clone(run.Stages)
+
+	for i := range run.Stages {
+		run.Stages[i].Processors = slices.Clone(run.Stages[i].Processors)
+	}
+
+	return &run
+}
+
+// RecordProcessor records the run of a processor of the stage.
+func (sr *StageRecorder) RecordProcessor(p Processor) {
+	if sr == nil {
+		return
+	}
+
+	if p.Err != nil {
+		p.Error = p.Err.Error()
+	}
+
+	sr.r.mu.Lock()
+	defer sr.r.mu.Unlock()
+
+	s := &sr.r.run.Stages[sr.index]
+
+	s.Processors = append(s.Processors, p)
+}
+
+// Finish records the end of the stage run, failed if `err` isn't nil.
+func (sr *StageRecorder) Finish(itemsOut, deadLetters int, err error) {
+	if sr == nil {
+		return
+	}
+
+	sr.r.mu.Lock()
+	defer sr.r.mu.Unlock()
+
+	s := &sr.r.run.Stages[sr.index]
+
+	s.Status, s.Error = outcome(err)
+	s.Err = err
+	s.Duration = time.Since(s.StartedAt)
+	s.ItemsOut = itemsOut
+	s.DeadLetters = deadLetters
+}
+
+//////
+// Exported functions.
+//////
+
+// ContextWithRecorder returns a copy of `ctx` carrying `r`.
+func ContextWithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderCtxKey{}, r)
+}
+
+// FromContext returns the recorder carried by `ctx`, or nil.
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderCtxKey{}).(*Recorder)
+
+	return r
+}
+
+// ContextWithStage returns a copy of `ctx` carrying `sr`.
+func ContextWithStage(ctx context.Context, sr *StageRecorder) context.Context {
+	return context.WithValue(ctx, stageRecorderCtxKey{}, sr)
+}
+
+// StageFromContext returns the stage recorder carried by `ctx`, or nil.
+func StageFromContext(ctx context.Context) *StageRecorder {
+	sr, _ := ctx.Value(stageRecorderCtxKey{}).(*StageRecorder)
+
+	return sr
+}
+
+//////
+// Factory.
+//////
+
+// New returns a recorder for the run `runID` of the pipeline named
+// `pipeline`, started now.
+func New(runID, pipeline string) *Recorder {
+	return &Recorder{
+		run: Run{
+			RunID:     runID,
+			Pipeline:  pipeline,
+			Status:    status.Runnning.String(),
+			StartedAt: time.Now(),
+			Stages:    []Stage{},
+		},
+	}
+}
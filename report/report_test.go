@@ -0,0 +1,75 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/status"
+)
+
+func TestRecorder(t *testing.T) {
+	r := New("run-1", "pipeline")
+
+	ctx := ContextWithRecorder(context.Background(), r)
+	require.Same(t, r, FromContext(ctx))
+
+	r.SetTask("task-1", 3)
+
+	sr := FromContext(ctx).StartStage("stage", "task-1", 3)
+
+	ctx = ContextWithStage(ctx, sr)
+	require.Same(t, sr, StageFromContext(ctx))
+
+	StageFromContext(ctx).RecordProcessor(Processor{Name: "ok", Status: status.Done.String(), ItemsIn: 3, ItemsOut: 2})
+	StageFromContext(ctx).RecordProcessor(Processor{Name: "ko", Status: status.Failed.String(), Err: errors.New("boom")})
+
+	// Happy path: a running report.
+	running := r.Report()
+	assert.Equal(t, status.Runnning.String(), running.Status)
+	assert.Equal(t, status.Runnning.String(), running.Stages[0].Status)
+
+	sr.Finish(2, 1, errors.New("boom"))
+
+	run := r.Finish(nil)
+
+	assert.Equal(t, "run-1", run.RunID)
+	assert.Equal(t, "task-1", run.TaskID)
+	assert.Equal(t, status.Done.String(), run.Status)
+	assert.Empty(t, run.Error)
+
+	require.Len(t, run.Stages, 1)
+
+	s := run.Stages[0]
+
+	assert.Equal(t, status.Failed.String(), s.Status)
+	assert.Equal(t, "boom", s.Error)
+	assert.Equal(t, 2, s.ItemsOut)
+	assert.Equal(t, 1, s.DeadLetters)
+	assert.Equal(t, "boom", s.Processors[1].Error)
+
+	// Edge case: reports are copies.
+	run.Stages[0].Processors[0].Name = "changed"
+	assert.Equal(t, "ok", r.Report().Stages[0].Processors[0].Name)
+	assert.Len(t, running.Stages[0].Processors, 2)
+}
+
+// Edge case: without a recorder, nothing is recorded, nor panics.
+func TestRecorder_nil(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NotPanics(t, func() {
+		FromContext(ctx).SetTask("task", 1)
+
+		sr := FromContext(ctx).StartStage("stage", "task", 1)
+		sr.RecordProcessor(Processor{})
+		sr.Finish(1, 0, nil)
+
+		StageFromContext(ctx).RecordProcessor(Processor{})
+	})
+
+	assert.Nil(t, FromContext(ctx).Finish(nil))
+	assert.Nil(t, FromContext(ctx).Report())
+}
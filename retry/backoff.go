@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Backoff returns how long to wait before the next attempt, given the number
+// of the attempt which just failed (starting at 1).
+type Backoff func(attempt int) time.Duration
+
+//////
+// Built-in backoffs.
+//////
+
+// Constant waits `delay` between attempts.
+func Constant(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// Exponential waits `initial`, then doubles the delay after every attempt,
+// up to `maxDelay`. A non-positive `maxDelay` means no cap.
+func Exponential(initial, maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := float64(initial) * math.Pow(2, float64(attempt-1))
+
+		if maxDelay > 0 && delay > float64(maxDelay) {
+			return maxDelay
+		}
+
+		// Overflow guard.
+		//
+		// NOTE: `float64(math.MaxInt64)` is 2^63, out of range of int64.
+		if delay >= math.MaxInt64 {
+			return time.Duration(math.MaxInt64)
+		}
+
+		return time.Duration(delay)
+	}
+}
+
+// Jitter randomizes `backoff` ("full jitter"): it waits a random duration
+// between zero and what `backoff` returns. It spreads retries of concurrent
+// callers, avoiding them to hit a recovering dependency all at once.
+func Jitter(backoff Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		delay := backoff(attempt)
+
+		if delay <= 0 {
+			return 0
+		}
+
+		// NOTE: Not security-sensitive. `delay + 1` would overflow, but
+		// `rand.Int64` already is in [0, math.MaxInt64].
+		if delay == math.MaxInt64 {
+			return time.Duration(rand.Int64()) //nolint:gosec
+		}
+
+		return time.Duration(rand.Int64N(int64(delay) + 1)) //nolint:gosec
+	}
+}
@@ -0,0 +1,12 @@
+// Package retry provides retry policies, with backoff, for processors,
+// converters and loaders.
+//
+// A `Policy` defines the maximum number of attempts, the delay between them
+// (`Constant`, `Exponential`, optionally wrapped with `Jitter`), and which
+// errors are worth retrying (`Classifier`). Retries stop as soon as the
+// context is done.
+//
+// Components take a policy via their `WithRetry` option. Each attempt is a
+// full run of the component: it's traced as its own span, and counted in the
+// component's metrics.
+package retry
@@ -0,0 +1,147 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// Classifier returns whether `err` is worth retrying.
+type Classifier func(err error) bool
+
+// Func allows to specify policy's options.
+type Func func(p *Policy) *Policy
+
+// Policy defines how an operation is retried.
+type Policy struct {
+	// Backoff is the delay between attempts.
+	Backoff Backoff `json:"-" validate:"required"`
+
+	// Classifier returns whether an error is worth retrying.
+	Classifier Classifier `json:"-" validate:"required"`
+
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int `json:"maxAttempts" validate:"gte=1"`
+}
+
+//////
+// Built-in classifiers.
+//////
+
+// IsRetryable is the default classifier: every error is worth retrying,
+// except the context being canceled, or its deadline exceeded, and the run
+// being interrupted by a drain.
+func IsRetryable(err error) bool {
+	return !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.Is(err, shared.ErrInterrupted)
+}
+
+//////
+// Built-in options.
+//////
+
+// WithClassifier sets which errors are worth retrying.
+func WithClassifier(classifier Classifier) Func {
+	return func(p *Policy) *Policy {
+		p.Classifier = classifier
+
+		return p
+	}
+}
+
+//////
+// Methods.
+//////
+
+// wait blocks for `delay`, or until the context is done.
+func wait(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Do calls `fn` until it succeeds, it fails with an error not worth
+// retrying, attempts are exhausted, or the context is done. `attempt` starts
+// at 1. It returns the outcome of the last attempt. A nil policy calls `fn`
+// once.
+func Do[T any](
+	ctx context.Context,
+	p *Policy,
+	fn func(ctx context.Context, attempt int) (T, error),
+) (T, error) {
+	if p == nil {
+		return fn(ctx, 1)
+	}
+
+	for attempt := 1; ; attempt++ {
+		out, err := fn(ctx, attempt)
+		if err == nil {
+			return out, nil
+		}
+
+		if attempt >= p.MaxAttempts || !p.Classifier(err) {
+			return out, err
+		}
+
+		// Context done while waiting: the last attempt's error is the most
+		// meaningful one.
+		if wait(ctx, p.Backoff(attempt)) != nil {
+			return out, err
+		}
+	}
+}
+
+//////
+// Factory.
+//////
+
+// New returns a new retry policy, making up to `maxAttempts` attempts, and
+// waiting `backoff` between them. By default, every error is worth
+// retrying, except context ones, see `IsRetryable`.
+func New(maxAttempts int, backoff Backoff, opts ...Func) (*Policy, error) {
+	p := &Policy{
+		Backoff:     backoff,
+		Classifier:  IsRetryable,
+		MaxAttempts: maxAttempts,
+	}
+
+	// Apply options.
+	for _, opt := range opts {
+		p = opt(p)
+	}
+
+	// Validation.
+	if err := validation.Validate(p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Must returns a new retry policy or panics.
+func Must(maxAttempts int, backoff Backoff, opts ...Func) *Policy {
+	p, err := New(maxAttempts, backoff, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
@@ -0,0 +1,158 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffs(t *testing.T) {
+	assert.Equal(t, 5*time.Millisecond, Constant(5*time.Millisecond)(1))
+	assert.Equal(t, 5*time.Millisecond, Constant(5*time.Millisecond)(10))
+
+	exp := Exponential(10*time.Millisecond, 50*time.Millisecond)
+
+	assert.Equal(t, 10*time.Millisecond, exp(1))
+	assert.Equal(t, 20*time.Millisecond, exp(2))
+	assert.Equal(t, 40*time.Millisecond, exp(3))
+	assert.Equal(t, 50*time.Millisecond, exp(4), "capped")
+
+	// Edge case: no cap, no overflow.
+	assert.Equal(t, time.Duration(1<<62), Exponential(1, 0)(63))
+	assert.Positive(t, Exponential(time.Second, 0)(1000))
+	assert.Equal(t, time.Duration(math.MaxInt64), Exponential(1, 0)(64))
+	assert.Equal(t, time.Duration(math.MaxInt64), Exponential(time.Second, 0)(200))
+
+	// Edge case: no cap, jittered, no overflow.
+	for _, attempt := range []int{64, 200, 1000} {
+		assert.GreaterOrEqual(t, Jitter(Exponential(time.Second, 0))(attempt), time.Duration(0))
+	}
+
+	jittered := Jitter(Constant(10 * time.Millisecond))
+
+	for i := 1; i < 100; i++ {
+		d := jittered(i)
+
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, 10*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Duration(0), Jitter(Constant(0))(1))
+}
+
+// Happy path: retried until it succeeds.
+func TestDo_retriesUntilSuccess(t *testing.T) {
+	p := Must(5, Constant(time.Millisecond))
+
+	attempts := []int{}
+
+	out, err := Do(context.Background(), p, func(ctx context.Context, attempt int) (string, error) {
+		attempts = append(attempts, attempt)
+
+		if attempt < 3 {
+			return "", errors.New("transient")
+		}
+
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+// Bad path: attempts are exhausted, the last error is returned.
+func TestDo_exhaustsAttempts(t *testing.T) {
+	p := Must(3, Constant(0))
+
+	calls := 0
+
+	_, err := Do(context.Background(), p, func(ctx context.Context, attempt int) (int, error) {
+		calls++
+
+		return 0, errors.New("attempt failed")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+// Bad path: errors not worth retrying aren't retried.
+func TestDo_classifier(t *testing.T) {
+	permanent := errors.New("permanent")
+
+	p := Must(5, Constant(0), WithClassifier(func(err error) bool {
+		return !errors.Is(err, permanent)
+	}))
+
+	calls := 0
+
+	_, err := Do(context.Background(), p, func(ctx context.Context, attempt int) (int, error) {
+		calls++
+
+		return 0, permanent
+	})
+	require.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, calls)
+
+	// Default classifier: context errors aren't retried.
+	calls = 0
+
+	_, err = Do(context.Background(), Must(5, Constant(0)), func(ctx context.Context, attempt int) (int, error) {
+		calls++
+
+		return 0, context.DeadlineExceeded
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, calls)
+}
+
+// Edge case: a done context stops waiting, and retrying.
+func TestDo_contextDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	p := Must(10, Constant(time.Hour))
+
+	start := time.Now()
+	calls := 0
+
+	_, err := Do(ctx, p, func(ctx context.Context, attempt int) (int, error) {
+		calls++
+
+		return 0, errors.New("transient")
+	})
+	require.EqualError(t, err, "transient")
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+// Edge case: without a policy, a single attempt.
+func TestDo_nilPolicy(t *testing.T) {
+	calls := 0
+
+	_, err := Do(context.Background(), nil, func(ctx context.Context, attempt int) (int, error) {
+		calls++
+
+		return 0, errors.New("failed")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+// Bad path: invalid policies.
+func TestNew_invalid(t *testing.T) {
+	_, err := New(0, Constant(0))
+	assert.Error(t, err)
+
+	_, err = New(1, nil)
+	assert.Error(t, err)
+
+	_, err = New(1, Constant(0), WithClassifier(nil))
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { Must(0, Constant(0)) })
+}
@@ -0,0 +1,164 @@
+package stage
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/thalesfsp/concurrentloop"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// MinRateLimit is the lowest rate limit, in items per second, of a stage:
+// one item every 1000 seconds.
+const MinRateLimit = 0.001
+
+// Concurrency bounds how many items a stage converts at once, and how fast.
+// The zero value converts as many items at once as there are CPUs, as fast
+// as they convert.
+type Concurrency struct {
+	// Workers is the maximum number of items converted at once. Defaults to
+	// the number of CPUs.
+	Workers int `json:"workers,omitempty" validate:"gte=0"`
+
+	// RateLimit is the maximum number of items converted per second, across
+	// workers. Unlimited if zero, else at least `MinRateLimit`.
+	RateLimit float64 `json:"rateLimit,omitempty" validate:"omitempty,gte=0.001"`
+}
+
+// limiter spaces the items it lets through evenly, according to a rate.
+type limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+//////
+// Helpers.
+//////
+
+// workers returns the effective number of workers.
+func (c Concurrency) workers() int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+
+	return runtime.NumCPU()
+}
+
+// wait blocks until the next item is let through, or the context is done.
+// A nil limiter lets every item through right away.
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+
+	now := time.Now()
+
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+
+	l.next = at.Add(l.interval)
+
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// convertFunc returns the conversion of a single item, rate limited, and the
+// options of the conversion loop, bounded in workers, according to the
+// stage's concurrency. The rate limit applies to a single run.
+func (s *Stage[ProcessingData, ConvertedData]) convertFunc() (
+	concurrentloop.MapFunc[ProcessingData, ConvertedData],
+	[]concurrentloop.Func,
+	error,
+) {
+	concurrency := s.GetConcurrency()
+
+	if err := validation.Validate(&concurrency); err != nil {
+		return nil, nil, err
+	}
+
+	// NOTE: WithRemoveZeroValues(false) is required. The default would
+	// silently drop converted items that happen to be the zero value of
+	// `ConvertedData` — data loss.
+	opts := []concurrentloop.Func{
+		concurrentloop.WithRemoveZeroValues(false),
+		concurrentloop.WithBatchSize(concurrency.workers()),
+	}
+
+	if concurrency.RateLimit == 0 {
+		return s.Conversor.Run, opts, nil
+	}
+
+	l := newLimiter(concurrency.RateLimit)
+
+	return func(ctx context.Context, in ProcessingData) (ConvertedData, error) {
+		if err := l.wait(ctx); err != nil {
+			return *new(ConvertedData), err
+		}
+
+		return s.Conversor.Run(ctx, in)
+	}, opts, nil
+}
+
+// convertAll converts every item, failing on the first item failing
+// conversion. It returns the converted items, in input order.
+func (s *Stage[ProcessingData, ConvertedData]) convertAll(
+	ctx context.Context,
+	items []ProcessingData,
+) ([]ConvertedData, error) {
+	convert, opts, err := s.convertFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	convertedData, errs := concurrentloop.Map(ctx, items, convert, opts...)
+
+	// Conversions paused, and stopped because the run drains.
+	if interrupted(errs) {
+		return nil, shared.ErrInterrupted
+	}
+
+	// NOTE: A nil `concurrentloop.Errors` must stay a nil `error`.
+	if errs != nil {
+		return nil, errs
+	}
+
+	return convertedData, nil
+}
+
+//////
+// Factory.
+//////
+
+// newLimiter returns a new limiter, letting `rate` items through per second.
+//
+// NOTE: Rates above a billion items per second would round to no interval,
+// so no limit: the interval is at least 1ns.
+func newLimiter(rate float64) *limiter {
+	return &limiter{interval: max(time.Duration(float64(time.Second)/rate), time.Nanosecond)}
+}
@@ -38,7 +38,7 @@
 //
 // 4. **Observability**: The stage package provides comprehensive observability features, including metrics, logging, and tracing, to monitor and debug the stage's execution.
 //
-// 5. **Metrics**: Stage metrics are tracked with `expvar` variables (registered globally on `/debug/vars` only when `ETLER_METRICS_PUBLISH=true`; unpublished per-instance variables otherwise), allowing for easy integration with monitoring systems. Metrics include counters for created, running, failed, and done stages, as well as duration, progress, and progress percentage.
+// 5. **Metrics**: Stage metrics are tracked with `expvar` variables (registered globally on `/debug/vars` only when `ETLER_METRICS_PUBLISH=true`; unpublished per-instance variables otherwise), allowing for easy integration with monitoring systems. Metrics include counters for created, running, failed, and done stages, items in and out, and items per second, as well as duration, a duration histogram, with percentiles, progress, and progress percentage.
 //
 // 6. **Logging**: The package utilizes the `sypl` library for structured logging, providing rich context and consistent log levels throughout the codebase. Log messages include relevant information such as stage status, counters, duration, and progress.
 //
@@ -58,6 +58,16 @@
 //
 // 14. **Customizable**: The stage package provides a high level of customization through the use of interfaces and generic types. Developers can easily create custom converters and processors to meet their specific data processing requirements.
 //
+// ## Error Policy
+//
+// By default, a single item failing conversion fails the whole stage (`FailFast`). `WithErrorPolicy` (or `SetErrorPolicy`) changes that: with `SkipAndCollect`, failing items are dropped and the stage completes with the others; with `MaxErrorRatio`, the same happens as long as the ratio of failing items doesn't exceed `ErrorPolicy.MaxErrorRatio`, otherwise the stage fails.
+//
+// Dropped items are recorded as `task.DeadLetter` (stage, index, item, and error) on the returned task's `DeadLetters`, accumulated across stages, and counted by the `counterDeadLettered` metric. `WithDeadLetterSink` additionally hands them to a sink, e.g., to persist them for later replay. A failing sink fails the stage, so dropped items are never silently lost.
+//
+// ## Concurrency
+//
+// A stage converts as many items at once as there are CPUs. `WithConcurrency` (or `SetConcurrency`) bounds that with `Concurrency.Workers`, e.g., to spare the storage written to by a storage converter, and optionally limits the items converted per second, across workers, with `Concurrency.RateLimit`. Both are reported by the `workers`, and `rateLimit`, metrics.
+//
 // ## Architectural Modularity and Flexibility
 //
 // The stage package is designed with architectural modularity and flexibility in mind. It leverages Go's interfaces and generic types to provide a highly extensible and customizable stage framework.
@@ -0,0 +1,162 @@
+package stage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/thalesfsp/concurrentloop"
+	"github.com/thalesfsp/customerror/v2"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/validation"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// ErrorMode defines how a stage reacts to items failing conversion.
+type ErrorMode string
+
+const (
+	// FailFast fails the stage on the first item failing conversion. It's
+	// the default.
+	FailFast ErrorMode = "failFast"
+
+	// SkipAndCollect drops the items failing conversion, and collects them
+	// as dead letters. The stage never fails because of them.
+	SkipAndCollect ErrorMode = "skipAndCollect"
+
+	// MaxErrorRatio behaves like `SkipAndCollect` as long as the ratio of
+	// items failing conversion doesn't exceed `ErrorPolicy.MaxErrorRatio`.
+	MaxErrorRatio ErrorMode = "maxErrorRatio"
+)
+
+// ErrorPolicy defines how a stage reacts to items failing conversion.
+type ErrorPolicy struct {
+	// Mode of the policy.
+	Mode ErrorMode `json:"mode" validate:"omitempty,oneof=failFast skipAndCollect maxErrorRatio"`
+
+	// MaxErrorRatio is the maximum ratio (0 to 1) of items that can fail
+	// conversion before the stage fails. Only used by the `MaxErrorRatio`
+	// mode.
+	MaxErrorRatio float64 `json:"maxErrorRatio,omitempty" validate:"gte=0,lte=1"`
+}
+
+// DeadLetterSink receives the items dropped by a stage, once conversion is
+// done. An error fails the stage, so dropped items are never silently lost.
+type DeadLetterSink[ProcessedData any] func(
+	ctx context.Context,
+	deadLetters []task.DeadLetter[ProcessedData],
+) error
+
+// conversion is the outcome of converting a single item.
+type conversion[ConvertedData any] struct {
+	out ConvertedData
+	err error
+}
+
+//////
+// Helpers.
+//////
+
+// tolerates returns whether the policy tolerates `failed` out of `total`
+// items failing conversion.
+func (e ErrorPolicy) tolerates(failed, total int) bool {
+	switch e.Mode {
+	case SkipAndCollect:
+		return true
+	case MaxErrorRatio:
+		return total == 0 || float64(failed)/float64(total) <= e.MaxErrorRatio
+	default:
+		return failed == 0
+	}
+}
+
+// convertIsolated converts every item on its own: a failing item doesn't
+// stop the others. It returns the converted items, in input order, and the
+// dead letters of the failing ones. The returned error is only about the
+// conversion loop itself, e.g., the context being done.
+func (s *Stage[ProcessingData, ConvertedData]) convertIsolated(
+	ctx context.Context,
+	items []ProcessingData,
+) ([]ConvertedData, []task.DeadLetter[ProcessingData], error) {
+	errorPolicy := s.GetErrorPolicy()
+
+	if err := validation.Validate(&errorPolicy); err != nil {
+		return nil, nil, err
+	}
+
+	convert, opts, err := s.convertFunc()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexes := make([]int, len(items))
+
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	// NOTE: Per-item errors are carried in the outcome, so the loop itself
+	// only errors if the context is done.
+	outcomes, errs := concurrentloop.Map(
+		ctx,
+		indexes,
+		func(ctx context.Context, i int) (conversion[ConvertedData], error) {
+			out, err := convert(ctx, items[i])
+
+			return conversion[ConvertedData]{out: out, err: err}, nil
+		},
+		opts...,
+	)
+	if errs != nil {
+		return nil, nil, errs
+	}
+
+	convertedData := make([]ConvertedData, 0, len(items))
+
+	var deadLetters []task.DeadLetter[ProcessingData]
+
+	for i, o := range outcomes {
+		if o.err == nil {
+			convertedData = append(convertedData, o.out)
+
+			continue
+		}
+
+		// Items not converted because the run drains didn't fail.
+		if errors.Is(o.err, shared.ErrInterrupted) {
+			return nil, nil, shared.ErrInterrupted
+		}
+
+		deadLetters = append(deadLetters, task.DeadLetter[ProcessingData]{
+			Stage: s.GetName(),
+			Index: i,
+			Item:  items[i],
+			Err:   o.err,
+			Error: o.err.Error(),
+		})
+	}
+
+	if !errorPolicy.tolerates(len(deadLetters), len(items)) {
+		errs := make([]error, 0, len(deadLetters))
+
+		for _, d := range deadLetters {
+			errs = append(errs, fmt.Errorf("item %d: %w", d.Index, d.Err))
+		}
+
+		return nil, nil, customerror.New(
+			fmt.Sprintf(
+				"%d of %d items failed, above the max error ratio of %v",
+				len(deadLetters),
+				len(items),
+				errorPolicy.MaxErrorRatio,
+			),
+			customerror.WithError(errors.Join(errs...)),
+		)
+	}
+
+	return convertedData, deadLetters, nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"expvar"
 
+	"github.com/thalesfsp/etler/v3/event"
 	"github.com/thalesfsp/etler/v3/internal/shared"
 	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/status"
 )
 
 // IStage defines what a `Stage` must do.
@@ -23,12 +25,52 @@ type IStage[ProcessedData, ConvertedOut any] interface {
 	// SetProgressPercent sets the `ProgressPercent` of the stage.
 	SetProgressPercent()
 
+	// GetPaused returns the Paused status of the stage.
+	GetPaused() status.Status
+
+	// SetPause the stage only.
+	SetPause(state bool)
+
+	// GetEventBus returns the bus the stage publishes its pause events to.
+	GetEventBus() *event.Bus
+
+	// SetEventBus sets the bus the stage publishes its pause events to.
+	SetEventBus(bus *event.Bus, pipeline string)
+
 	// GetOnFinished returns the `OnFinished` function.
 	GetOnFinished() OnFinished[ProcessedData, ConvertedOut]
 
 	// SetOnFinished sets the `OnFinished` function.
 	SetOnFinished(onFinished OnFinished[ProcessedData, ConvertedOut])
 
+	// GetCounterDeadLettered returns the `CounterDeadLettered` of the stage.
+	GetCounterDeadLettered() *expvar.Int
+
+	// GetCounterInterrupted returns the `CounterInterrupted` of the stage.
+	GetCounterInterrupted() *expvar.Int
+
+	// GetErrorPolicy returns the `ErrorPolicy` of the stage.
+	GetErrorPolicy() ErrorPolicy
+
+	// SetErrorPolicy sets the `ErrorPolicy` of the stage.
+	SetErrorPolicy(errorPolicy ErrorPolicy)
+
+	// GetConcurrency returns the `Concurrency` of the stage.
+	GetConcurrency() Concurrency
+
+	// SetConcurrency sets the `Concurrency` of the stage.
+	SetConcurrency(concurrency Concurrency)
+
+	// GetDeadLetterSink returns the `DeadLetterSink` of the stage.
+	GetDeadLetterSink() DeadLetterSink[ProcessedData]
+
+	// SetDeadLetterSink sets the `DeadLetterSink` of the stage.
+	SetDeadLetterSink(sink DeadLetterSink[ProcessedData])
+
+	// SetHistogram sets the buckets, and percentiles, of the duration
+	// histogram, replacing it.
+	SetHistogram(buckets, percentiles []float64) error
+
 	// Run the stage function.
 	Run(
 		ctx context.Context,
@@ -29,3 +29,31 @@ func WithOnFinished[ProcessedData, ConvertedOut any](onFinished OnFinished[Proce
 		return p
 	}
 }
+
+// WithErrorPolicy sets how the stage reacts to items failing conversion.
+func WithErrorPolicy[ProcessedData, ConvertedOut any](errorPolicy ErrorPolicy) Func[ProcessedData, ConvertedOut] {
+	return func(p IStage[ProcessedData, ConvertedOut]) IStage[ProcessedData, ConvertedOut] {
+		p.SetErrorPolicy(errorPolicy)
+
+		return p
+	}
+}
+
+// WithDeadLetterSink sets where the items dropped by the error policy go.
+func WithDeadLetterSink[ProcessedData, ConvertedOut any](sink DeadLetterSink[ProcessedData]) Func[ProcessedData, ConvertedOut] {
+	return func(p IStage[ProcessedData, ConvertedOut]) IStage[ProcessedData, ConvertedOut] {
+		p.SetDeadLetterSink(sink)
+
+		return p
+	}
+}
+
+// WithConcurrency sets how many items the stage converts at once, and how
+// fast.
+func WithConcurrency[ProcessedData, ConvertedOut any](concurrency Concurrency) Func[ProcessedData, ConvertedOut] {
+	return func(p IStage[ProcessedData, ConvertedOut]) IStage[ProcessedData, ConvertedOut] {
+		p.SetConcurrency(concurrency)
+
+		return p
+	}
+}
@@ -5,17 +5,21 @@ import (
 	"errors"
 	"expvar"
 	"fmt"
+	"maps"
+	"strconv"
 	"sync"
 	"time"
 
-	"github.com/thalesfsp/concurrentloop"
 	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/event"
 	"github.com/thalesfsp/etler/v3/internal/customapm"
 	"github.com/thalesfsp/etler/v3/internal/logging"
 	"github.com/thalesfsp/etler/v3/internal/metrics"
 	"github.com/thalesfsp/etler/v3/internal/shared"
 	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/report"
 	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/etler/v3/tracing"
 	"github.com/thalesfsp/status"
 	"github.com/thalesfsp/sypl/v2"
 	"github.com/thalesfsp/sypl/v2/level"
@@ -34,9 +38,19 @@ type Stage[ProcessingData, ConvertedData any] struct {
 	// Description of the stage.
 	Description string `json:"description"`
 
+	// Concurrency bounds how many items the stage converts at once, and how
+	// fast.
+	Concurrency Concurrency `json:"concurrency"`
+
 	// Conversor to be used tsk the stage.
 	Conversor converter.IConverter[ProcessingData, ConvertedData] `json:"-" validate:"required"`
 
+	// DeadLetterSink receives the items dropped by the error policy.
+	DeadLetterSink DeadLetterSink[ProcessingData] `json:"-"`
+
+	// ErrorPolicy defines how the stage reacts to items failing conversion.
+	ErrorPolicy ErrorPolicy `json:"errorPolicy"`
+
 	// Logger is the internal logger.
 	Logger sypl.ISypl `json:"-" validate:"required"`
 
@@ -50,17 +64,38 @@ type Stage[ProcessingData, ConvertedData any] struct {
 	// Processors to be run tsk the stage.
 	Processors []processor.IProcessor[ProcessingData] `json:"processors" validate:"required,gt=0"`
 
-	// Metrics.
-	CounterCreated *expvar.Int `json:"counterCreated"`
-	CounterDone    *expvar.Int `json:"counterDone"`
-	CounterFailed  *expvar.Int `json:"counterFailed"`
-	CounterRunning *expvar.Int `json:"counterRunning"`
+	// Buckets of the duration histogram, in milliseconds. See
+	// `SetHistogram`.
+	Buckets []float64 `json:"buckets,omitempty"`
+
+	// Percentiles reported by the duration histogram. See `SetHistogram`.
+	Percentiles []float64 `json:"percentiles,omitempty"`
 
-	CreatedAt       time.Time      `json:"createdAt"`
-	Duration        *expvar.Int    `json:"duration"`
-	Progress        *expvar.Int    `json:"progress"`
-	ProgressPercent *expvar.String `json:"progressPercent"`
-	Status          *expvar.String `json:"status"`
+	// Metrics.
+	CounterCreated      *expvar.Int `json:"counterCreated"`
+	CounterDeadLettered *expvar.Int `json:"counterDeadLettered"`
+	CounterDone         *expvar.Int `json:"counterDone"`
+	CounterFailed       *expvar.Int `json:"counterFailed"`
+	CounterInterrupted  *expvar.Int `json:"counterInterrupted"`
+	CounterItemsIn      *expvar.Int `json:"counterItemsIn"`
+	CounterItemsOut     *expvar.Int `json:"counterItemsOut"`
+	CounterRunning      *expvar.Int `json:"counterRunning"`
+
+	CreatedAt         time.Time          `json:"createdAt"`
+	Duration          *expvar.Int        `json:"duration"`
+	DurationHistogram *metrics.Histogram `json:"durationHistogram"`
+	Progress          *expvar.Int        `json:"progress"`
+	ProgressPercent   *expvar.String     `json:"progressPercent"`
+	Status            *expvar.String     `json:"status"`
+
+	// pause is this stage's pause controller, combined with those of its
+	// pipeline, and run.
+	pause *shared.PauseController
+
+	// bus is where the stage publishes its pause events, on behalf of the
+	// pipeline named `pipeline`.
+	bus      *event.Bus
+	pipeline string
 }
 
 //////
@@ -102,6 +137,17 @@ func (s *Stage[ProcessingData, ConvertedData]) GetCounterDone() *expvar.Int {
 	return s.CounterDone
 }
 
+// GetCounterDeadLettered returns the `CounterDeadLettered` of the stage.
+func (s *Stage[ProcessingData, ConvertedData]) GetCounterDeadLettered() *expvar.Int {
+	return s.CounterDeadLettered
+}
+
+// GetCounterInterrupted returns the `CounterInterrupted` of the stage: the
+// runs stopped by a drain.
+func (s *Stage[ProcessingData, ConvertedData]) GetCounterInterrupted() *expvar.Int {
+	return s.CounterInterrupted
+}
+
 // GetProgress returns the `CounterProgress` of the stage.
 func (s *Stage[ProcessingData, ConvertedData]) GetProgress() *expvar.Int {
 	return s.Progress
@@ -128,6 +174,64 @@ func (s *Stage[ProcessingData, ConvertedData]) SetProgressPercent() {
 	s.GetProgressPercent().Set(fmt.Sprintf("%d%%", int(percentage)))
 }
 
+// GetPaused returns the Paused status of THIS stage.
+func (s *Stage[ProcessingData, ConvertedData]) GetPaused() status.Status {
+	if s.pause.Paused() {
+		return status.Paused
+	}
+
+	return status.Runnning
+}
+
+// SetPause sets the Paused status of THIS stage only. Its processors pause
+// before their next execution, while the other stages keep running, and
+// resume immediately on unpause, unless their pipeline, or run, is paused.
+func (s *Stage[ProcessingData, ConvertedData]) SetPause(state bool) {
+	e := event.Event{
+		Type:      event.Paused,
+		Pipeline:  s.pipeline,
+		Component: Type,
+		Name:      s.GetName(),
+	}
+
+	if state {
+		s.GetStatus().Set(status.Paused.String())
+
+		s.pause.Pause()
+
+		s.GetEventBus().Publish(context.Background(), e)
+
+		return
+	}
+
+	// Updates the stage's status.
+	s.GetStatus().Set(status.Runnning.String())
+
+	s.pause.Resume()
+
+	e.Type = event.Resumed
+
+	s.GetEventBus().Publish(context.Background(), e)
+}
+
+// GetEventBus returns the bus the stage publishes its pause events to, if
+// any.
+func (s *Stage[ProcessingData, ConvertedData]) GetEventBus() *event.Bus {
+	return s.bus
+}
+
+// SetEventBus sets the bus the stage publishes its pause events to, on
+// behalf of the pipeline named `pipeline`. Pipelines set theirs on their
+// stages, and processors, when created.
+func (s *Stage[ProcessingData, ConvertedData]) SetEventBus(bus *event.Bus, pipeline string) {
+	s.bus = bus
+	s.pipeline = pipeline
+
+	for _, p := range s.Processors {
+		p.SetEventBus(bus, pipeline)
+	}
+}
+
 // GetStatus returns the `Status` metric.
 func (s *Stage[ProcessingData, ConvertedData]) GetStatus() *expvar.String {
 	return s.Status
@@ -143,6 +247,36 @@ func (s *Stage[ProcessingData, ConvertedData]) SetOnFinished(onFinished OnFinish
 	s.OnFinished = onFinished
 }
 
+// GetErrorPolicy returns the `ErrorPolicy` of the stage.
+func (s *Stage[ProcessingData, ConvertedData]) GetErrorPolicy() ErrorPolicy {
+	return s.ErrorPolicy
+}
+
+// SetErrorPolicy sets the `ErrorPolicy` of the stage.
+func (s *Stage[ProcessingData, ConvertedData]) SetErrorPolicy(errorPolicy ErrorPolicy) {
+	s.ErrorPolicy = errorPolicy
+}
+
+// GetConcurrency returns the `Concurrency` of the stage.
+func (s *Stage[ProcessingData, ConvertedData]) GetConcurrency() Concurrency {
+	return s.Concurrency
+}
+
+// SetConcurrency sets the `Concurrency` of the stage.
+func (s *Stage[ProcessingData, ConvertedData]) SetConcurrency(concurrency Concurrency) {
+	s.Concurrency = concurrency
+}
+
+// GetDeadLetterSink returns the `DeadLetterSink` of the stage.
+func (s *Stage[ProcessingData, ConvertedData]) GetDeadLetterSink() DeadLetterSink[ProcessingData] {
+	return s.DeadLetterSink
+}
+
+// SetDeadLetterSink sets the `DeadLetterSink` of the stage.
+func (s *Stage[ProcessingData, ConvertedData]) SetDeadLetterSink(sink DeadLetterSink[ProcessingData]) {
+	s.DeadLetterSink = sink
+}
+
 // GetType returns the entity type.
 func (s *Stage[ProcessingData, ConvertedData]) GetType() string {
 	return Type
@@ -158,23 +292,169 @@ func (s *Stage[ProcessingData, ConvertedData]) GetDuration() *expvar.Int {
 	return s.Duration
 }
 
+// GetDurationHistogram returns the `DurationHistogram` of the stage.
+func (s *Stage[ProcessingData, ConvertedData]) GetDurationHistogram() *metrics.Histogram {
+	return s.DurationHistogram
+}
+
+// SetHistogram sets the buckets, in milliseconds, and the percentiles of the
+// duration histogram, replacing it. If none, the defaults, or those set
+// through the `ETLER_METRICS_BUCKETS`, and `ETLER_METRICS_PERCENTILES`, env
+// vars, are used.
+func (s *Stage[ProcessingData, ConvertedData]) SetHistogram(buckets, percentiles []float64) error {
+	s.Buckets = buckets
+	s.Percentiles = percentiles
+
+	durationHistogram, err := s.newDurationHistogram()
+	if err != nil {
+		return err
+	}
+
+	s.DurationHistogram = durationHistogram
+
+	return nil
+}
+
+// newDurationHistogram returns a new duration histogram, with the stage's
+// buckets, and percentiles.
+func (s *Stage[ProcessingData, ConvertedData]) newDurationHistogram() (*metrics.Histogram, error) {
+	return metrics.NewHistogramWithPattern(
+		Type,
+		s.GetName(),
+		"duration",
+		metrics.WithBuckets(s.Buckets...),
+		metrics.WithPercentiles(s.Percentiles...),
+	)
+}
+
+// GetCounterItemsIn returns the `CounterItemsIn` metric: the items the
+// stage received.
+func (s *Stage[ProcessingData, ConvertedData]) GetCounterItemsIn() *expvar.Int {
+	return s.CounterItemsIn
+}
+
+// GetCounterItemsOut returns the `CounterItemsOut` metric: the items the
+// stage returned.
+func (s *Stage[ProcessingData, ConvertedData]) GetCounterItemsOut() *expvar.Int {
+	return s.CounterItemsOut
+}
+
 // GetMetrics returns the stage's metrics.
 func (s *Stage[ProcessingData, ConvertedData]) GetMetrics() map[string]string {
-	return map[string]string{
-		"createdAt":       s.GetCreatedAt().String(),
-		"counterCreated":  s.GetCounterCreated().String(),
-		"counterDone":     s.GetCounterDone().String(),
-		"counterFailed":   s.GetCounterFailed().String(),
-		"counterRunning":  s.GetCounterRunning().String(),
-		"duration":        s.GetDuration().String(),
-		"progress":        s.GetProgress().String(),
-		"progressPercent": s.GetProgressPercent().String(),
-		"status":          s.GetStatus().String(),
+	m := map[string]string{
+		"createdAt":           s.GetCreatedAt().String(),
+		"counterCreated":      s.GetCounterCreated().String(),
+		"counterDeadLettered": s.GetCounterDeadLettered().String(),
+		"counterDone":         s.GetCounterDone().String(),
+		"counterFailed":       s.GetCounterFailed().String(),
+		"counterInterrupted":  s.GetCounterInterrupted().String(),
+		"counterItemsIn":      s.GetCounterItemsIn().String(),
+		"counterItemsOut":     s.GetCounterItemsOut().String(),
+		"counterRunning":      s.GetCounterRunning().String(),
+		"duration":            s.GetDuration().String(),
+		"progress":            s.GetProgress().String(),
+		"progressPercent":     s.GetProgressPercent().String(),
+		"status":              s.GetStatus().String(),
+		"workers":             strconv.Itoa(s.GetConcurrency().workers()),
 	}
+
+	maps.Copy(m, s.GetDurationHistogram().Metrics("duration"))
+
+	// NOTE: Zero if unlimited.
+	m["rateLimit"] = strconv.FormatFloat(s.GetConcurrency().RateLimit, 'f', 3, 64)
+
+	// NOTE: Items out, over the time successful runs took.
+	m["itemsPerSecond"] = strconv.FormatFloat(
+		metrics.Throughput(s.GetCounterItemsOut().Value(), s.GetDurationHistogram().Sum()),
+		'f', 3, 64,
+	)
+
+	return m
 }
 
-// Run the transform function.
+// Run the transform function. Within a pipeline run, the stage, and its
+// processors, are reported to the run's report.
 func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task.Task[ProcessingData, ConvertedData]) (task.Task[ProcessingData, ConvertedData], error) {
+	sr := report.FromContext(ctx).StartStage(s.GetName(), tsk.ID, len(tsk.ProcessingData))
+
+	ctx = event.ContextWithTask(report.ContextWithStage(ctx, sr), tsk.ID)
+
+	event.Publish(ctx, event.Event{
+		Type:      event.StageStarted,
+		Component: Type,
+		Name:      s.GetName(),
+		ItemsIn:   len(tsk.ProcessingData),
+	})
+
+	now := time.Now()
+
+	out, err := s.run(ctx, tsk)
+
+	// NOTE: Dead letters accumulate across stages.
+	sr.Finish(len(out.ConvertedData), max(len(out.DeadLetters)-len(tsk.DeadLetters), 0), err)
+
+	event.Publish(ctx, event.Event{
+		Type:      event.Outcome(err, event.StageFinished, event.StageFailed, event.StageInterrupted),
+		Component: Type,
+		Name:      s.GetName(),
+		Duration:  time.Since(now),
+		ItemsIn:   len(tsk.ProcessingData),
+		ItemsOut:  len(out.ConvertedData),
+		Err:       err,
+	})
+
+	return out, err
+}
+
+// interrupted returns whether conversions failed only because the run
+// drains.
+func interrupted(errs []error) bool {
+	found := false
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, shared.ErrInterrupted) {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
+
+// interrupt joins the async processors in flight, and stops the stage
+// because its run drains, unless they failed.
+//
+// NOTE: `asyncErrs` is only read once they're joined.
+func (s *Stage[ProcessingData, ConvertedData]) interrupt(asyncWG *sync.WaitGroup, asyncErrs *[]error) error {
+	asyncWG.Wait()
+
+	if len(*asyncErrs) > 0 {
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		s.GetStatus().Set(status.Failed.String())
+
+		s.GetCounterFailed().Add(1)
+
+		// Already traced by the failing processor(s).
+		return errors.Join(*asyncErrs...)
+	}
+
+	//////
+	// Observability: tracing, metrics, status, logging, etc.
+	//////
+
+	return shared.OnInterruptedHandler(s, s.GetCounterInterrupted(), s.GetLogger())
+}
+
+// run is `Run`, reporting to the stage recorder in `ctx`, if any.
+func (s *Stage[ProcessingData, ConvertedData]) run(ctx context.Context, tsk task.Task[ProcessingData, ConvertedData]) (task.Task[ProcessingData, ConvertedData], error) {
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -189,8 +469,14 @@ func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task
 	)
 	defer span.End()
 
-	// Update the status.
-	s.GetStatus().Set(status.Runnning.String())
+	// Make this stage's pause controller visible to its processors.
+	tracedContext = shared.ContextWithPause(tracedContext, s.pause)
+
+	// A paused stage keeps reporting paused — its processors are about to
+	// block on the pause controller.
+	if !s.pause.Paused() {
+		s.GetStatus().Set(status.Runnning.String())
+	}
 
 	s.GetLogger().PrintlnWithOptions(level.Trace, status.Runnning.String())
 
@@ -199,6 +485,12 @@ func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task
 
 	s.SetProgressPercent()
 
+	s.GetCounterItemsIn().Add(int64(len(tsk.ProcessingData)))
+
+	tracedContext = customapm.Annotate(tracedContext, span, tracing.String(tracing.KeyTaskID, tsk.ID))
+
+	span.SetAttributes(tracing.Int(tracing.KeyItemsIn, len(tsk.ProcessingData)))
+
 	now := time.Now()
 
 	//////
@@ -222,6 +514,12 @@ func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task
 
 	// NOTE: It process the data sequentially.
 	for _, proc := range s.Processors {
+		// A draining run stops before its next processor, once the async
+		// processors in flight finish.
+		if shared.IsDraining(tracedContext) {
+			return task.Task[ProcessingData, ConvertedData]{}, s.interrupt(&asyncWG, &asyncErrs)
+		}
+
 		if proc.GetAsync() {
 			// The goroutine gets its own copy of the data as it was at this
 			// processor's position in the chain. A slice-header snapshot
@@ -249,6 +547,10 @@ func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task
 			// Re-use the output of the previous stage as the input of the
 			// next stage ensuring that the data is processed sequentially.
 			rFI, err := proc.Run(tracedContext, retroFeedIn)
+			if errors.Is(err, shared.ErrInterrupted) {
+				return task.Task[ProcessingData, ConvertedData]{}, s.interrupt(&asyncWG, &asyncErrs)
+			}
+
 			if err != nil {
 				//////
 				// Observability: tracing, metrics, status, logging, etc.
@@ -290,16 +592,22 @@ func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task
 	// Stage's conversor.
 	//////
 
-	// NOTE: WithRemoveZeroValues(false) is required. The default would
-	// silently drop converted items that happen to be the zero value of
-	// `ConvertedData` — data loss.
-	convertedData, errs := concurrentloop.Map(
-		tracedContext,
-		retroFeedIn,
-		s.Conversor.Run,
-		concurrentloop.WithRemoveZeroValues(false),
+	var (
+		convertedData []ConvertedData
+		deadLetters   []task.DeadLetter[ProcessingData]
+		errs          error
 	)
 
+	if s.GetErrorPolicy().Mode == SkipAndCollect || s.GetErrorPolicy().Mode == MaxErrorRatio {
+		convertedData, deadLetters, errs = s.convertIsolated(tracedContext, retroFeedIn)
+	} else {
+		convertedData, errs = s.convertAll(tracedContext, retroFeedIn)
+	}
+
+	if errors.Is(errs, shared.ErrInterrupted) {
+		return task.Task[ProcessingData, ConvertedData]{}, s.interrupt(&asyncWG, &asyncErrs)
+	}
+
 	// Join the async processors: the stage is not done while they run, and
 	// their failures fail the stage.
 	asyncWG.Wait()
@@ -337,6 +645,39 @@ func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task
 		return task.Task[ProcessingData, ConvertedData]{}, asyncErr
 	}
 
+	if len(deadLetters) > 0 {
+		if s.GetDeadLetterSink() != nil {
+			if err := s.GetDeadLetterSink()(tracedContext, deadLetters); err != nil {
+				//////
+				// Observability: tracing, metrics, status, logging, etc.
+				//////
+
+				s.GetStatus().Set(status.Failed.String())
+
+				return task.Task[ProcessingData, ConvertedData]{}, shared.OnErrorHandler(
+					tracedContext,
+					s,
+					s.GetLogger(),
+					err,
+					"send dead letters",
+					Type,
+					s.GetName(),
+				)
+			}
+		}
+
+		//////
+		// Observability: tracing, metrics, status, logging, etc.
+		//////
+
+		s.GetCounterDeadLettered().Add(int64(len(deadLetters)))
+
+		s.GetLogger().PrintlnWithOptions(
+			level.Warn,
+			fmt.Sprintf("%d of %d items failed conversion, and were dead-lettered", len(deadLetters), len(retroFeedIn)),
+		)
+	}
+
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -353,12 +694,26 @@ func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task
 
 	tsk.ConvertedData = convertedData
 
+	if len(deadLetters) > 0 {
+		// NOTE: Copies, so tasks sharing the backing array (e.g., DAG
+		// branches) never see each other's dead letters.
+		tsk.DeadLetters = append(append([]task.DeadLetter[ProcessingData](nil), tsk.DeadLetters...), deadLetters...)
+	}
+
 	if s.GetOnFinished() != nil {
 		s.GetOnFinished()(ctx, s, originalTask, tsk)
 	}
 
+	s.GetCounterItemsOut().Add(int64(len(convertedData)))
+
+	span.SetAttributes(tracing.Int(tracing.KeyItemsOut, len(convertedData)))
+
 	// Set duration.
-	s.GetDuration().Set(time.Since(now).Milliseconds())
+	elapsed := time.Since(now)
+
+	s.GetDuration().Set(elapsed.Milliseconds())
+
+	s.GetDurationHistogram().Observe(float64(elapsed.Microseconds()) / 1000)
 
 	// Print the stage's status.
 	s.GetLogger().PrintWithOptions(
@@ -366,6 +721,7 @@ func (s *Stage[ProcessingData, ConvertedData]) Run(ctx context.Context, tsk task
 		status.Done.String(),
 		sypl.WithField("createdAt", s.GetCreatedAt().String()),
 		sypl.WithField("counterCreated", s.GetCounterCreated().String()),
+		sypl.WithField("counterDeadLettered", s.GetCounterDeadLettered().String()),
 		sypl.WithField("counterDone", s.GetCounterDone().String()),
 		sypl.WithField("counterFailed", s.GetCounterFailed().String()),
 		sypl.WithField("counterRunning", s.GetCounterRunning().String()),
@@ -393,15 +749,20 @@ func New[ProcessingData, ConvertedData any](
 		Logger:     logging.Get().New(name).SetTags(Type, name),
 		Processors: processors,
 		Conversor:  conversor,
+		pause:      shared.NewPauseController(),
 
 		CreatedAt:   time.Now(),
 		Name:        name,
 		Description: description,
 
-		CounterCreated: metrics.NewIntWithPattern(Type, name, status.Created),
-		CounterDone:    metrics.NewIntWithPattern(Type, name, status.Done),
-		CounterFailed:  metrics.NewIntWithPattern(Type, name, status.Failed),
-		CounterRunning: metrics.NewIntWithPattern(Type, name, status.Runnning),
+		CounterCreated:      metrics.NewIntWithPattern(Type, name, status.Created),
+		CounterDeadLettered: metrics.NewIntWithPattern(Type, name, "deadLettered"),
+		CounterDone:         metrics.NewIntWithPattern(Type, name, status.Done),
+		CounterFailed:       metrics.NewIntWithPattern(Type, name, status.Failed),
+		CounterInterrupted:  metrics.NewIntWithPattern(Type, name, status.Interrupted),
+		CounterItemsIn:      metrics.NewIntWithPattern(Type, name, "itemsIn"),
+		CounterItemsOut:     metrics.NewIntWithPattern(Type, name, "itemsOut"),
+		CounterRunning:      metrics.NewIntWithPattern(Type, name, status.Runnning),
 
 		Duration:        metrics.NewIntWithPattern(Type, name, "duration"),
 		Progress:        metrics.NewIntWithPattern(Type, name, "progress"),
@@ -414,6 +775,13 @@ func New[ProcessingData, ConvertedData any](
 		return nil, err
 	}
 
+	durationHistogram, err := s.newDurationHistogram()
+	if err != nil {
+		return nil, err
+	}
+
+	s.DurationHistogram = durationHistogram
+
 	//////
 	// Observability: tracing, metrics, status, logging, etc.
 	//////
@@ -0,0 +1,116 @@
+package stage
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/task"
+)
+
+// newConcurrencyStage returns a stage whose converter records the most
+// items it converted at once.
+func newConcurrencyStage(t *testing.T, name string, maxInFlight *atomic.Int32) IStage[int, int] {
+	t.Helper()
+
+	var inFlight atomic.Int32
+
+	identity, err := processor.New(name+"-proc", "identity", func(ctx context.Context, in []int) ([]int, error) {
+		return in, nil
+	})
+	require.NoError(t, err)
+
+	conv := converter.MustDefault(func(ctx context.Context, in int) (int, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		return in, nil
+	})
+
+	stg, err := New(name, "bounded conversion", conv, identity)
+	require.NoError(t, err)
+
+	return stg
+}
+
+// Happy path: a stage converts at most `Workers` items at once, reported by
+// its metrics.
+func TestStage_Concurrency_workers(t *testing.T) {
+	var maxInFlight atomic.Int32
+
+	stg := newConcurrencyStage(t, "concurrency-workers", &maxInFlight)
+
+	WithConcurrency[int, int](Concurrency{Workers: 2})(stg)
+
+	out, err := stg.Run(context.Background(), task.MustNew[int, int]([]int{1, 2, 3, 4, 5, 6, 7, 8}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, out.ConvertedData)
+	assert.Equal(t, int32(2), maxInFlight.Load())
+
+	m := stg.GetMetrics()
+	assert.Equal(t, "2", m["workers"])
+	assert.Equal(t, "0.000", m["rateLimit"])
+
+	// Edge case: the error policy's conversion is bounded too.
+	maxInFlight.Store(0)
+
+	WithErrorPolicy[int, int](ErrorPolicy{Mode: SkipAndCollect})(stg)
+
+	_, err = stg.Run(context.Background(), task.MustNew[int, int]([]int{1, 2, 3, 4, 5, 6}))
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), maxInFlight.Load())
+}
+
+// Happy path: a stage converts at most `RateLimit` items per second.
+func TestStage_Concurrency_rateLimit(t *testing.T) {
+	var maxInFlight atomic.Int32
+
+	stg := newConcurrencyStage(t, "concurrency-rate-limit", &maxInFlight)
+
+	stg.SetConcurrency(Concurrency{Workers: 4, RateLimit: 20})
+
+	now := time.Now()
+
+	_, err := stg.Run(context.Background(), task.MustNew[int, int]([]int{1, 2, 3, 4, 5}))
+	require.NoError(t, err)
+
+	// The first item right away, then one every 50ms.
+	assert.GreaterOrEqual(t, time.Since(now), 200*time.Millisecond)
+
+	assert.Equal(t, "20.000", stg.GetMetrics()["rateLimit"])
+
+	// Bad path: an invalid concurrency fails the run.
+	for _, concurrency := range []Concurrency{
+		{Workers: -1},
+		{RateLimit: -1},
+		{RateLimit: 1e-12},
+	} {
+		stg.SetConcurrency(concurrency)
+
+		_, err = stg.Run(context.Background(), task.MustNew[int, int]([]int{1}))
+		require.Error(t, err)
+	}
+}
+
+// Edge case: huge rates still limit, by at least 1ns.
+func TestNewLimiter(t *testing.T) {
+	assert.Equal(t, 50*time.Millisecond, newLimiter(20).interval)
+	assert.Equal(t, 1000*time.Second, newLimiter(MinRateLimit).interval)
+	assert.Equal(t, time.Nanosecond, newLimiter(1e12).interval)
+}
@@ -0,0 +1,207 @@
+package stage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/status"
+)
+
+// newNegativeRejectingStage returns a stage whose converter fails on negative
+// items.
+func newNegativeRejectingStage(t *testing.T, name string) IStage[int, int] {
+	t.Helper()
+
+	identity, err := processor.New(
+		name+"-identity",
+		"identity",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			return processingData, nil
+		},
+	)
+	require.NoError(t, err)
+
+	stg, err := New(
+		name,
+		"rejects negative items",
+		converter.MustDefault(
+			func(ctx context.Context, in int) (int, error) {
+				if in < 0 {
+					return 0, errors.New("negative item")
+				}
+
+				return in * 2, nil
+			},
+		),
+		identity,
+	)
+	require.NoError(t, err)
+
+	return stg
+}
+
+// Happy path: skip-and-collect keeps the good items, in order, and exposes
+// the bad ones as dead letters, on the task and on the sink.
+func TestStage_errorPolicy_skipAndCollect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		sunk []task.DeadLetter[int]
+	)
+
+	stg := newNegativeRejectingStage(t, "stage-skip-and-collect")
+
+	WithErrorPolicy[int, int](ErrorPolicy{Mode: SkipAndCollect})(stg)
+	WithDeadLetterSink[int, int](func(ctx context.Context, deadLetters []task.DeadLetter[int]) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		sunk = append(sunk, deadLetters...)
+
+		return nil
+	})(stg)
+
+	out, err := stg.Run(ctx, task.MustNew[int, int]([]int{1, -2, 3, -4, 0}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{2, 6, 0}, out.ConvertedData)
+	require.Len(t, out.DeadLetters, 2)
+
+	assert.Equal(t, "stage-skip-and-collect", out.DeadLetters[0].Stage)
+	assert.Equal(t, 1, out.DeadLetters[0].Index)
+	assert.Equal(t, -2, out.DeadLetters[0].Item)
+	assert.Contains(t, out.DeadLetters[0].Error, "negative item")
+	require.Error(t, out.DeadLetters[0].Err)
+	assert.Equal(t, 3, out.DeadLetters[1].Index)
+	assert.Equal(t, -4, out.DeadLetters[1].Item)
+
+	assert.Equal(t, out.DeadLetters, sunk)
+
+	assert.Equal(t, status.Done.String(), stg.GetStatus().Value())
+	assert.Equal(t, int64(2), stg.GetCounterDeadLettered().Value())
+	assert.Equal(t, "2", stg.GetMetrics()["counterDeadLettered"])
+}
+
+// Happy path: dead letters accumulate across stages.
+func TestStage_errorPolicy_deadLettersAccumulate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stg := newNegativeRejectingStage(t, "stage-accumulate")
+
+	WithErrorPolicy[int, int](ErrorPolicy{Mode: SkipAndCollect})(stg)
+
+	in := task.MustNew[int, int]([]int{-1, 1})
+	in.DeadLetters = []task.DeadLetter[int]{{Stage: "upstream", Index: 7, Item: 7, Error: "upstream"}}
+
+	out, err := stg.Run(ctx, in)
+	require.NoError(t, err)
+
+	require.Len(t, out.DeadLetters, 2)
+	assert.Equal(t, "upstream", out.DeadLetters[0].Stage)
+	assert.Equal(t, "stage-accumulate", out.DeadLetters[1].Stage)
+
+	// The input task is untouched.
+	assert.Len(t, in.DeadLetters, 1)
+}
+
+// Max error ratio: tolerated up to the ratio, fails the stage above it.
+func TestStage_errorPolicy_maxErrorRatio(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name    string
+		ratio   float64
+		wantErr bool
+	}{
+		{name: "at the ratio", ratio: 0.5, wantErr: false},
+		{name: "above the ratio", ratio: 0.25, wantErr: true},
+		{name: "no errors tolerated", ratio: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stg := newNegativeRejectingStage(t, "stage-max-error-ratio")
+
+			WithErrorPolicy[int, int](ErrorPolicy{Mode: MaxErrorRatio, MaxErrorRatio: tt.ratio})(stg)
+
+			out, err := stg.Run(ctx, task.MustNew[int, int]([]int{1, -2, 3, -4}))
+
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, []int{2, 6}, out.ConvertedData)
+				assert.Len(t, out.DeadLetters, 2)
+
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "2 of 4 items failed")
+			assert.Contains(t, err.Error(), "item 1: failed to process. Original Error: negative item")
+			assert.Equal(t, status.Failed.String(), stg.GetStatus().Value())
+			assert.Equal(t, int64(0), stg.GetCounterDeadLettered().Value())
+		})
+	}
+}
+
+// Bad path: fail-fast, the default, fails the stage on a single bad item.
+func TestStage_errorPolicy_failFastByDefault(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stg := newNegativeRejectingStage(t, "stage-fail-fast")
+
+	assert.Equal(t, ErrorPolicy{}, stg.GetErrorPolicy())
+
+	_, err := stg.Run(ctx, task.MustNew[int, int]([]int{1, -2, 3}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "negative item")
+	assert.Equal(t, status.Failed.String(), stg.GetStatus().Value())
+}
+
+// Bad path: a failing sink fails the stage, dropped items are never lost
+// silently.
+func TestStage_errorPolicy_failingSink(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stg := newNegativeRejectingStage(t, "stage-failing-sink")
+
+	WithErrorPolicy[int, int](ErrorPolicy{Mode: SkipAndCollect})(stg)
+	WithDeadLetterSink[int, int](func(ctx context.Context, deadLetters []task.DeadLetter[int]) error {
+		return errors.New("sink unavailable")
+	})(stg)
+
+	_, err := stg.Run(ctx, task.MustNew[int, int]([]int{1, -2}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sink unavailable")
+	assert.Equal(t, status.Failed.String(), stg.GetStatus().Value())
+
+	// Without dead letters, the sink isn't called.
+	_, err = stg.Run(ctx, task.MustNew[int, int]([]int{1, 2}))
+	require.NoError(t, err)
+}
+
+// Bad path: an invalid ratio is rejected.
+func TestStage_errorPolicy_invalidRatio(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stg := newNegativeRejectingStage(t, "stage-invalid-ratio")
+
+	WithErrorPolicy[int, int](ErrorPolicy{Mode: MaxErrorRatio, MaxErrorRatio: 1.5})(stg)
+
+	_, err := stg.Run(ctx, task.MustNew[int, int]([]int{1}))
+	require.Error(t, err)
+}
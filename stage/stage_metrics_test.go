@@ -0,0 +1,120 @@
+package stage
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/task"
+)
+
+func TestStage_histogramAndItems(t *testing.T) {
+	evens, err := processor.New(
+		"evens-metrics",
+		"keeps even numbers",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			out := []int{}
+
+			for _, v := range processingData {
+				if v%2 == 0 {
+					out = append(out, v)
+				}
+			}
+
+			return out, nil
+		},
+	)
+	require.NoError(t, err)
+
+	stg, err := New("metrics-stage", "metrics test", identityConverter(), evens)
+	require.NoError(t, err)
+
+	s, ok := stg.(*Stage[int, int])
+	require.True(t, ok)
+
+	// Happy path: concurrent runs are all observed, none clobbered.
+	var wg sync.WaitGroup
+
+	for range 5 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := stg.Run(context.Background(), task.MustNew[int, int]([]int{1, 2, 3, 4}))
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, uint64(5), s.GetDurationHistogram().Count())
+	assert.Equal(t, int64(20), s.GetCounterItemsIn().Value())
+	assert.Equal(t, int64(10), s.GetCounterItemsOut().Value())
+
+	m := stg.GetMetrics()
+	assert.Equal(t, "20", m["counterItemsIn"])
+	assert.Equal(t, "10", m["counterItemsOut"])
+	assert.Equal(t, "5", m["durationCount"])
+
+	for _, key := range []string{"durationSum", "durationP50", "durationP90", "durationP99"} {
+		assert.Contains(t, m, key)
+	}
+
+	rate, err := strconv.ParseFloat(m["itemsPerSecond"], 64)
+	assert.NoError(t, err)
+	assert.Positive(t, rate)
+
+	// Processors count their own items.
+	assert.Equal(t, "20", evens.GetMetrics()["counterItemsIn"])
+	assert.Equal(t, "10", evens.GetMetrics()["counterItemsOut"])
+	assert.Equal(t, "5", evens.GetMetrics()["durationCount"])
+}
+
+func TestStage_histogramBuckets(t *testing.T) {
+	identity, err := processor.New(
+		"identity-buckets",
+		"returns its input",
+		func(ctx context.Context, processingData []int) ([]int, error) {
+			return processingData, nil
+		},
+	)
+	require.NoError(t, err)
+
+	conv := identityConverter()
+
+	stg, err := New("buckets-stage", "histogram buckets test", conv, identity)
+	require.NoError(t, err)
+
+	// Happy path: per-stage buckets, and percentiles.
+	require.NoError(t, stg.SetHistogram([]float64{1000, 10}, []float64{95}))
+
+	_, err = stg.Run(context.Background(), task.MustNew[int, int]([]int{1}))
+	require.NoError(t, err)
+
+	m := stg.GetMetrics()
+	assert.Equal(t, "1", m["durationCount"])
+	assert.Contains(t, m, "durationP95")
+	assert.NotContains(t, m, "durationP50")
+
+	s, ok := stg.(*Stage[int, int])
+	require.True(t, ok)
+
+	buckets := s.GetDurationHistogram().Snapshot().Buckets
+	require.Len(t, buckets, 2)
+	assert.InDelta(t, 10, buckets[0].UpperBound, 0)
+
+	// Bad path: invalid percentiles are reported, the histogram is kept.
+	assert.ErrorContains(t, stg.SetHistogram(nil, []float64{101}), "percentiles")
+	assert.Len(t, s.GetDurationHistogram().Snapshot().Buckets, 2)
+
+	// Bad path: invalid env vars fail the factory.
+	t.Setenv("ETLER_METRICS_BUCKETS", "ten")
+
+	_, err = New("buckets-env-stage", "histogram buckets test", conv, identity)
+	assert.ErrorContains(t, err, "ETLER_METRICS_BUCKETS")
+}
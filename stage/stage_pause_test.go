@@ -0,0 +1,138 @@
+package stage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thalesfsp/etler/v3/converter"
+	"github.com/thalesfsp/etler/v3/internal/shared"
+	"github.com/thalesfsp/etler/v3/processor"
+	"github.com/thalesfsp/etler/v3/task"
+	"github.com/thalesfsp/status"
+)
+
+// Happy path: pausing a stage holds its processors, not the other stages'.
+func TestStage_SetPause(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	newStage := func(name string) (IStage[int, int], processor.IProcessor[int]) {
+		proc, err := processor.New(name+"-proc", "identity", func(ctx context.Context, in []int) ([]int, error) {
+			return in, nil
+		})
+		require.NoError(t, err)
+
+		stg, err := New(name, "pausable stage", identityConverter(), proc)
+		require.NoError(t, err)
+
+		return stg, proc
+	}
+
+	paused, pausedProc := newStage("pause-stage-held")
+	running, _ := newStage("pause-stage-free")
+
+	paused.SetPause(true)
+
+	assert.Equal(t, status.Paused, paused.GetPaused())
+	assert.Equal(t, status.Paused.String(), paused.GetStatus().Value())
+
+	tsk, err := task.New[int, int]([]int{1})
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := paused.Run(ctx, tsk)
+
+		done <- err
+	}()
+
+	// The other stage isn't paused.
+	out, err := running.Run(ctx, tsk)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, out.ConvertedData)
+
+	require.Eventually(t, func() bool {
+		return pausedProc.GetStatus().Value() == status.Paused.String()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A paused stage keeps reporting paused.
+	assert.Equal(t, status.Paused.String(), paused.GetStatus().Value())
+
+	paused.SetPause(false)
+
+	assert.Equal(t, status.Runnning, paused.GetPaused())
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stage did not resume")
+	}
+
+	assert.Equal(t, status.Done.String(), paused.GetStatus().Value())
+}
+
+// Edge case: conversions paused midway, by a processor pausing the run,
+// don't start, and if the run drains meanwhile, the stage is interrupted.
+func TestStage_Run_pausedConversion(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pc := shared.NewPauseController()
+
+	d := shared.NewDrainer()
+
+	ctx = shared.ContextWithDrainer(shared.ContextWithPause(ctx, pc), d)
+
+	pauser, err := processor.New("pause-conversion-proc", "pauses the run", func(ctx context.Context, in []int) ([]int, error) {
+		pc.Pause()
+
+		return in, nil
+	})
+	require.NoError(t, err)
+
+	converted := make(chan int, 1)
+
+	conv := converter.MustDefault(func(ctx context.Context, in int) (int, error) {
+		converted <- in
+
+		return in, nil
+	})
+
+	stg, err := New("pause-conversion", "pauses before converting", conv, pauser)
+	require.NoError(t, err)
+
+	tsk, err := task.New[int, int]([]int{1})
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := stg.Run(ctx, tsk)
+
+		done <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		return conv.GetStatus().Value() == status.Paused.String()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Empty(t, converted)
+
+	d.Drain()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, shared.ErrInterrupted)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stage did not drain")
+	}
+
+	assert.Empty(t, converted)
+	assert.Equal(t, status.Interrupted.String(), stg.GetStatus().Value())
+	assert.Equal(t, int64(1), stg.GetCounterInterrupted().Value())
+}
@@ -19,6 +19,25 @@ const (
 	Type = "task"
 )
 
+// DeadLetter records an item that failed conversion, and why. Stages only
+// produce dead letters when their error policy tolerates per-item failures.
+type DeadLetter[ProcessingData any] struct {
+	// Stage is the name of the stage where the item failed.
+	Stage string `json:"stage"`
+
+	// Index of the item in the stage's input.
+	Index int `json:"index"`
+
+	// Item that failed conversion.
+	Item ProcessingData `json:"item"`
+
+	// Err is the conversion error.
+	Err error `json:"-"`
+
+	// Error is the message of `Err`, kept for serialization.
+	Error string `json:"error"`
+}
+
 // Task encapsulates the work to be done plus some metadata.
 type Task[ProcessingData, ConvertedData any] struct {
 	// Logger of the job.
@@ -39,6 +58,10 @@ type Task[ProcessingData, ConvertedData any] struct {
 
 	// ConvertedData is the output of the task.
 	ConvertedData []ConvertedData `json:"out"`
+
+	// DeadLetters are the items dropped by stages tolerating per-item
+	// conversion failures, accumulated across stages.
+	DeadLetters []DeadLetter[ProcessingData] `json:"deadLetters,omitempty"`
 }
 
 //////
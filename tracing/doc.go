@@ -0,0 +1,18 @@
+// Package tracing provides the tracing backends of etler: Elastic APM, the
+// default, OpenTelemetry, and no-op.
+//
+// Every pipeline, stage, processor, converter and loader run is traced by
+// the tracer returned by `GetTracer`. Set it with `SetTracer`, e.g., to
+// trace to an OpenTelemetry collector:
+//
+//	tracing.SetTracer(tracing.NewOTel(provider))
+//
+// or through the `ETLER_TRACER` env var: "elastic", "otel" (using the global
+// provider, see `otel.SetTracerProvider`), or "noop".
+//
+// Spans carry the names of the components they run under, e.g.,
+// "etler.pipeline", "etler.stage", and "etler.processor", the task ID
+// ("etler.task.id"), and the items received, and returned
+// ("etler.items.in", "etler.items.out"). Logs are correlated with the trace
+// through the IDs returned by `Tracer.CorrelationFields`.
+package tracing
@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.elastic.co/apm"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// ElasticTracer traces to Elastic APM.
+type ElasticTracer struct {
+	tracer *apm.Tracer
+}
+
+// elasticSpan is an Elastic APM span.
+type elasticSpan struct {
+	span *apm.Span
+}
+
+//////
+// Methods.
+//////
+
+// End implements the `Span` interface.
+func (s *elasticSpan) End() {
+	s.span.End()
+}
+
+// SetAttributes implements the `Span` interface. Attributes are set as
+// labels.
+func (s *elasticSpan) SetAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		s.span.Context.SetLabel(attr.Key, attr.Value)
+	}
+}
+
+// Fail implements the `Span` interface.
+func (s *elasticSpan) Fail() {
+	s.span.Outcome = "failure"
+}
+
+// Start implements the `Tracer` interface. It uses the transaction in `ctx`,
+// otherwise it falls back to creating a new one, named, and typed after the
+// span, then it creates the span within the transaction.
+func (t *ElasticTracer) Start(ctx context.Context, name, spanType string) (context.Context, Span) {
+	tx := apm.TransactionFromContext(ctx)
+	if tx == nil {
+		tx = t.tracer.StartTransaction(name, spanType)
+
+		ctx = apm.ContextWithTransaction(ctx, tx)
+	}
+
+	span, ctx := apm.StartSpan(ctx, name, spanType)
+
+	return ctx, &elasticSpan{span: span}
+}
+
+// CaptureError implements the `Tracer` interface. Nested errors are
+// unwrapped, as `apm.CaptureError` doesn't.
+func (t *ElasticTracer) CaptureError(ctx context.Context, err error) {
+	if span := apm.SpanFromContext(ctx); span != nil {
+		span.Outcome = "failure"
+	}
+
+	for {
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			break
+		}
+
+		err = unwrapped
+	}
+
+	apm.CaptureError(ctx, err).Send()
+}
+
+// SpanFromContext implements the `Tracer` interface.
+func (t *ElasticTracer) SpanFromContext(ctx context.Context) Span {
+	span := apm.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+
+	return &elasticSpan{span: span}
+}
+
+// CorrelationFields implements the `Tracer` interface: "trace.id",
+// "transaction.id", and "span.id".
+func (t *ElasticTracer) CorrelationFields(ctx context.Context) map[string]string {
+	f := map[string]string{}
+
+	tx := apm.TransactionFromContext(ctx)
+	if tx == nil {
+		return f
+	}
+
+	traceContext := tx.TraceContext()
+
+	f["trace.id"] = traceContext.Trace.String()
+	f["transaction.id"] = traceContext.Span.String()
+
+	if span := apm.SpanFromContext(ctx); span != nil {
+		f["span.id"] = span.TraceContext().Span.String()
+	}
+
+	return f
+}
+
+//////
+// Factory.
+//////
+
+// NewElastic returns a tracer tracing to Elastic APM, through `tracer`, or
+// `apm.DefaultTracer` if nil. It's the default tracer.
+func NewElastic(tracer *apm.Tracer) *ElasticTracer {
+	// Enforces interface implementation.
+	var _ Tracer = (*ElasticTracer)(nil)
+
+	if tracer == nil {
+		tracer = apm.DefaultTracer
+	}
+
+	return &ElasticTracer{tracer: tracer}
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import "context"
+
+//////
+// Consts, vars and types.
+//////
+
+// NoopTracer doesn't trace.
+type NoopTracer struct{}
+
+// noopSpan is a span that's not recorded.
+type noopSpan struct{}
+
+//////
+// Methods.
+//////
+
+// End implements the `Span` interface.
+func (noopSpan) End() {}
+
+// SetAttributes implements the `Span` interface.
+func (noopSpan) SetAttributes(...Attribute) {}
+
+// Fail implements the `Span` interface.
+func (noopSpan) Fail() {}
+
+// Start implements the `Tracer` interface.
+func (NoopTracer) Start(ctx context.Context, _, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// CaptureError implements the `Tracer` interface.
+func (NoopTracer) CaptureError(context.Context, error) {}
+
+// SpanFromContext implements the `Tracer` interface.
+func (NoopTracer) SpanFromContext(context.Context) Span {
+	return nil
+}
+
+// CorrelationFields implements the `Tracer` interface.
+func (NoopTracer) CorrelationFields(context.Context) map[string]string {
+	return map[string]string{}
+}
+
+//////
+// Factory.
+//////
+
+// NewNoop returns a tracer that doesn't trace.
+func NewNoop() NoopTracer {
+	// Enforces interface implementation.
+	var _ Tracer = NoopTracer{}
+
+	return NoopTracer{}
+}
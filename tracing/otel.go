@@ -0,0 +1,138 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// InstrumentationName is the name of the OpenTelemetry tracer.
+const InstrumentationName = "github.com/thalesfsp/etler/v3"
+
+// OTelTracer traces to OpenTelemetry.
+type OTelTracer struct {
+	provider trace.TracerProvider
+}
+
+// otelSpan is an OpenTelemetry span.
+type otelSpan struct {
+	span trace.Span
+}
+
+//////
+// Helpers.
+//////
+
+// toKeyValue converts an attribute to an OpenTelemetry one.
+func toKeyValue(attr Attribute) attribute.KeyValue {
+	switch v := attr.Value.(type) {
+	case string:
+		return attribute.String(attr.Key, v)
+	case bool:
+		return attribute.Bool(attr.Key, v)
+	case int:
+		return attribute.Int(attr.Key, v)
+	case int64:
+		return attribute.Int64(attr.Key, v)
+	case float64:
+		return attribute.Float64(attr.Key, v)
+	default:
+		return attribute.String(attr.Key, fmt.Sprint(v))
+	}
+}
+
+//////
+// Methods.
+//////
+
+// End implements the `Span` interface.
+func (s *otelSpan) End() {
+	s.span.End()
+}
+
+// SetAttributes implements the `Span` interface.
+func (s *otelSpan) SetAttributes(attrs ...Attribute) {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+
+	for _, attr := range attrs {
+		kvs = append(kvs, toKeyValue(attr))
+	}
+
+	s.span.SetAttributes(kvs...)
+}
+
+// Fail implements the `Span` interface.
+func (s *otelSpan) Fail() {
+	s.span.SetStatus(codes.Error, "")
+}
+
+// Start implements the `Tracer` interface. The span type is set as the
+// "etler.type" attribute.
+func (t *OTelTracer) Start(ctx context.Context, name, spanType string) (context.Context, Span) {
+	ctx, span := t.provider.Tracer(InstrumentationName).Start(
+		ctx,
+		name,
+		trace.WithAttributes(attribute.String(KeyPrefix+"type", spanType)),
+	)
+
+	return ctx, &otelSpan{span: span}
+}
+
+// CaptureError implements the `Tracer` interface.
+func (t *OTelTracer) CaptureError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// SpanFromContext implements the `Tracer` interface.
+func (t *OTelTracer) SpanFromContext(ctx context.Context) Span {
+	span := trace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return nil
+	}
+
+	return &otelSpan{span: span}
+}
+
+// CorrelationFields implements the `Tracer` interface: "trace_id", and
+// "span_id".
+func (t *OTelTracer) CorrelationFields(ctx context.Context) map[string]string {
+	f := map[string]string{}
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return f
+	}
+
+	f["trace_id"] = sc.TraceID().String()
+	f["span_id"] = sc.SpanID().String()
+
+	return f
+}
+
+//////
+// Factory.
+//////
+
+// NewOTel returns a tracer tracing to OpenTelemetry, through `provider`, or
+// the global one, see `otel.SetTracerProvider`, if nil.
+func NewOTel(provider trace.TracerProvider) *OTelTracer {
+	// Enforces interface implementation.
+	var _ Tracer = (*OTelTracer)(nil)
+
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return &OTelTracer{provider: provider}
+}
@@ -0,0 +1,184 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+)
+
+//////
+// Consts, vars and types.
+//////
+
+// TracerEnvVar selects the default tracer, unless one is set through
+// `SetTracer`: "elastic" (default), "otel", or "noop".
+const TracerEnvVar = "ETLER_TRACER"
+
+// Tracers, see `TracerEnvVar`.
+const (
+	Elastic = "elastic"
+	OTel    = "otel"
+	Noop    = "noop"
+)
+
+// Attribute keys set by etler.
+const (
+	// KeyPrefix prefixes the keys of the component names, e.g.,
+	// "etler.pipeline", or "etler.processor".
+	KeyPrefix = "etler."
+
+	// KeyRunID is the ID of the pipeline run.
+	KeyRunID = "etler.run.id"
+
+	// KeyTaskID is the ID of the task being run.
+	KeyTaskID = "etler.task.id"
+
+	// KeyItemsIn is the count of items received by a component.
+	KeyItemsIn = "etler.items.in"
+
+	// KeyItemsOut is the count of items returned by a component.
+	KeyItemsOut = "etler.items.out"
+
+	// KeyAttempt is the attempt of a retried run.
+	KeyAttempt = "etler.attempt"
+
+	// KeyTimeout flags a timed out span.
+	KeyTimeout = "etler.timeout"
+)
+
+// Attribute is a key-value pair describing a span. Values should be strings,
+// numbers, or booleans.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Span is a traced operation. It's up to the caller to `End` it.
+type Span interface {
+	// End ends the span.
+	End()
+
+	// SetAttributes sets `attrs` on the span.
+	SetAttributes(attrs ...Attribute)
+
+	// Fail flags the span as failed.
+	Fail()
+}
+
+// Tracer is a tracing backend.
+type Tracer interface {
+	// Start starts a span named `name`, of type `spanType`, e.g.,
+	// "stage.clean.running", child of the span in `ctx`, if any. The returned
+	// context carries the span.
+	Start(ctx context.Context, name, spanType string) (context.Context, Span)
+
+	// CaptureError records `err` on the span in `ctx`, if any, and flags it
+	// as failed.
+	CaptureError(ctx context.Context, err error)
+
+	// SpanFromContext returns the span in `ctx`, or nil.
+	SpanFromContext(ctx context.Context) Span
+
+	// CorrelationFields returns the IDs correlating logs with the trace in
+	// `ctx`, e.g., "trace.id", if any.
+	CorrelationFields(ctx context.Context) map[string]string
+}
+
+// attributesCtxKey is the key of the inherited attributes in a context.
+type attributesCtxKey struct{}
+
+var (
+	tracerMu sync.RWMutex
+	tracer   Tracer
+)
+
+//////
+// Helpers.
+//////
+
+// fromEnv returns the tracer selected through `TracerEnvVar`.
+func fromEnv() Tracer {
+	switch strings.ToLower(os.Getenv(TracerEnvVar)) {
+	case OTel:
+		return NewOTel(nil)
+	case Noop:
+		return NewNoop()
+	default:
+		return NewElastic(nil)
+	}
+}
+
+//////
+// Exported functions.
+//////
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Component returns the attribute naming a component of type `t`, e.g.,
+// "etler.stage": "clean".
+func Component(t, name string) Attribute {
+	return String(KeyPrefix+t, name)
+}
+
+// ContextWithAttributes returns a copy of `ctx` carrying `attrs`, on top of
+// those already carried. They're set on the spans started by etler under it,
+// e.g., a processor's span carries the names of its pipeline, and stage.
+func ContextWithAttributes(ctx context.Context, attrs ...Attribute) context.Context {
+	inherited := AttributesFromContext(ctx)
+
+	merged := make([]Attribute, 0, len(inherited)+len(attrs))
+	merged = append(merged, inherited...)
+	merged = append(merged, attrs...)
+
+	return context.WithValue(ctx, attributesCtxKey{}, merged)
+}
+
+// AttributesFromContext returns the attributes carried by `ctx`, if any.
+func AttributesFromContext(ctx context.Context) []Attribute {
+	attrs, _ := ctx.Value(attributesCtxKey{}).([]Attribute)
+
+	return attrs
+}
+
+// SetTracer sets the tracer used by etler. A nil `t` restores the one
+// selected through `TracerEnvVar`.
+func SetTracer(t Tracer) {
+	tracerMu.Lock()
+	defer tracerMu.Unlock()
+
+	tracer = t
+}
+
+// GetTracer returns the tracer used by etler.
+func GetTracer() Tracer {
+	tracerMu.RLock()
+	t := tracer
+	tracerMu.RUnlock()
+
+	if t != nil {
+		return t
+	}
+
+	tracerMu.Lock()
+	defer tracerMu.Unlock()
+
+	if tracer == nil {
+		tracer = fromEnv()
+	}
+
+	return tracer
+}
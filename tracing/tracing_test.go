@@ -0,0 +1,221 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.elastic.co/apm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordedSpan is an OpenTelemetry span recording what's set on it.
+type recordedSpan struct {
+	trace.Span
+
+	mu         sync.Mutex
+	name       string
+	sc         trace.SpanContext
+	attributes map[attribute.Key]attribute.Value
+	code       codes.Code
+	errs       []error
+	ended      bool
+}
+
+func (s *recordedSpan) SpanContext() trace.SpanContext { return s.sc }
+
+func (s *recordedSpan) End(...trace.SpanEndOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ended = true
+}
+
+func (s *recordedSpan) SetAttributes(kvs ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, kv := range kvs {
+		s.attributes[kv.Key] = kv.Value
+	}
+}
+
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.code = code
+}
+
+func (s *recordedSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errs = append(s.errs, err)
+}
+
+// recorder is an OpenTelemetry tracer recording spans.
+type recorder struct {
+	noop.Tracer
+
+	spans []*recordedSpan
+}
+
+// recorderProvider provides the recorder.
+type recorderProvider struct {
+	noop.TracerProvider
+
+	r *recorder
+}
+
+func (p *recorderProvider) Tracer(string, ...trace.TracerOption) trace.Tracer { return p.r }
+
+func (r *recorder) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	id := byte(len(r.spans) + 1)
+
+	span := &recordedSpan{
+		Span: noop.Span{},
+		name: name,
+		sc: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1},
+			SpanID:  trace.SpanID{id},
+		}),
+		attributes: map[attribute.Key]attribute.Value{},
+	}
+
+	cfg := trace.NewSpanStartConfig(opts...)
+
+	span.SetAttributes(cfg.Attributes()...)
+
+	r.spans = append(r.spans, span)
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func TestGetTracer(t *testing.T) {
+	defer SetTracer(nil)
+
+	// Happy path: Elastic by default.
+	SetTracer(nil)
+	t.Setenv(TracerEnvVar, "")
+	assert.IsType(t, &ElasticTracer{}, GetTracer())
+
+	// Happy path: selected through the env var.
+	SetTracer(nil)
+	t.Setenv(TracerEnvVar, "OTel")
+	assert.IsType(t, &OTelTracer{}, GetTracer())
+
+	SetTracer(nil)
+	t.Setenv(TracerEnvVar, Noop)
+	assert.IsType(t, NoopTracer{}, GetTracer())
+
+	// Happy path: set explicitly, taking precedence over the env var.
+	SetTracer(NewOTel(nil))
+	assert.IsType(t, &OTelTracer{}, GetTracer())
+}
+
+func TestContextWithAttributes(t *testing.T) {
+	// Edge case: none.
+	assert.Empty(t, AttributesFromContext(context.Background()))
+
+	// Happy path: inherited, without leaking to the parent.
+	parent := ContextWithAttributes(context.Background(), Component("pipeline", "p"))
+	child := ContextWithAttributes(parent, Component("stage", "s"), String(KeyTaskID, "t"))
+
+	assert.Equal(t, []Attribute{{Key: "etler.pipeline", Value: "p"}}, AttributesFromContext(parent))
+	assert.Equal(t, []Attribute{
+		{Key: "etler.pipeline", Value: "p"},
+		{Key: "etler.stage", Value: "s"},
+		{Key: KeyTaskID, Value: "t"},
+	}, AttributesFromContext(child))
+}
+
+func TestOTelTracer(t *testing.T) {
+	r := &recorder{}
+	tracer := NewOTel(&recorderProvider{r: r})
+
+	// Edge case: no span.
+	assert.Nil(t, tracer.SpanFromContext(context.Background()))
+	assert.Empty(t, tracer.CorrelationFields(context.Background()))
+
+	// Happy path.
+	ctx, span := tracer.Start(context.Background(), "clean.running", "stage.clean.running")
+
+	span.SetAttributes(Component("stage", "clean"), Int(KeyItemsIn, 2), Bool(KeyTimeout, false), Attribute{Key: "other", Value: 1.5})
+
+	tracer.CaptureError(ctx, errors.New("failed"))
+
+	require.NotNil(t, tracer.SpanFromContext(ctx))
+
+	span.End()
+
+	require.Len(t, r.spans, 1)
+
+	recorded := r.spans[0]
+
+	assert.Equal(t, "clean.running", recorded.name)
+	assert.Equal(t, "stage.clean.running", recorded.attributes["etler.type"].AsString())
+	assert.Equal(t, "clean", recorded.attributes["etler.stage"].AsString())
+	assert.Equal(t, int64(2), recorded.attributes[KeyItemsIn].AsInt64())
+	assert.False(t, recorded.attributes[KeyTimeout].AsBool())
+	assert.Equal(t, 1.5, recorded.attributes["other"].AsFloat64())
+	assert.Equal(t, codes.Error, recorded.code)
+	assert.Len(t, recorded.errs, 1)
+	assert.True(t, recorded.ended)
+
+	assert.Equal(t, map[string]string{
+		"trace_id": recorded.sc.TraceID().String(),
+		"span_id":  recorded.sc.SpanID().String(),
+	}, tracer.CorrelationFields(ctx))
+}
+
+func TestElasticTracer(t *testing.T) {
+	tracer := NewElastic(nil)
+
+	// Edge case: no span.
+	assert.Nil(t, tracer.SpanFromContext(context.Background()))
+	assert.Empty(t, tracer.CorrelationFields(context.Background()))
+
+	// Happy path: a transaction is created, then reused.
+	ctx, span := tracer.Start(context.Background(), "clean.running", "stage.clean.running")
+
+	tx := apm.TransactionFromContext(ctx)
+	require.NotNil(t, tx)
+
+	defer tx.End()
+
+	childCtx, child := tracer.Start(ctx, "upper.running", "processor.upper.running")
+	assert.Same(t, tx, apm.TransactionFromContext(childCtx))
+
+	child.SetAttributes(Component("processor", "upper"), Int(KeyItemsIn, 2))
+
+	tracer.CaptureError(childCtx, errors.New("failed"))
+
+	assert.Equal(t, "failure", apm.SpanFromContext(childCtx).Outcome)
+	assert.Contains(t, tracer.CorrelationFields(childCtx), "span.id")
+
+	child.End()
+	span.End()
+}
+
+func TestNoopTracer(t *testing.T) {
+	tracer := NewNoop()
+
+	ctx, span := tracer.Start(context.Background(), "clean.running", "stage.clean.running")
+
+	assert.NotPanics(t, func() {
+		span.SetAttributes(Int(KeyItemsIn, 1))
+		span.Fail()
+		tracer.CaptureError(ctx, errors.New("failed"))
+		span.End()
+	})
+
+	assert.Nil(t, tracer.SpanFromContext(ctx))
+	assert.Empty(t, tracer.CorrelationFields(ctx))
+}